@@ -0,0 +1,182 @@
+package config
+
+import (
+	"time"
+
+	"github.com/afteralec/grpc-user/db"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	ListenAddress   string        `mapstructure:"listen_address" validate:"required,hostname_port"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" validate:"gt=0"`
+	SecretsDir      string        `mapstructure:"secrets_dir"`
+	RootUsername    string        `mapstructure:"root_username" validate:"required"`
+	RootPassphrase  string        `mapstructure:"root_passphrase"`
+	DB              DB            `mapstructure:"db"`
+	GRPC            GRPC          `mapstructure:"grpc"`
+	Gateway         Gateway       `mapstructure:"gateway"`
+	Log             Log           `mapstructure:"log"`
+	Metrics         Metrics       `mapstructure:"metrics"`
+	Tracing         Tracing       `mapstructure:"tracing"`
+	Deletion        Deletion      `mapstructure:"deletion"`
+	Username        Username      `mapstructure:"username"`
+	Settings        Settings      `mapstructure:"settings"`
+	Permissions     Permissions   `mapstructure:"permissions"`
+}
+
+type DB struct {
+	Path        string `mapstructure:"path" validate:"required"`
+	JournalMode string `mapstructure:"journal_mode" validate:"oneof=DELETE TRUNCATE PERSIST MEMORY WAL OFF"`
+	Synchronous string `mapstructure:"synchronous" validate:"oneof=off normal full extra"`
+	TempStore   string `mapstructure:"temp_store" validate:"oneof=default file memory"`
+	MmapSize    int64  `mapstructure:"mmap_size" validate:"gte=0"`
+	PageSize    int64  `mapstructure:"page_size" validate:"oneof=512 1024 2048 4096 8192 16384 32768 65536"`
+	ForeignKeys bool   `mapstructure:"foreign_keys"`
+}
+
+func (d DB) Pragmas() db.Pragmas {
+	return db.Pragmas{
+		JournalMode: d.JournalMode,
+		Synchronous: d.Synchronous,
+		TempStore:   d.TempStore,
+		MmapSize:    d.MmapSize,
+		PageSize:    d.PageSize,
+		ForeignKeys: d.ForeignKeys,
+	}
+}
+
+type GRPC struct {
+	MaxRecvMsgSize int       `mapstructure:"max_recv_msg_size" validate:"gt=0"`
+	MaxSendMsgSize int       `mapstructure:"max_send_msg_size" validate:"gt=0"`
+	Reflection     bool      `mapstructure:"reflection"`
+	Connect        bool      `mapstructure:"connect"`
+	Keepalive      Keepalive `mapstructure:"keepalive"`
+}
+
+// Keepalive configures how the server checks that idle clients are still there. When Connect is
+// enabled, connections are served through net/http, whose HTTP/2 server doesn't send pings: Time
+// becomes the TCP keepalive period instead, and the rest only apply to native gRPC serving.
+type Keepalive struct {
+	Time                time.Duration `mapstructure:"time" validate:"gt=0"`
+	Timeout             time.Duration `mapstructure:"timeout" validate:"gt=0"`
+	MinTime             time.Duration `mapstructure:"min_time" validate:"gte=0"`
+	PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+}
+
+type Gateway struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	ListenAddress string `mapstructure:"listen_address" validate:"required_if=Enabled true,omitempty,hostname_port"`
+}
+
+type Metrics struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	ListenAddress string `mapstructure:"listen_address" validate:"required_if=Enabled true,omitempty,hostname_port"`
+}
+
+// Tracing configures the OTLP trace exporter. Spans are only exported when Exporter is otlp.
+type Tracing struct {
+	Exporter    string  `mapstructure:"exporter" validate:"oneof=none otlp"`
+	Endpoint    string  `mapstructure:"endpoint" validate:"required_if=Exporter otlp,omitempty,hostname_port"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio" validate:"gte=0,lte=1"`
+}
+
+// Deletion configures how long deleted accounts can still be restored, and how often accounts
+// past their grace period are checked for and erased.
+type Deletion struct {
+	GracePeriod   time.Duration `mapstructure:"grace_period" validate:"gte=0"`
+	SweepInterval time.Duration `mapstructure:"sweep_interval" validate:"gt=0"`
+}
+
+// Username configures how often users can change their username, and how long an old username
+// stays reserved for its previous owner before anyone else can claim it. ReservedFile and
+// BlocklistFile list, one per line, names nobody can take and words no username can contain;
+// more can be added at runtime. Without a ReservedFile, a short built-in list is reserved.
+// Unicode allows letters and digits from any script, rather than only lowercase ASCII. Usernames that
+// look alike are refused either way, so turning Unicode on later can't let a new username pass for an old one.
+type Username struct {
+	Unicode           bool          `mapstructure:"unicode"`
+	ChangeCooldown    time.Duration `mapstructure:"change_cooldown" validate:"gte=0"`
+	ReservationPeriod time.Duration `mapstructure:"reservation_period" validate:"gte=0"`
+	ReservedFile      string        `mapstructure:"reserved_file" validate:"omitempty,file"`
+	BlocklistFile     string        `mapstructure:"blocklist_file" validate:"omitempty,file"`
+}
+
+// Settings configures how often WatchUserSettings streams send a heartbeat while nothing changes.
+type Settings struct {
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval" validate:"gt=0"`
+}
+
+// Permissions configures how often grants past their expiry are checked for and revoked.
+type Permissions struct {
+	ExpirySweepInterval time.Duration `mapstructure:"expiry_sweep_interval" validate:"gt=0"`
+}
+
+type Log struct {
+	Format string `mapstructure:"format" validate:"oneof=json text"`
+	Level  string `mapstructure:"level" validate:"oneof=debug info warn error"`
+}
+
+func New(v *viper.Viper) (Config, error) {
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return Config{}, err
+	}
+	if err := Validate(config); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("listen_address", ":8009")
+	v.SetDefault("shutdown_timeout", 30*time.Second)
+	v.SetDefault("secrets_dir", "/run/secrets")
+	v.SetDefault("root_username", "")
+	v.SetDefault("root_passphrase", "")
+
+	v.SetDefault("db.path", "/var/db/user.db")
+	v.SetDefault("db.journal_mode", db.DefaultPragmas.JournalMode)
+	v.SetDefault("db.synchronous", db.DefaultPragmas.Synchronous)
+	v.SetDefault("db.temp_store", db.DefaultPragmas.TempStore)
+	v.SetDefault("db.mmap_size", db.DefaultPragmas.MmapSize)
+	v.SetDefault("db.page_size", db.DefaultPragmas.PageSize)
+	v.SetDefault("db.foreign_keys", db.DefaultPragmas.ForeignKeys)
+
+	v.SetDefault("grpc.max_recv_msg_size", 4*1024*1024)
+	v.SetDefault("grpc.max_send_msg_size", 4*1024*1024)
+	v.SetDefault("grpc.reflection", false)
+	v.SetDefault("grpc.connect", true)
+	v.SetDefault("grpc.keepalive.time", 2*time.Hour)
+	v.SetDefault("grpc.keepalive.timeout", 20*time.Second)
+	v.SetDefault("grpc.keepalive.min_time", 5*time.Minute)
+	v.SetDefault("grpc.keepalive.permit_without_stream", false)
+
+	v.SetDefault("gateway.enabled", true)
+	v.SetDefault("gateway.listen_address", ":8010")
+
+	v.SetDefault("log.format", "json")
+	v.SetDefault("log.level", "info")
+
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.listen_address", ":8011")
+
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.endpoint", "localhost:4317")
+	v.SetDefault("tracing.insecure", false)
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	v.SetDefault("deletion.grace_period", 30*24*time.Hour)
+	v.SetDefault("deletion.sweep_interval", time.Hour)
+
+	v.SetDefault("username.unicode", false)
+	v.SetDefault("username.change_cooldown", 30*24*time.Hour)
+	v.SetDefault("username.reservation_period", 90*24*time.Hour)
+	v.SetDefault("username.reserved_file", "")
+	v.SetDefault("username.blocklist_file", "")
+
+	v.SetDefault("settings.heartbeat_interval", 30*time.Second)
+
+	v.SetDefault("permissions.expiry_sweep_interval", time.Minute)
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	v, err := Load([]string{"--secrets-dir", t.TempDir()})
+	require.NoError(t, err)
+	v.Set("root_username", "tested")
+
+	config, err := New(v)
+	require.NoError(t, err)
+	require.Equal(t, ":8009", config.ListenAddress)
+	require.Equal(t, "/var/db/user.db", config.DB.Path)
+	require.Equal(t, 30*time.Second, config.ShutdownTimeout)
+	require.True(t, config.DB.ForeignKeys)
+	require.Equal(t, 30*24*time.Hour, config.Deletion.GracePeriod)
+	require.Equal(t, 30*24*time.Hour, config.Username.ChangeCooldown)
+	require.Equal(t, 30*time.Second, config.Settings.HeartbeatInterval)
+	require.Equal(t, time.Minute, config.Permissions.ExpirySweepInterval)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "user.toml")
+	err := os.WriteFile(path, []byte("listen_address = \":9000\"\n\n[db]\npath = \"/tmp/file.db\"\n"), 0o600)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "root_username.toml"), []byte("root_username = \"tested\"\n"), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("USER_DB_PATH", "/tmp/env.db")
+	v, err := Load([]string{"--config", path, "--secrets-dir", dir, "--shutdown-timeout", "5s"})
+	require.NoError(t, err)
+
+	config, err := New(v)
+	require.NoError(t, err)
+	require.Equal(t, ":9000", config.ListenAddress)
+	require.Equal(t, "/tmp/env.db", config.DB.Path)
+	require.Equal(t, 5*time.Second, config.ShutdownTimeout)
+	require.Equal(t, "tested", config.RootUsername)
+}
+
+func TestNewReportsEveryInvalidField(t *testing.T) {
+	v, err := Load([]string{"--secrets-dir", t.TempDir(), "--listen-address", "nope"})
+	require.NoError(t, err)
+	v.Set("db.journal_mode", "SIDEWAYS")
+
+	_, err = New(v)
+	require.Error(t, err)
+
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	keys := []string{}
+	for _, field := range verr.Fields {
+		keys = append(keys, field.Key)
+	}
+	require.ElementsMatch(t, []string{"listen_address", "root_username", "db.journal_mode"}, keys)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const EnvPrefix = "USER"
+
+var secrets = []string{"root_username", "root_passphrase"}
+
+// Load builds the configuration from, in increasing order of precedence,
+// defaults, the config file, secrets, environment variables and flags.
+func Load(args []string) (*viper.Viper, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	flags := newFlagSet()
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := bindFlags(v, flags); err != nil {
+		return nil, err
+	}
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	v.SetConfigType("toml")
+	if path := v.GetString("config"); path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+	} else {
+		v.SetConfigName("user")
+		v.AddConfigPath("/etc/user")
+		v.AddConfigPath(".")
+		if err := v.ReadInConfig(); err != nil {
+			var notFound viper.ConfigFileNotFoundError
+			if !errors.As(err, &notFound) {
+				return nil, err
+			}
+		}
+	}
+
+	for _, name := range secrets {
+		v.SetConfigFile(filepath.Join(v.GetString("secrets_dir"), name+".toml"))
+		if err := v.MergeInConfig(); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func newFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("user", pflag.ContinueOnError)
+	flags.String("config", "", "path to a TOML config file")
+	flags.String("listen-address", "", "address for the gRPC server to listen on")
+	flags.Duration("shutdown-timeout", 0, "time to wait for in-flight requests before forcing shutdown")
+	flags.String("secrets-dir", "", "directory containing the root_username and root_passphrase secrets")
+	flags.String("db-path", "", "path to the SQLite database")
+	flags.Int("grpc-max-recv-msg-size", 0, "maximum size in bytes of a received message")
+	flags.Int("grpc-max-send-msg-size", 0, "maximum size in bytes of a sent message")
+	flags.Bool("grpc-reflection", false, "register the gRPC server reflection service")
+	flags.Bool("grpc-connect", false, "serve the Connect and gRPC-Web protocols alongside gRPC")
+	flags.Bool("gateway-enabled", false, "serve the REST/JSON gateway")
+	flags.String("gateway-listen-address", "", "address for the REST/JSON gateway to listen on")
+	flags.String("log-format", "", "log output format, json or text")
+	flags.String("log-level", "", "minimum log level, one of debug, info, warn or error")
+	flags.Bool("metrics-enabled", false, "serve Prometheus metrics")
+	flags.String("metrics-listen-address", "", "address for the Prometheus metrics endpoint to listen on")
+	flags.String("tracing-exporter", "", "trace exporter, none or otlp")
+	flags.String("tracing-endpoint", "", "host:port of the OTLP gRPC collector")
+	flags.Bool("tracing-insecure", false, "connect to the OTLP collector without TLS")
+	flags.Float64("tracing-sample-ratio", 0, "fraction of new traces to sample, from 0 to 1")
+	flags.Duration("deletion-grace-period", 0, "time a deleted account can be restored before it's erased")
+	flags.Duration("deletion-sweep-interval", 0, "how often to erase accounts past their deletion grace period")
+	flags.Bool("username-unicode", false, "allow letters and digits from any script in usernames")
+	flags.Duration("username-change-cooldown", 0, "time users must wait between changing their username")
+	flags.Duration("username-reservation-period", 0, "time an old username stays reserved for its previous owner")
+	flags.String("username-reserved-file", "", "file listing usernames nobody can take, one per line")
+	flags.String("username-blocklist-file", "", "file listing words no username can contain, one per line")
+	flags.Duration("settings-heartbeat-interval", 0, "how often to send a heartbeat on settings watch streams")
+	flags.Duration("permissions-expiry-sweep-interval", 0, "how often to revoke permission grants past their expiry")
+	return flags
+}
+
+func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
+	bindings := map[string]string{
+		"config":                            "config",
+		"listen_address":                    "listen-address",
+		"shutdown_timeout":                  "shutdown-timeout",
+		"secrets_dir":                       "secrets-dir",
+		"db.path":                           "db-path",
+		"grpc.max_recv_msg_size":            "grpc-max-recv-msg-size",
+		"grpc.max_send_msg_size":            "grpc-max-send-msg-size",
+		"grpc.reflection":                   "grpc-reflection",
+		"grpc.connect":                      "grpc-connect",
+		"gateway.enabled":                   "gateway-enabled",
+		"gateway.listen_address":            "gateway-listen-address",
+		"log.format":                        "log-format",
+		"log.level":                         "log-level",
+		"metrics.enabled":                   "metrics-enabled",
+		"metrics.listen_address":            "metrics-listen-address",
+		"tracing.exporter":                  "tracing-exporter",
+		"tracing.endpoint":                  "tracing-endpoint",
+		"tracing.insecure":                  "tracing-insecure",
+		"tracing.sample_ratio":              "tracing-sample-ratio",
+		"deletion.grace_period":             "deletion-grace-period",
+		"deletion.sweep_interval":           "deletion-sweep-interval",
+		"username.unicode":                  "username-unicode",
+		"username.change_cooldown":          "username-change-cooldown",
+		"username.reservation_period":       "username-reservation-period",
+		"username.reserved_file":            "username-reserved-file",
+		"username.blocklist_file":           "username-blocklist-file",
+		"settings.heartbeat_interval":       "settings-heartbeat-interval",
+		"permissions.expiry_sweep_interval": "permissions-expiry-sweep-interval",
+	}
+	for key, name := range bindings {
+		if err := v.BindPFlag(key, flags.Lookup(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate *validator.Validate
+
+func init() {
+	validate = validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("mapstructure")
+	})
+}
+
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, field := range e.Fields {
+		fmt.Fprintf(&b, "\n  %s: %s", field.Key, field.Message)
+	}
+	return b.String()
+}
+
+func Validate(config Config) error {
+	err := validate.Struct(config)
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	fields := []FieldError{}
+	for _, fe := range errs {
+		fields = append(fields, FieldError{
+			Key:     strings.TrimPrefix(fe.Namespace(), "Config."),
+			Message: message(fe),
+		})
+	}
+	return &ValidationError{Fields: fields}
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "hostname_port":
+		return fmt.Sprintf("must be a host:port address, got %q", fe.Value())
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], got %v", fe.Param(), fe.Value())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s, got %v", fe.Param(), fe.Value())
+	case "gte":
+		return fmt.Sprintf("must be at least %s, got %v", fe.Param(), fe.Value())
+	case "lte":
+		return fmt.Sprintf("must be at most %s, got %v", fe.Param(), fe.Value())
+	default:
+		return fmt.Sprintf("failed the %q check, got %v", fe.Tag(), fe.Value())
+	}
+}
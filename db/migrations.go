@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+type MissingMigrationsError struct {
+	Versions []int64
+}
+
+func (e *MissingMigrationsError) Error() string {
+	versions := []string{}
+	for _, version := range e.Versions {
+		versions = append(versions, strconv.FormatInt(version, 10))
+	}
+	return fmt.Sprintf("migrations have not been applied: %s", strings.Join(versions, ", "))
+}
+
+// VerifyMigrations checks that every migration in fsys has been applied successfully by sqlx.
+func VerifyMigrations(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	entries, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM _sqlx_migrations WHERE success = true;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	applied := map[int64]bool{}
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	missing := []int64{}
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return fmt.Errorf("migration %s does not start with a version: %w", entry, err)
+		}
+		if !applied[version] {
+			missing = append(missing, version)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingMigrationsError{Versions: missing}
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 
+	"github.com/XSAM/otelsql"
 	"github.com/afteralec/grpc-user/db/query"
 )
 
@@ -11,33 +13,66 @@ type OpenOutput struct {
 	Queries *query.Queries
 }
 
-func Open(url string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", url)
+type Pragmas struct {
+	JournalMode string
+	Synchronous string
+	TempStore   string
+	MmapSize    int64
+	PageSize    int64
+	ForeignKeys bool
+}
+
+var DefaultPragmas Pragmas = Pragmas{
+	JournalMode: "WAL",
+	Synchronous: "normal",
+	TempStore:   "memory",
+	MmapSize:    30000000000,
+	PageSize:    32768,
+	ForeignKeys: true,
+}
+
+func WithPragmas(pragmas Pragmas) func(p *Pragmas) {
+	return func(p *Pragmas) {
+		*p = pragmas
+	}
+}
+
+func Open(url string, opts ...func(p *Pragmas)) (*sql.DB, error) {
+	pragmas := DefaultPragmas
+	for _, opt := range opts {
+		opt(&pragmas)
+	}
+
+	db, err := otelsql.Open("sqlite3", url, traceOptions()...)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = db.Exec("PRAGMA JOURNAL_MODE = WAL;")
+	_, err = db.Exec(fmt.Sprintf("PRAGMA JOURNAL_MODE = %s;", pragmas.JournalMode))
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Exec("PRAGMA SYNCHRONOUS = normal;")
+	_, err = db.Exec(fmt.Sprintf("PRAGMA SYNCHRONOUS = %s;", pragmas.Synchronous))
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Exec("PRAGMA TEMP_STORE = memory;")
+	_, err = db.Exec(fmt.Sprintf("PRAGMA TEMP_STORE = %s;", pragmas.TempStore))
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Exec("PRAGMA MMAP_SIZE = 30000000000;")
+	_, err = db.Exec(fmt.Sprintf("PRAGMA MMAP_SIZE = %d;", pragmas.MmapSize))
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Exec("PRAGMA PAGE_SIZE = 32768;")
+	_, err = db.Exec(fmt.Sprintf("PRAGMA PAGE_SIZE = %d;", pragmas.PageSize))
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Exec("PRAGMA FOREIGN_KEYS = ON;")
+	foreignKeys := "OFF"
+	if pragmas.ForeignKeys {
+		foreignKeys = "ON"
+	}
+	_, err = db.Exec(fmt.Sprintf("PRAGMA FOREIGN_KEYS = %s;", foreignKeys))
 	if err != nil {
 		return nil, err
 	}
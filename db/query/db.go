@@ -24,15 +24,93 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.anonymizeGroupMemberChangesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeGroupMemberChangesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeGroupMemberChangesByIUID: %w", err)
+	}
+	if q.anonymizeGroupMemberChangesByUIDStmt, err = db.PrepareContext(ctx, anonymizeGroupMemberChangesByUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeGroupMemberChangesByUID: %w", err)
+	}
+	if q.anonymizeGroupMembersByIUIDStmt, err = db.PrepareContext(ctx, anonymizeGroupMembersByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeGroupMembersByIUID: %w", err)
+	}
+	if q.anonymizeGroupsByIUIDStmt, err = db.PrepareContext(ctx, anonymizeGroupsByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeGroupsByIUID: %w", err)
+	}
+	if q.anonymizeRolesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeRolesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeRolesByIUID: %w", err)
+	}
+	if q.anonymizeThemesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeThemesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeThemesByIUID: %w", err)
+	}
+	if q.anonymizeUserDeletionsByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUserDeletionsByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserDeletionsByIUID: %w", err)
+	}
+	if q.anonymizeUserPermissionGrantsByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUserPermissionGrantsByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserPermissionGrantsByIUID: %w", err)
+	}
+	if q.anonymizeUserPermissionGrantsByUIDStmt, err = db.PrepareContext(ctx, anonymizeUserPermissionGrantsByUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserPermissionGrantsByUID: %w", err)
+	}
+	if q.anonymizeUserPermissionRevocationsByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUserPermissionRevocationsByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserPermissionRevocationsByIUID: %w", err)
+	}
+	if q.anonymizeUserPermissionRevocationsByUIDStmt, err = db.PrepareContext(ctx, anonymizeUserPermissionRevocationsByUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserPermissionRevocationsByUID: %w", err)
+	}
+	if q.anonymizeUserRoleChangesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUserRoleChangesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserRoleChangesByIUID: %w", err)
+	}
+	if q.anonymizeUserRoleChangesByUIDStmt, err = db.PrepareContext(ctx, anonymizeUserRoleChangesByUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserRoleChangesByUID: %w", err)
+	}
+	if q.anonymizeUserRolesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUserRolesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserRolesByIUID: %w", err)
+	}
+	if q.anonymizeUserStatusChangesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUserStatusChangesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserStatusChangesByIUID: %w", err)
+	}
+	if q.anonymizeUserStatusChangesByUIDStmt, err = db.PrepareContext(ctx, anonymizeUserStatusChangesByUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUserStatusChangesByUID: %w", err)
+	}
+	if q.anonymizeUsernameHistoryByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUsernameHistoryByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUsernameHistoryByIUID: %w", err)
+	}
+	if q.anonymizeUsernameRulesByIUIDStmt, err = db.PrepareContext(ctx, anonymizeUsernameRulesByIUID); err != nil {
+		return nil, fmt.Errorf("error preparing query AnonymizeUsernameRulesByIUID: %w", err)
+	}
 	if q.countEmailsStmt, err = db.PrepareContext(ctx, countEmails); err != nil {
 		return nil, fmt.Errorf("error preparing query CountEmails: %w", err)
 	}
 	if q.createEmailStmt, err = db.PrepareContext(ctx, createEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateEmail: %w", err)
 	}
+	if q.createGroupStmt, err = db.PrepareContext(ctx, createGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateGroup: %w", err)
+	}
+	if q.createGroupMemberStmt, err = db.PrepareContext(ctx, createGroupMember); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateGroupMember: %w", err)
+	}
+	if q.createGroupMemberChangeStmt, err = db.PrepareContext(ctx, createGroupMemberChange); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateGroupMemberChange: %w", err)
+	}
+	if q.createGroupPermissionStmt, err = db.PrepareContext(ctx, createGroupPermission); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateGroupPermission: %w", err)
+	}
+	if q.createRoleStmt, err = db.PrepareContext(ctx, createRole); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRole: %w", err)
+	}
+	if q.createRolePermissionStmt, err = db.PrepareContext(ctx, createRolePermission); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRolePermission: %w", err)
+	}
+	if q.createThemeStmt, err = db.PrepareContext(ctx, createTheme); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTheme: %w", err)
+	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createUserDeletionStmt, err = db.PrepareContext(ctx, createUserDeletion); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserDeletion: %w", err)
+	}
 	if q.createUserPermissionStmt, err = db.PrepareContext(ctx, createUserPermission); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUserPermission: %w", err)
 	}
@@ -42,24 +120,96 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createUserPermissionRevocationStmt, err = db.PrepareContext(ctx, createUserPermissionRevocation); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUserPermissionRevocation: %w", err)
 	}
-	if q.createUserSettingsStmt, err = db.PrepareContext(ctx, createUserSettings); err != nil {
-		return nil, fmt.Errorf("error preparing query CreateUserSettings: %w", err)
+	if q.createUserRoleStmt, err = db.PrepareContext(ctx, createUserRole); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserRole: %w", err)
+	}
+	if q.createUserRoleChangeStmt, err = db.PrepareContext(ctx, createUserRoleChange); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserRoleChange: %w", err)
+	}
+	if q.createUserStatusChangeStmt, err = db.PrepareContext(ctx, createUserStatusChange); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserStatusChange: %w", err)
+	}
+	if q.createUsernameHistoryStmt, err = db.PrepareContext(ctx, createUsernameHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUsernameHistory: %w", err)
+	}
+	if q.createUsernameRuleStmt, err = db.PrepareContext(ctx, createUsernameRule); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUsernameRule: %w", err)
 	}
 	if q.deleteEmailStmt, err = db.PrepareContext(ctx, deleteEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteEmail: %w", err)
 	}
+	if q.deleteGroupStmt, err = db.PrepareContext(ctx, deleteGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteGroup: %w", err)
+	}
+	if q.deleteGroupMemberStmt, err = db.PrepareContext(ctx, deleteGroupMember); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteGroupMember: %w", err)
+	}
+	if q.deleteGroupPermissionsStmt, err = db.PrepareContext(ctx, deleteGroupPermissions); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteGroupPermissions: %w", err)
+	}
+	if q.deleteRoleStmt, err = db.PrepareContext(ctx, deleteRole); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRole: %w", err)
+	}
+	if q.deleteRolePermissionsStmt, err = db.PrepareContext(ctx, deleteRolePermissions); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRolePermissions: %w", err)
+	}
+	if q.deleteThemeStmt, err = db.PrepareContext(ctx, deleteTheme); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTheme: %w", err)
+	}
+	if q.deleteUserStmt, err = db.PrepareContext(ctx, deleteUser); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUser: %w", err)
+	}
+	if q.deleteUserDeletionStmt, err = db.PrepareContext(ctx, deleteUserDeletion); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserDeletion: %w", err)
+	}
 	if q.deleteUserPermissionStmt, err = db.PrepareContext(ctx, deleteUserPermission); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUserPermission: %w", err)
 	}
 	if q.deleteUserPermissionsByNameStmt, err = db.PrepareContext(ctx, deleteUserPermissionsByName); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUserPermissionsByName: %w", err)
 	}
+	if q.deleteUserRoleStmt, err = db.PrepareContext(ctx, deleteUserRole); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserRole: %w", err)
+	}
+	if q.deleteUserSettingValueStmt, err = db.PrepareContext(ctx, deleteUserSettingValue); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserSettingValue: %w", err)
+	}
+	if q.deleteUserStatusStmt, err = db.PrepareContext(ctx, deleteUserStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserStatus: %w", err)
+	}
+	if q.deleteUsernameRuleStmt, err = db.PrepareContext(ctx, deleteUsernameRule); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUsernameRule: %w", err)
+	}
 	if q.getEmailStmt, err = db.PrepareContext(ctx, getEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEmail: %w", err)
 	}
 	if q.getEmailByAddressForUserStmt, err = db.PrepareContext(ctx, getEmailByAddressForUser); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEmailByAddressForUser: %w", err)
 	}
+	if q.getGroupStmt, err = db.PrepareContext(ctx, getGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query GetGroup: %w", err)
+	}
+	if q.getGroupByNameStmt, err = db.PrepareContext(ctx, getGroupByName); err != nil {
+		return nil, fmt.Errorf("error preparing query GetGroupByName: %w", err)
+	}
+	if q.getGroupMemberStmt, err = db.PrepareContext(ctx, getGroupMember); err != nil {
+		return nil, fmt.Errorf("error preparing query GetGroupMember: %w", err)
+	}
+	if q.getLatestOwnUsernameHistoryStmt, err = db.PrepareContext(ctx, getLatestOwnUsernameHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestOwnUsernameHistory: %w", err)
+	}
+	if q.getPrimaryEmailStmt, err = db.PrepareContext(ctx, getPrimaryEmail); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPrimaryEmail: %w", err)
+	}
+	if q.getRoleStmt, err = db.PrepareContext(ctx, getRole); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRole: %w", err)
+	}
+	if q.getRoleByNameStmt, err = db.PrepareContext(ctx, getRoleByName); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRoleByName: %w", err)
+	}
+	if q.getThemeStmt, err = db.PrepareContext(ctx, getTheme); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTheme: %w", err)
+	}
 	if q.getUSerUsernameByIdStmt, err = db.PrepareContext(ctx, getUSerUsernameById); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUSerUsernameById: %w", err)
 	}
@@ -69,50 +219,263 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUserByUsernameStmt, err = db.PrepareContext(ctx, getUserByUsername); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByUsername: %w", err)
 	}
+	if q.getUserByUsernameSkeletonStmt, err = db.PrepareContext(ctx, getUserByUsernameSkeleton); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByUsernameSkeleton: %w", err)
+	}
+	if q.getUserDeletionStmt, err = db.PrepareContext(ctx, getUserDeletion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserDeletion: %w", err)
+	}
 	if q.getUserPermissionByNameStmt, err = db.PrepareContext(ctx, getUserPermissionByName); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserPermissionByName: %w", err)
 	}
-	if q.getUserSettingsStmt, err = db.PrepareContext(ctx, getUserSettings); err != nil {
-		return nil, fmt.Errorf("error preparing query GetUserSettings: %w", err)
+	if q.getUserProfileStmt, err = db.PrepareContext(ctx, getUserProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserProfile: %w", err)
+	}
+	if q.getUserRoleStmt, err = db.PrepareContext(ctx, getUserRole); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserRole: %w", err)
+	}
+	if q.getUserStatusStmt, err = db.PrepareContext(ctx, getUserStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserStatus: %w", err)
 	}
 	if q.getUserUsernameStmt, err = db.PrepareContext(ctx, getUserUsername); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserUsername: %w", err)
 	}
+	if q.getUsernameReservationStmt, err = db.PrepareContext(ctx, getUsernameReservation); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsernameReservation: %w", err)
+	}
+	if q.getUsernameRuleStmt, err = db.PrepareContext(ctx, getUsernameRule); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsernameRule: %w", err)
+	}
+	if q.getUsernameRuleByPatternStmt, err = db.PrepareContext(ctx, getUsernameRuleByPattern); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsernameRuleByPattern: %w", err)
+	}
 	if q.getVerifiedEmailByAddressStmt, err = db.PrepareContext(ctx, getVerifiedEmailByAddress); err != nil {
 		return nil, fmt.Errorf("error preparing query GetVerifiedEmailByAddress: %w", err)
 	}
+	if q.listDueUserDeletionsStmt, err = db.PrepareContext(ctx, listDueUserDeletions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueUserDeletions: %w", err)
+	}
 	if q.listEmailsStmt, err = db.PrepareContext(ctx, listEmails); err != nil {
 		return nil, fmt.Errorf("error preparing query ListEmails: %w", err)
 	}
+	if q.listExpiredUserPermissionsStmt, err = db.PrepareContext(ctx, listExpiredUserPermissions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExpiredUserPermissions: %w", err)
+	}
+	if q.listGroupMemberChangesStmt, err = db.PrepareContext(ctx, listGroupMemberChanges); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGroupMemberChanges: %w", err)
+	}
+	if q.listGroupMembersStmt, err = db.PrepareContext(ctx, listGroupMembers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGroupMembers: %w", err)
+	}
+	if q.listGroupPermissionsStmt, err = db.PrepareContext(ctx, listGroupPermissions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGroupPermissions: %w", err)
+	}
+	if q.listGroupsStmt, err = db.PrepareContext(ctx, listGroups); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGroups: %w", err)
+	}
+	if q.listRolePermissionsStmt, err = db.PrepareContext(ctx, listRolePermissions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListRolePermissions: %w", err)
+	}
+	if q.listRolesStmt, err = db.PrepareContext(ctx, listRoles); err != nil {
+		return nil, fmt.Errorf("error preparing query ListRoles: %w", err)
+	}
+	if q.listThemesStmt, err = db.PrepareContext(ctx, listThemes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListThemes: %w", err)
+	}
+	if q.listUserGroupsStmt, err = db.PrepareContext(ctx, listUserGroups); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserGroups: %w", err)
+	}
+	if q.listUserPermissionGrantsStmt, err = db.PrepareContext(ctx, listUserPermissionGrants); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserPermissionGrants: %w", err)
+	}
+	if q.listUserPermissionRevocationsStmt, err = db.PrepareContext(ctx, listUserPermissionRevocations); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserPermissionRevocations: %w", err)
+	}
 	if q.listUserPermissionsStmt, err = db.PrepareContext(ctx, listUserPermissions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUserPermissions: %w", err)
 	}
 	if q.listUserPermissionsByNameStmt, err = db.PrepareContext(ctx, listUserPermissionsByName); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUserPermissionsByName: %w", err)
 	}
+	if q.listUserRoleChangesStmt, err = db.PrepareContext(ctx, listUserRoleChanges); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserRoleChanges: %w", err)
+	}
+	if q.listUserRolesStmt, err = db.PrepareContext(ctx, listUserRoles); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserRoles: %w", err)
+	}
+	if q.listUserRolesByRoleIDStmt, err = db.PrepareContext(ctx, listUserRolesByRoleID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserRolesByRoleID: %w", err)
+	}
+	if q.listUserSettingValueUIDsStmt, err = db.PrepareContext(ctx, listUserSettingValueUIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserSettingValueUIDs: %w", err)
+	}
+	if q.listUserSettingValuesStmt, err = db.PrepareContext(ctx, listUserSettingValues); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserSettingValues: %w", err)
+	}
+	if q.listUserStatusChangesStmt, err = db.PrepareContext(ctx, listUserStatusChanges); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserStatusChanges: %w", err)
+	}
+	if q.listUsernameHistoryStmt, err = db.PrepareContext(ctx, listUsernameHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsernameHistory: %w", err)
+	}
+	if q.listUsernameRulesStmt, err = db.PrepareContext(ctx, listUsernameRules); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsernameRules: %w", err)
+	}
 	if q.listUsersStmt, err = db.PrepareContext(ctx, listUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUsers: %w", err)
 	}
+	if q.listUsersOrderByCreatedAtStmt, err = db.PrepareContext(ctx, listUsersOrderByCreatedAt); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsersOrderByCreatedAt: %w", err)
+	}
+	if q.listUsersOrderByCreatedAtDescStmt, err = db.PrepareContext(ctx, listUsersOrderByCreatedAtDesc); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsersOrderByCreatedAtDesc: %w", err)
+	}
+	if q.listUsersOrderByIDStmt, err = db.PrepareContext(ctx, listUsersOrderByID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsersOrderByID: %w", err)
+	}
+	if q.listUsersOrderByIDDescStmt, err = db.PrepareContext(ctx, listUsersOrderByIDDesc); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsersOrderByIDDesc: %w", err)
+	}
+	if q.listUsersOrderByUsernameStmt, err = db.PrepareContext(ctx, listUsersOrderByUsername); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsersOrderByUsername: %w", err)
+	}
+	if q.listUsersOrderByUsernameDescStmt, err = db.PrepareContext(ctx, listUsersOrderByUsernameDesc); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsersOrderByUsernameDesc: %w", err)
+	}
 	if q.listVerifiedEmailsStmt, err = db.PrepareContext(ctx, listVerifiedEmails); err != nil {
 		return nil, fmt.Errorf("error preparing query ListVerifiedEmails: %w", err)
 	}
 	if q.markEmailVerifiedStmt, err = db.PrepareContext(ctx, markEmailVerified); err != nil {
 		return nil, fmt.Errorf("error preparing query MarkEmailVerified: %w", err)
 	}
+	if q.resetUserSettingValuesStmt, err = db.PrepareContext(ctx, resetUserSettingValues); err != nil {
+		return nil, fmt.Errorf("error preparing query ResetUserSettingValues: %w", err)
+	}
 	if q.searchUsersByUsernameStmt, err = db.PrepareContext(ctx, searchUsersByUsername); err != nil {
 		return nil, fmt.Errorf("error preparing query SearchUsersByUsername: %w", err)
 	}
+	if q.setUserProfileStmt, err = db.PrepareContext(ctx, setUserProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserProfile: %w", err)
+	}
+	if q.setUserSettingValueStmt, err = db.PrepareContext(ctx, setUserSettingValue); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserSettingValue: %w", err)
+	}
+	if q.setUserStatusStmt, err = db.PrepareContext(ctx, setUserStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserStatus: %w", err)
+	}
+	if q.updateGroupStmt, err = db.PrepareContext(ctx, updateGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateGroup: %w", err)
+	}
+	if q.updateRoleStmt, err = db.PrepareContext(ctx, updateRole); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateRole: %w", err)
+	}
+	if q.updateThemePaletteStmt, err = db.PrepareContext(ctx, updateThemePalette); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateThemePalette: %w", err)
+	}
 	if q.updateUserPasswordStmt, err = db.PrepareContext(ctx, updateUserPassword); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserPassword: %w", err)
 	}
-	if q.updateUserSettingsThemeStmt, err = db.PrepareContext(ctx, updateUserSettingsTheme); err != nil {
-		return nil, fmt.Errorf("error preparing query UpdateUserSettingsTheme: %w", err)
+	if q.updateUserPermissionExpiryStmt, err = db.PrepareContext(ctx, updateUserPermissionExpiry); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserPermissionExpiry: %w", err)
+	}
+	if q.updateUsernameStmt, err = db.PrepareContext(ctx, updateUsername); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUsername: %w", err)
 	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.anonymizeGroupMemberChangesByIUIDStmt != nil {
+		if cerr := q.anonymizeGroupMemberChangesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeGroupMemberChangesByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeGroupMemberChangesByUIDStmt != nil {
+		if cerr := q.anonymizeGroupMemberChangesByUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeGroupMemberChangesByUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeGroupMembersByIUIDStmt != nil {
+		if cerr := q.anonymizeGroupMembersByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeGroupMembersByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeGroupsByIUIDStmt != nil {
+		if cerr := q.anonymizeGroupsByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeGroupsByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeRolesByIUIDStmt != nil {
+		if cerr := q.anonymizeRolesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeRolesByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeThemesByIUIDStmt != nil {
+		if cerr := q.anonymizeThemesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeThemesByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserDeletionsByIUIDStmt != nil {
+		if cerr := q.anonymizeUserDeletionsByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserDeletionsByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserPermissionGrantsByIUIDStmt != nil {
+		if cerr := q.anonymizeUserPermissionGrantsByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserPermissionGrantsByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserPermissionGrantsByUIDStmt != nil {
+		if cerr := q.anonymizeUserPermissionGrantsByUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserPermissionGrantsByUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserPermissionRevocationsByIUIDStmt != nil {
+		if cerr := q.anonymizeUserPermissionRevocationsByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserPermissionRevocationsByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserPermissionRevocationsByUIDStmt != nil {
+		if cerr := q.anonymizeUserPermissionRevocationsByUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserPermissionRevocationsByUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserRoleChangesByIUIDStmt != nil {
+		if cerr := q.anonymizeUserRoleChangesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserRoleChangesByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserRoleChangesByUIDStmt != nil {
+		if cerr := q.anonymizeUserRoleChangesByUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserRoleChangesByUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserRolesByIUIDStmt != nil {
+		if cerr := q.anonymizeUserRolesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserRolesByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserStatusChangesByIUIDStmt != nil {
+		if cerr := q.anonymizeUserStatusChangesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserStatusChangesByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUserStatusChangesByUIDStmt != nil {
+		if cerr := q.anonymizeUserStatusChangesByUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUserStatusChangesByUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUsernameHistoryByIUIDStmt != nil {
+		if cerr := q.anonymizeUsernameHistoryByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUsernameHistoryByIUIDStmt: %w", cerr)
+		}
+	}
+	if q.anonymizeUsernameRulesByIUIDStmt != nil {
+		if cerr := q.anonymizeUsernameRulesByIUIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anonymizeUsernameRulesByIUIDStmt: %w", cerr)
+		}
+	}
 	if q.countEmailsStmt != nil {
 		if cerr := q.countEmailsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countEmailsStmt: %w", cerr)
@@ -123,11 +486,51 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createEmailStmt: %w", cerr)
 		}
 	}
+	if q.createGroupStmt != nil {
+		if cerr := q.createGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createGroupStmt: %w", cerr)
+		}
+	}
+	if q.createGroupMemberStmt != nil {
+		if cerr := q.createGroupMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createGroupMemberStmt: %w", cerr)
+		}
+	}
+	if q.createGroupMemberChangeStmt != nil {
+		if cerr := q.createGroupMemberChangeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createGroupMemberChangeStmt: %w", cerr)
+		}
+	}
+	if q.createGroupPermissionStmt != nil {
+		if cerr := q.createGroupPermissionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createGroupPermissionStmt: %w", cerr)
+		}
+	}
+	if q.createRoleStmt != nil {
+		if cerr := q.createRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRoleStmt: %w", cerr)
+		}
+	}
+	if q.createRolePermissionStmt != nil {
+		if cerr := q.createRolePermissionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRolePermissionStmt: %w", cerr)
+		}
+	}
+	if q.createThemeStmt != nil {
+		if cerr := q.createThemeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createThemeStmt: %w", cerr)
+		}
+	}
 	if q.createUserStmt != nil {
 		if cerr := q.createUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
 		}
 	}
+	if q.createUserDeletionStmt != nil {
+		if cerr := q.createUserDeletionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserDeletionStmt: %w", cerr)
+		}
+	}
 	if q.createUserPermissionStmt != nil {
 		if cerr := q.createUserPermissionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createUserPermissionStmt: %w", cerr)
@@ -143,9 +546,29 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createUserPermissionRevocationStmt: %w", cerr)
 		}
 	}
-	if q.createUserSettingsStmt != nil {
-		if cerr := q.createUserSettingsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createUserSettingsStmt: %w", cerr)
+	if q.createUserRoleStmt != nil {
+		if cerr := q.createUserRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserRoleStmt: %w", cerr)
+		}
+	}
+	if q.createUserRoleChangeStmt != nil {
+		if cerr := q.createUserRoleChangeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserRoleChangeStmt: %w", cerr)
+		}
+	}
+	if q.createUserStatusChangeStmt != nil {
+		if cerr := q.createUserStatusChangeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserStatusChangeStmt: %w", cerr)
+		}
+	}
+	if q.createUsernameHistoryStmt != nil {
+		if cerr := q.createUsernameHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUsernameHistoryStmt: %w", cerr)
+		}
+	}
+	if q.createUsernameRuleStmt != nil {
+		if cerr := q.createUsernameRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUsernameRuleStmt: %w", cerr)
 		}
 	}
 	if q.deleteEmailStmt != nil {
@@ -153,6 +576,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteEmailStmt: %w", cerr)
 		}
 	}
+	if q.deleteGroupStmt != nil {
+		if cerr := q.deleteGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteGroupStmt: %w", cerr)
+		}
+	}
+	if q.deleteGroupMemberStmt != nil {
+		if cerr := q.deleteGroupMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteGroupMemberStmt: %w", cerr)
+		}
+	}
+	if q.deleteGroupPermissionsStmt != nil {
+		if cerr := q.deleteGroupPermissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteGroupPermissionsStmt: %w", cerr)
+		}
+	}
+	if q.deleteRoleStmt != nil {
+		if cerr := q.deleteRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRoleStmt: %w", cerr)
+		}
+	}
+	if q.deleteRolePermissionsStmt != nil {
+		if cerr := q.deleteRolePermissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRolePermissionsStmt: %w", cerr)
+		}
+	}
+	if q.deleteThemeStmt != nil {
+		if cerr := q.deleteThemeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteThemeStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserStmt != nil {
+		if cerr := q.deleteUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserDeletionStmt != nil {
+		if cerr := q.deleteUserDeletionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserDeletionStmt: %w", cerr)
+		}
+	}
 	if q.deleteUserPermissionStmt != nil {
 		if cerr := q.deleteUserPermissionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteUserPermissionStmt: %w", cerr)
@@ -163,6 +626,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteUserPermissionsByNameStmt: %w", cerr)
 		}
 	}
+	if q.deleteUserRoleStmt != nil {
+		if cerr := q.deleteUserRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserRoleStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserSettingValueStmt != nil {
+		if cerr := q.deleteUserSettingValueStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserSettingValueStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserStatusStmt != nil {
+		if cerr := q.deleteUserStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserStatusStmt: %w", cerr)
+		}
+	}
+	if q.deleteUsernameRuleStmt != nil {
+		if cerr := q.deleteUsernameRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUsernameRuleStmt: %w", cerr)
+		}
+	}
 	if q.getEmailStmt != nil {
 		if cerr := q.getEmailStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getEmailStmt: %w", cerr)
@@ -173,6 +656,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getEmailByAddressForUserStmt: %w", cerr)
 		}
 	}
+	if q.getGroupStmt != nil {
+		if cerr := q.getGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getGroupStmt: %w", cerr)
+		}
+	}
+	if q.getGroupByNameStmt != nil {
+		if cerr := q.getGroupByNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getGroupByNameStmt: %w", cerr)
+		}
+	}
+	if q.getGroupMemberStmt != nil {
+		if cerr := q.getGroupMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getGroupMemberStmt: %w", cerr)
+		}
+	}
+	if q.getLatestOwnUsernameHistoryStmt != nil {
+		if cerr := q.getLatestOwnUsernameHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestOwnUsernameHistoryStmt: %w", cerr)
+		}
+	}
+	if q.getPrimaryEmailStmt != nil {
+		if cerr := q.getPrimaryEmailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPrimaryEmailStmt: %w", cerr)
+		}
+	}
+	if q.getRoleStmt != nil {
+		if cerr := q.getRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRoleStmt: %w", cerr)
+		}
+	}
+	if q.getRoleByNameStmt != nil {
+		if cerr := q.getRoleByNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRoleByNameStmt: %w", cerr)
+		}
+	}
+	if q.getThemeStmt != nil {
+		if cerr := q.getThemeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getThemeStmt: %w", cerr)
+		}
+	}
 	if q.getUSerUsernameByIdStmt != nil {
 		if cerr := q.getUSerUsernameByIdStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUSerUsernameByIdStmt: %w", cerr)
@@ -188,14 +711,34 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserByUsernameStmt: %w", cerr)
 		}
 	}
+	if q.getUserByUsernameSkeletonStmt != nil {
+		if cerr := q.getUserByUsernameSkeletonStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByUsernameSkeletonStmt: %w", cerr)
+		}
+	}
+	if q.getUserDeletionStmt != nil {
+		if cerr := q.getUserDeletionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserDeletionStmt: %w", cerr)
+		}
+	}
 	if q.getUserPermissionByNameStmt != nil {
 		if cerr := q.getUserPermissionByNameStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserPermissionByNameStmt: %w", cerr)
 		}
 	}
-	if q.getUserSettingsStmt != nil {
-		if cerr := q.getUserSettingsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getUserSettingsStmt: %w", cerr)
+	if q.getUserProfileStmt != nil {
+		if cerr := q.getUserProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserProfileStmt: %w", cerr)
+		}
+	}
+	if q.getUserRoleStmt != nil {
+		if cerr := q.getUserRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserRoleStmt: %w", cerr)
+		}
+	}
+	if q.getUserStatusStmt != nil {
+		if cerr := q.getUserStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserStatusStmt: %w", cerr)
 		}
 	}
 	if q.getUserUsernameStmt != nil {
@@ -203,16 +746,91 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserUsernameStmt: %w", cerr)
 		}
 	}
+	if q.getUsernameReservationStmt != nil {
+		if cerr := q.getUsernameReservationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsernameReservationStmt: %w", cerr)
+		}
+	}
+	if q.getUsernameRuleStmt != nil {
+		if cerr := q.getUsernameRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsernameRuleStmt: %w", cerr)
+		}
+	}
+	if q.getUsernameRuleByPatternStmt != nil {
+		if cerr := q.getUsernameRuleByPatternStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsernameRuleByPatternStmt: %w", cerr)
+		}
+	}
 	if q.getVerifiedEmailByAddressStmt != nil {
 		if cerr := q.getVerifiedEmailByAddressStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getVerifiedEmailByAddressStmt: %w", cerr)
 		}
 	}
+	if q.listDueUserDeletionsStmt != nil {
+		if cerr := q.listDueUserDeletionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueUserDeletionsStmt: %w", cerr)
+		}
+	}
 	if q.listEmailsStmt != nil {
 		if cerr := q.listEmailsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listEmailsStmt: %w", cerr)
 		}
 	}
+	if q.listExpiredUserPermissionsStmt != nil {
+		if cerr := q.listExpiredUserPermissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExpiredUserPermissionsStmt: %w", cerr)
+		}
+	}
+	if q.listGroupMemberChangesStmt != nil {
+		if cerr := q.listGroupMemberChangesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGroupMemberChangesStmt: %w", cerr)
+		}
+	}
+	if q.listGroupMembersStmt != nil {
+		if cerr := q.listGroupMembersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGroupMembersStmt: %w", cerr)
+		}
+	}
+	if q.listGroupPermissionsStmt != nil {
+		if cerr := q.listGroupPermissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGroupPermissionsStmt: %w", cerr)
+		}
+	}
+	if q.listGroupsStmt != nil {
+		if cerr := q.listGroupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGroupsStmt: %w", cerr)
+		}
+	}
+	if q.listRolePermissionsStmt != nil {
+		if cerr := q.listRolePermissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listRolePermissionsStmt: %w", cerr)
+		}
+	}
+	if q.listRolesStmt != nil {
+		if cerr := q.listRolesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listRolesStmt: %w", cerr)
+		}
+	}
+	if q.listThemesStmt != nil {
+		if cerr := q.listThemesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listThemesStmt: %w", cerr)
+		}
+	}
+	if q.listUserGroupsStmt != nil {
+		if cerr := q.listUserGroupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserGroupsStmt: %w", cerr)
+		}
+	}
+	if q.listUserPermissionGrantsStmt != nil {
+		if cerr := q.listUserPermissionGrantsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserPermissionGrantsStmt: %w", cerr)
+		}
+	}
+	if q.listUserPermissionRevocationsStmt != nil {
+		if cerr := q.listUserPermissionRevocationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserPermissionRevocationsStmt: %w", cerr)
+		}
+	}
 	if q.listUserPermissionsStmt != nil {
 		if cerr := q.listUserPermissionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listUserPermissionsStmt: %w", cerr)
@@ -223,11 +841,81 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listUserPermissionsByNameStmt: %w", cerr)
 		}
 	}
+	if q.listUserRoleChangesStmt != nil {
+		if cerr := q.listUserRoleChangesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserRoleChangesStmt: %w", cerr)
+		}
+	}
+	if q.listUserRolesStmt != nil {
+		if cerr := q.listUserRolesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserRolesStmt: %w", cerr)
+		}
+	}
+	if q.listUserRolesByRoleIDStmt != nil {
+		if cerr := q.listUserRolesByRoleIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserRolesByRoleIDStmt: %w", cerr)
+		}
+	}
+	if q.listUserSettingValueUIDsStmt != nil {
+		if cerr := q.listUserSettingValueUIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserSettingValueUIDsStmt: %w", cerr)
+		}
+	}
+	if q.listUserSettingValuesStmt != nil {
+		if cerr := q.listUserSettingValuesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserSettingValuesStmt: %w", cerr)
+		}
+	}
+	if q.listUserStatusChangesStmt != nil {
+		if cerr := q.listUserStatusChangesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserStatusChangesStmt: %w", cerr)
+		}
+	}
+	if q.listUsernameHistoryStmt != nil {
+		if cerr := q.listUsernameHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsernameHistoryStmt: %w", cerr)
+		}
+	}
+	if q.listUsernameRulesStmt != nil {
+		if cerr := q.listUsernameRulesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsernameRulesStmt: %w", cerr)
+		}
+	}
 	if q.listUsersStmt != nil {
 		if cerr := q.listUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listUsersStmt: %w", cerr)
 		}
 	}
+	if q.listUsersOrderByCreatedAtStmt != nil {
+		if cerr := q.listUsersOrderByCreatedAtStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersOrderByCreatedAtStmt: %w", cerr)
+		}
+	}
+	if q.listUsersOrderByCreatedAtDescStmt != nil {
+		if cerr := q.listUsersOrderByCreatedAtDescStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersOrderByCreatedAtDescStmt: %w", cerr)
+		}
+	}
+	if q.listUsersOrderByIDStmt != nil {
+		if cerr := q.listUsersOrderByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersOrderByIDStmt: %w", cerr)
+		}
+	}
+	if q.listUsersOrderByIDDescStmt != nil {
+		if cerr := q.listUsersOrderByIDDescStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersOrderByIDDescStmt: %w", cerr)
+		}
+	}
+	if q.listUsersOrderByUsernameStmt != nil {
+		if cerr := q.listUsersOrderByUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersOrderByUsernameStmt: %w", cerr)
+		}
+	}
+	if q.listUsersOrderByUsernameDescStmt != nil {
+		if cerr := q.listUsersOrderByUsernameDescStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersOrderByUsernameDescStmt: %w", cerr)
+		}
+	}
 	if q.listVerifiedEmailsStmt != nil {
 		if cerr := q.listVerifiedEmailsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listVerifiedEmailsStmt: %w", cerr)
@@ -238,19 +926,59 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing markEmailVerifiedStmt: %w", cerr)
 		}
 	}
+	if q.resetUserSettingValuesStmt != nil {
+		if cerr := q.resetUserSettingValuesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resetUserSettingValuesStmt: %w", cerr)
+		}
+	}
 	if q.searchUsersByUsernameStmt != nil {
 		if cerr := q.searchUsersByUsernameStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing searchUsersByUsernameStmt: %w", cerr)
 		}
 	}
+	if q.setUserProfileStmt != nil {
+		if cerr := q.setUserProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserProfileStmt: %w", cerr)
+		}
+	}
+	if q.setUserSettingValueStmt != nil {
+		if cerr := q.setUserSettingValueStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserSettingValueStmt: %w", cerr)
+		}
+	}
+	if q.setUserStatusStmt != nil {
+		if cerr := q.setUserStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserStatusStmt: %w", cerr)
+		}
+	}
+	if q.updateGroupStmt != nil {
+		if cerr := q.updateGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateGroupStmt: %w", cerr)
+		}
+	}
+	if q.updateRoleStmt != nil {
+		if cerr := q.updateRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateRoleStmt: %w", cerr)
+		}
+	}
+	if q.updateThemePaletteStmt != nil {
+		if cerr := q.updateThemePaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateThemePaletteStmt: %w", cerr)
+		}
+	}
 	if q.updateUserPasswordStmt != nil {
 		if cerr := q.updateUserPasswordStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateUserPasswordStmt: %w", cerr)
 		}
 	}
-	if q.updateUserSettingsThemeStmt != nil {
-		if cerr := q.updateUserSettingsThemeStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing updateUserSettingsThemeStmt: %w", cerr)
+	if q.updateUserPermissionExpiryStmt != nil {
+		if cerr := q.updateUserPermissionExpiryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserPermissionExpiryStmt: %w", cerr)
+		}
+	}
+	if q.updateUsernameStmt != nil {
+		if cerr := q.updateUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUsernameStmt: %w", cerr)
 		}
 	}
 	return err
@@ -290,69 +1018,251 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                                 DBTX
-	tx                                 *sql.Tx
-	countEmailsStmt                    *sql.Stmt
-	createEmailStmt                    *sql.Stmt
-	createUserStmt                     *sql.Stmt
-	createUserPermissionStmt           *sql.Stmt
-	createUserPermissionGrantStmt      *sql.Stmt
-	createUserPermissionRevocationStmt *sql.Stmt
-	createUserSettingsStmt             *sql.Stmt
-	deleteEmailStmt                    *sql.Stmt
-	deleteUserPermissionStmt           *sql.Stmt
-	deleteUserPermissionsByNameStmt    *sql.Stmt
-	getEmailStmt                       *sql.Stmt
-	getEmailByAddressForUserStmt       *sql.Stmt
-	getUSerUsernameByIdStmt            *sql.Stmt
-	getUserStmt                        *sql.Stmt
-	getUserByUsernameStmt              *sql.Stmt
-	getUserPermissionByNameStmt        *sql.Stmt
-	getUserSettingsStmt                *sql.Stmt
-	getUserUsernameStmt                *sql.Stmt
-	getVerifiedEmailByAddressStmt      *sql.Stmt
-	listEmailsStmt                     *sql.Stmt
-	listUserPermissionsStmt            *sql.Stmt
-	listUserPermissionsByNameStmt      *sql.Stmt
-	listUsersStmt                      *sql.Stmt
-	listVerifiedEmailsStmt             *sql.Stmt
-	markEmailVerifiedStmt              *sql.Stmt
-	searchUsersByUsernameStmt          *sql.Stmt
-	updateUserPasswordStmt             *sql.Stmt
-	updateUserSettingsThemeStmt        *sql.Stmt
+	db                                           DBTX
+	tx                                           *sql.Tx
+	anonymizeGroupMemberChangesByIUIDStmt        *sql.Stmt
+	anonymizeGroupMemberChangesByUIDStmt         *sql.Stmt
+	anonymizeGroupMembersByIUIDStmt              *sql.Stmt
+	anonymizeGroupsByIUIDStmt                    *sql.Stmt
+	anonymizeRolesByIUIDStmt                     *sql.Stmt
+	anonymizeThemesByIUIDStmt                    *sql.Stmt
+	anonymizeUserDeletionsByIUIDStmt             *sql.Stmt
+	anonymizeUserPermissionGrantsByIUIDStmt      *sql.Stmt
+	anonymizeUserPermissionGrantsByUIDStmt       *sql.Stmt
+	anonymizeUserPermissionRevocationsByIUIDStmt *sql.Stmt
+	anonymizeUserPermissionRevocationsByUIDStmt  *sql.Stmt
+	anonymizeUserRoleChangesByIUIDStmt           *sql.Stmt
+	anonymizeUserRoleChangesByUIDStmt            *sql.Stmt
+	anonymizeUserRolesByIUIDStmt                 *sql.Stmt
+	anonymizeUserStatusChangesByIUIDStmt         *sql.Stmt
+	anonymizeUserStatusChangesByUIDStmt          *sql.Stmt
+	anonymizeUsernameHistoryByIUIDStmt           *sql.Stmt
+	anonymizeUsernameRulesByIUIDStmt             *sql.Stmt
+	countEmailsStmt                              *sql.Stmt
+	createEmailStmt                              *sql.Stmt
+	createGroupStmt                              *sql.Stmt
+	createGroupMemberStmt                        *sql.Stmt
+	createGroupMemberChangeStmt                  *sql.Stmt
+	createGroupPermissionStmt                    *sql.Stmt
+	createRoleStmt                               *sql.Stmt
+	createRolePermissionStmt                     *sql.Stmt
+	createThemeStmt                              *sql.Stmt
+	createUserStmt                               *sql.Stmt
+	createUserDeletionStmt                       *sql.Stmt
+	createUserPermissionStmt                     *sql.Stmt
+	createUserPermissionGrantStmt                *sql.Stmt
+	createUserPermissionRevocationStmt           *sql.Stmt
+	createUserRoleStmt                           *sql.Stmt
+	createUserRoleChangeStmt                     *sql.Stmt
+	createUserStatusChangeStmt                   *sql.Stmt
+	createUsernameHistoryStmt                    *sql.Stmt
+	createUsernameRuleStmt                       *sql.Stmt
+	deleteEmailStmt                              *sql.Stmt
+	deleteGroupStmt                              *sql.Stmt
+	deleteGroupMemberStmt                        *sql.Stmt
+	deleteGroupPermissionsStmt                   *sql.Stmt
+	deleteRoleStmt                               *sql.Stmt
+	deleteRolePermissionsStmt                    *sql.Stmt
+	deleteThemeStmt                              *sql.Stmt
+	deleteUserStmt                               *sql.Stmt
+	deleteUserDeletionStmt                       *sql.Stmt
+	deleteUserPermissionStmt                     *sql.Stmt
+	deleteUserPermissionsByNameStmt              *sql.Stmt
+	deleteUserRoleStmt                           *sql.Stmt
+	deleteUserSettingValueStmt                   *sql.Stmt
+	deleteUserStatusStmt                         *sql.Stmt
+	deleteUsernameRuleStmt                       *sql.Stmt
+	getEmailStmt                                 *sql.Stmt
+	getEmailByAddressForUserStmt                 *sql.Stmt
+	getGroupStmt                                 *sql.Stmt
+	getGroupByNameStmt                           *sql.Stmt
+	getGroupMemberStmt                           *sql.Stmt
+	getLatestOwnUsernameHistoryStmt              *sql.Stmt
+	getPrimaryEmailStmt                          *sql.Stmt
+	getRoleStmt                                  *sql.Stmt
+	getRoleByNameStmt                            *sql.Stmt
+	getThemeStmt                                 *sql.Stmt
+	getUSerUsernameByIdStmt                      *sql.Stmt
+	getUserStmt                                  *sql.Stmt
+	getUserByUsernameStmt                        *sql.Stmt
+	getUserByUsernameSkeletonStmt                *sql.Stmt
+	getUserDeletionStmt                          *sql.Stmt
+	getUserPermissionByNameStmt                  *sql.Stmt
+	getUserProfileStmt                           *sql.Stmt
+	getUserRoleStmt                              *sql.Stmt
+	getUserStatusStmt                            *sql.Stmt
+	getUserUsernameStmt                          *sql.Stmt
+	getUsernameReservationStmt                   *sql.Stmt
+	getUsernameRuleStmt                          *sql.Stmt
+	getUsernameRuleByPatternStmt                 *sql.Stmt
+	getVerifiedEmailByAddressStmt                *sql.Stmt
+	listDueUserDeletionsStmt                     *sql.Stmt
+	listEmailsStmt                               *sql.Stmt
+	listExpiredUserPermissionsStmt               *sql.Stmt
+	listGroupMemberChangesStmt                   *sql.Stmt
+	listGroupMembersStmt                         *sql.Stmt
+	listGroupPermissionsStmt                     *sql.Stmt
+	listGroupsStmt                               *sql.Stmt
+	listRolePermissionsStmt                      *sql.Stmt
+	listRolesStmt                                *sql.Stmt
+	listThemesStmt                               *sql.Stmt
+	listUserGroupsStmt                           *sql.Stmt
+	listUserPermissionGrantsStmt                 *sql.Stmt
+	listUserPermissionRevocationsStmt            *sql.Stmt
+	listUserPermissionsStmt                      *sql.Stmt
+	listUserPermissionsByNameStmt                *sql.Stmt
+	listUserRoleChangesStmt                      *sql.Stmt
+	listUserRolesStmt                            *sql.Stmt
+	listUserRolesByRoleIDStmt                    *sql.Stmt
+	listUserSettingValueUIDsStmt                 *sql.Stmt
+	listUserSettingValuesStmt                    *sql.Stmt
+	listUserStatusChangesStmt                    *sql.Stmt
+	listUsernameHistoryStmt                      *sql.Stmt
+	listUsernameRulesStmt                        *sql.Stmt
+	listUsersStmt                                *sql.Stmt
+	listUsersOrderByCreatedAtStmt                *sql.Stmt
+	listUsersOrderByCreatedAtDescStmt            *sql.Stmt
+	listUsersOrderByIDStmt                       *sql.Stmt
+	listUsersOrderByIDDescStmt                   *sql.Stmt
+	listUsersOrderByUsernameStmt                 *sql.Stmt
+	listUsersOrderByUsernameDescStmt             *sql.Stmt
+	listVerifiedEmailsStmt                       *sql.Stmt
+	markEmailVerifiedStmt                        *sql.Stmt
+	resetUserSettingValuesStmt                   *sql.Stmt
+	searchUsersByUsernameStmt                    *sql.Stmt
+	setUserProfileStmt                           *sql.Stmt
+	setUserSettingValueStmt                      *sql.Stmt
+	setUserStatusStmt                            *sql.Stmt
+	updateGroupStmt                              *sql.Stmt
+	updateRoleStmt                               *sql.Stmt
+	updateThemePaletteStmt                       *sql.Stmt
+	updateUserPasswordStmt                       *sql.Stmt
+	updateUserPermissionExpiryStmt               *sql.Stmt
+	updateUsernameStmt                           *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                                 tx,
-		tx:                                 tx,
-		countEmailsStmt:                    q.countEmailsStmt,
-		createEmailStmt:                    q.createEmailStmt,
-		createUserStmt:                     q.createUserStmt,
-		createUserPermissionStmt:           q.createUserPermissionStmt,
-		createUserPermissionGrantStmt:      q.createUserPermissionGrantStmt,
-		createUserPermissionRevocationStmt: q.createUserPermissionRevocationStmt,
-		createUserSettingsStmt:             q.createUserSettingsStmt,
-		deleteEmailStmt:                    q.deleteEmailStmt,
-		deleteUserPermissionStmt:           q.deleteUserPermissionStmt,
-		deleteUserPermissionsByNameStmt:    q.deleteUserPermissionsByNameStmt,
-		getEmailStmt:                       q.getEmailStmt,
-		getEmailByAddressForUserStmt:       q.getEmailByAddressForUserStmt,
-		getUSerUsernameByIdStmt:            q.getUSerUsernameByIdStmt,
-		getUserStmt:                        q.getUserStmt,
-		getUserByUsernameStmt:              q.getUserByUsernameStmt,
-		getUserPermissionByNameStmt:        q.getUserPermissionByNameStmt,
-		getUserSettingsStmt:                q.getUserSettingsStmt,
-		getUserUsernameStmt:                q.getUserUsernameStmt,
-		getVerifiedEmailByAddressStmt:      q.getVerifiedEmailByAddressStmt,
-		listEmailsStmt:                     q.listEmailsStmt,
-		listUserPermissionsStmt:            q.listUserPermissionsStmt,
-		listUserPermissionsByNameStmt:      q.listUserPermissionsByNameStmt,
-		listUsersStmt:                      q.listUsersStmt,
-		listVerifiedEmailsStmt:             q.listVerifiedEmailsStmt,
-		markEmailVerifiedStmt:              q.markEmailVerifiedStmt,
-		searchUsersByUsernameStmt:          q.searchUsersByUsernameStmt,
-		updateUserPasswordStmt:             q.updateUserPasswordStmt,
-		updateUserSettingsThemeStmt:        q.updateUserSettingsThemeStmt,
+		db:                                           tx,
+		tx:                                           tx,
+		anonymizeGroupMemberChangesByIUIDStmt:        q.anonymizeGroupMemberChangesByIUIDStmt,
+		anonymizeGroupMemberChangesByUIDStmt:         q.anonymizeGroupMemberChangesByUIDStmt,
+		anonymizeGroupMembersByIUIDStmt:              q.anonymizeGroupMembersByIUIDStmt,
+		anonymizeGroupsByIUIDStmt:                    q.anonymizeGroupsByIUIDStmt,
+		anonymizeRolesByIUIDStmt:                     q.anonymizeRolesByIUIDStmt,
+		anonymizeThemesByIUIDStmt:                    q.anonymizeThemesByIUIDStmt,
+		anonymizeUserDeletionsByIUIDStmt:             q.anonymizeUserDeletionsByIUIDStmt,
+		anonymizeUserPermissionGrantsByIUIDStmt:      q.anonymizeUserPermissionGrantsByIUIDStmt,
+		anonymizeUserPermissionGrantsByUIDStmt:       q.anonymizeUserPermissionGrantsByUIDStmt,
+		anonymizeUserPermissionRevocationsByIUIDStmt: q.anonymizeUserPermissionRevocationsByIUIDStmt,
+		anonymizeUserPermissionRevocationsByUIDStmt:  q.anonymizeUserPermissionRevocationsByUIDStmt,
+		anonymizeUserRoleChangesByIUIDStmt:           q.anonymizeUserRoleChangesByIUIDStmt,
+		anonymizeUserRoleChangesByUIDStmt:            q.anonymizeUserRoleChangesByUIDStmt,
+		anonymizeUserRolesByIUIDStmt:                 q.anonymizeUserRolesByIUIDStmt,
+		anonymizeUserStatusChangesByIUIDStmt:         q.anonymizeUserStatusChangesByIUIDStmt,
+		anonymizeUserStatusChangesByUIDStmt:          q.anonymizeUserStatusChangesByUIDStmt,
+		anonymizeUsernameHistoryByIUIDStmt:           q.anonymizeUsernameHistoryByIUIDStmt,
+		anonymizeUsernameRulesByIUIDStmt:             q.anonymizeUsernameRulesByIUIDStmt,
+		countEmailsStmt:                              q.countEmailsStmt,
+		createEmailStmt:                              q.createEmailStmt,
+		createGroupStmt:                              q.createGroupStmt,
+		createGroupMemberStmt:                        q.createGroupMemberStmt,
+		createGroupMemberChangeStmt:                  q.createGroupMemberChangeStmt,
+		createGroupPermissionStmt:                    q.createGroupPermissionStmt,
+		createRoleStmt:                               q.createRoleStmt,
+		createRolePermissionStmt:                     q.createRolePermissionStmt,
+		createThemeStmt:                              q.createThemeStmt,
+		createUserStmt:                               q.createUserStmt,
+		createUserDeletionStmt:                       q.createUserDeletionStmt,
+		createUserPermissionStmt:                     q.createUserPermissionStmt,
+		createUserPermissionGrantStmt:                q.createUserPermissionGrantStmt,
+		createUserPermissionRevocationStmt:           q.createUserPermissionRevocationStmt,
+		createUserRoleStmt:                           q.createUserRoleStmt,
+		createUserRoleChangeStmt:                     q.createUserRoleChangeStmt,
+		createUserStatusChangeStmt:                   q.createUserStatusChangeStmt,
+		createUsernameHistoryStmt:                    q.createUsernameHistoryStmt,
+		createUsernameRuleStmt:                       q.createUsernameRuleStmt,
+		deleteEmailStmt:                              q.deleteEmailStmt,
+		deleteGroupStmt:                              q.deleteGroupStmt,
+		deleteGroupMemberStmt:                        q.deleteGroupMemberStmt,
+		deleteGroupPermissionsStmt:                   q.deleteGroupPermissionsStmt,
+		deleteRoleStmt:                               q.deleteRoleStmt,
+		deleteRolePermissionsStmt:                    q.deleteRolePermissionsStmt,
+		deleteThemeStmt:                              q.deleteThemeStmt,
+		deleteUserStmt:                               q.deleteUserStmt,
+		deleteUserDeletionStmt:                       q.deleteUserDeletionStmt,
+		deleteUserPermissionStmt:                     q.deleteUserPermissionStmt,
+		deleteUserPermissionsByNameStmt:              q.deleteUserPermissionsByNameStmt,
+		deleteUserRoleStmt:                           q.deleteUserRoleStmt,
+		deleteUserSettingValueStmt:                   q.deleteUserSettingValueStmt,
+		deleteUserStatusStmt:                         q.deleteUserStatusStmt,
+		deleteUsernameRuleStmt:                       q.deleteUsernameRuleStmt,
+		getEmailStmt:                                 q.getEmailStmt,
+		getEmailByAddressForUserStmt:                 q.getEmailByAddressForUserStmt,
+		getGroupStmt:                                 q.getGroupStmt,
+		getGroupByNameStmt:                           q.getGroupByNameStmt,
+		getGroupMemberStmt:                           q.getGroupMemberStmt,
+		getLatestOwnUsernameHistoryStmt:              q.getLatestOwnUsernameHistoryStmt,
+		getPrimaryEmailStmt:                          q.getPrimaryEmailStmt,
+		getRoleStmt:                                  q.getRoleStmt,
+		getRoleByNameStmt:                            q.getRoleByNameStmt,
+		getThemeStmt:                                 q.getThemeStmt,
+		getUSerUsernameByIdStmt:                      q.getUSerUsernameByIdStmt,
+		getUserStmt:                                  q.getUserStmt,
+		getUserByUsernameStmt:                        q.getUserByUsernameStmt,
+		getUserByUsernameSkeletonStmt:                q.getUserByUsernameSkeletonStmt,
+		getUserDeletionStmt:                          q.getUserDeletionStmt,
+		getUserPermissionByNameStmt:                  q.getUserPermissionByNameStmt,
+		getUserProfileStmt:                           q.getUserProfileStmt,
+		getUserRoleStmt:                              q.getUserRoleStmt,
+		getUserStatusStmt:                            q.getUserStatusStmt,
+		getUserUsernameStmt:                          q.getUserUsernameStmt,
+		getUsernameReservationStmt:                   q.getUsernameReservationStmt,
+		getUsernameRuleStmt:                          q.getUsernameRuleStmt,
+		getUsernameRuleByPatternStmt:                 q.getUsernameRuleByPatternStmt,
+		getVerifiedEmailByAddressStmt:                q.getVerifiedEmailByAddressStmt,
+		listDueUserDeletionsStmt:                     q.listDueUserDeletionsStmt,
+		listEmailsStmt:                               q.listEmailsStmt,
+		listExpiredUserPermissionsStmt:               q.listExpiredUserPermissionsStmt,
+		listGroupMemberChangesStmt:                   q.listGroupMemberChangesStmt,
+		listGroupMembersStmt:                         q.listGroupMembersStmt,
+		listGroupPermissionsStmt:                     q.listGroupPermissionsStmt,
+		listGroupsStmt:                               q.listGroupsStmt,
+		listRolePermissionsStmt:                      q.listRolePermissionsStmt,
+		listRolesStmt:                                q.listRolesStmt,
+		listThemesStmt:                               q.listThemesStmt,
+		listUserGroupsStmt:                           q.listUserGroupsStmt,
+		listUserPermissionGrantsStmt:                 q.listUserPermissionGrantsStmt,
+		listUserPermissionRevocationsStmt:            q.listUserPermissionRevocationsStmt,
+		listUserPermissionsStmt:                      q.listUserPermissionsStmt,
+		listUserPermissionsByNameStmt:                q.listUserPermissionsByNameStmt,
+		listUserRoleChangesStmt:                      q.listUserRoleChangesStmt,
+		listUserRolesStmt:                            q.listUserRolesStmt,
+		listUserRolesByRoleIDStmt:                    q.listUserRolesByRoleIDStmt,
+		listUserSettingValueUIDsStmt:                 q.listUserSettingValueUIDsStmt,
+		listUserSettingValuesStmt:                    q.listUserSettingValuesStmt,
+		listUserStatusChangesStmt:                    q.listUserStatusChangesStmt,
+		listUsernameHistoryStmt:                      q.listUsernameHistoryStmt,
+		listUsernameRulesStmt:                        q.listUsernameRulesStmt,
+		listUsersStmt:                                q.listUsersStmt,
+		listUsersOrderByCreatedAtStmt:                q.listUsersOrderByCreatedAtStmt,
+		listUsersOrderByCreatedAtDescStmt:            q.listUsersOrderByCreatedAtDescStmt,
+		listUsersOrderByIDStmt:                       q.listUsersOrderByIDStmt,
+		listUsersOrderByIDDescStmt:                   q.listUsersOrderByIDDescStmt,
+		listUsersOrderByUsernameStmt:                 q.listUsersOrderByUsernameStmt,
+		listUsersOrderByUsernameDescStmt:             q.listUsersOrderByUsernameDescStmt,
+		listVerifiedEmailsStmt:                       q.listVerifiedEmailsStmt,
+		markEmailVerifiedStmt:                        q.markEmailVerifiedStmt,
+		resetUserSettingValuesStmt:                   q.resetUserSettingValuesStmt,
+		searchUsersByUsernameStmt:                    q.searchUsersByUsernameStmt,
+		setUserProfileStmt:                           q.setUserProfileStmt,
+		setUserSettingValueStmt:                      q.setUserSettingValueStmt,
+		setUserStatusStmt:                            q.setUserStatusStmt,
+		updateGroupStmt:                              q.updateGroupStmt,
+		updateRoleStmt:                               q.updateRoleStmt,
+		updateThemePaletteStmt:                       q.updateThemePaletteStmt,
+		updateUserPasswordStmt:                       q.updateUserPasswordStmt,
+		updateUserPermissionExpiryStmt:               q.updateUserPermissionExpiryStmt,
+		updateUsernameStmt:                           q.updateUsernameStmt,
 	}
 }
@@ -0,0 +1,268 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: deletion.sql
+
+package query
+
+import (
+	"context"
+)
+
+const anonymizeGroupMemberChangesByIUID = `-- name: AnonymizeGroupMemberChangesByIUID :exec
+UPDATE user_group_member_changes SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeGroupMemberChangesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeGroupMemberChangesByIUIDStmt, anonymizeGroupMemberChangesByIUID, iuid)
+	return err
+}
+
+const anonymizeGroupMemberChangesByUID = `-- name: AnonymizeGroupMemberChangesByUID :exec
+UPDATE user_group_member_changes SET uid = -1 WHERE uid = ?
+`
+
+func (q *Queries) AnonymizeGroupMemberChangesByUID(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.anonymizeGroupMemberChangesByUIDStmt, anonymizeGroupMemberChangesByUID, uid)
+	return err
+}
+
+const anonymizeGroupMembersByIUID = `-- name: AnonymizeGroupMembersByIUID :exec
+UPDATE user_group_members SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeGroupMembersByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeGroupMembersByIUIDStmt, anonymizeGroupMembersByIUID, iuid)
+	return err
+}
+
+const anonymizeGroupsByIUID = `-- name: AnonymizeGroupsByIUID :exec
+UPDATE user_groups SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeGroupsByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeGroupsByIUIDStmt, anonymizeGroupsByIUID, iuid)
+	return err
+}
+
+const anonymizeRolesByIUID = `-- name: AnonymizeRolesByIUID :exec
+UPDATE roles SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeRolesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeRolesByIUIDStmt, anonymizeRolesByIUID, iuid)
+	return err
+}
+
+const anonymizeThemesByIUID = `-- name: AnonymizeThemesByIUID :exec
+UPDATE themes SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeThemesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeThemesByIUIDStmt, anonymizeThemesByIUID, iuid)
+	return err
+}
+
+const anonymizeUserDeletionsByIUID = `-- name: AnonymizeUserDeletionsByIUID :exec
+UPDATE user_deletions SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUserDeletionsByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserDeletionsByIUIDStmt, anonymizeUserDeletionsByIUID, iuid)
+	return err
+}
+
+const anonymizeUserPermissionGrantsByIUID = `-- name: AnonymizeUserPermissionGrantsByIUID :exec
+UPDATE user_permission_grants SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUserPermissionGrantsByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserPermissionGrantsByIUIDStmt, anonymizeUserPermissionGrantsByIUID, iuid)
+	return err
+}
+
+const anonymizeUserPermissionGrantsByUID = `-- name: AnonymizeUserPermissionGrantsByUID :exec
+UPDATE user_permission_grants SET uid = -1 WHERE uid = ?
+`
+
+func (q *Queries) AnonymizeUserPermissionGrantsByUID(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserPermissionGrantsByUIDStmt, anonymizeUserPermissionGrantsByUID, uid)
+	return err
+}
+
+const anonymizeUserPermissionRevocationsByIUID = `-- name: AnonymizeUserPermissionRevocationsByIUID :exec
+UPDATE user_permission_revocations SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUserPermissionRevocationsByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserPermissionRevocationsByIUIDStmt, anonymizeUserPermissionRevocationsByIUID, iuid)
+	return err
+}
+
+const anonymizeUserPermissionRevocationsByUID = `-- name: AnonymizeUserPermissionRevocationsByUID :exec
+UPDATE user_permission_revocations SET uid = -1 WHERE uid = ?
+`
+
+func (q *Queries) AnonymizeUserPermissionRevocationsByUID(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserPermissionRevocationsByUIDStmt, anonymizeUserPermissionRevocationsByUID, uid)
+	return err
+}
+
+const anonymizeUserRoleChangesByIUID = `-- name: AnonymizeUserRoleChangesByIUID :exec
+UPDATE user_role_changes SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUserRoleChangesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserRoleChangesByIUIDStmt, anonymizeUserRoleChangesByIUID, iuid)
+	return err
+}
+
+const anonymizeUserRoleChangesByUID = `-- name: AnonymizeUserRoleChangesByUID :exec
+UPDATE user_role_changes SET uid = -1 WHERE uid = ?
+`
+
+func (q *Queries) AnonymizeUserRoleChangesByUID(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserRoleChangesByUIDStmt, anonymizeUserRoleChangesByUID, uid)
+	return err
+}
+
+const anonymizeUserRolesByIUID = `-- name: AnonymizeUserRolesByIUID :exec
+UPDATE user_roles SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUserRolesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserRolesByIUIDStmt, anonymizeUserRolesByIUID, iuid)
+	return err
+}
+
+const anonymizeUserStatusChangesByIUID = `-- name: AnonymizeUserStatusChangesByIUID :exec
+UPDATE user_status_changes SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUserStatusChangesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserStatusChangesByIUIDStmt, anonymizeUserStatusChangesByIUID, iuid)
+	return err
+}
+
+const anonymizeUserStatusChangesByUID = `-- name: AnonymizeUserStatusChangesByUID :exec
+UPDATE user_status_changes SET uid = -1, reason = '' WHERE uid = ?
+`
+
+func (q *Queries) AnonymizeUserStatusChangesByUID(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUserStatusChangesByUIDStmt, anonymizeUserStatusChangesByUID, uid)
+	return err
+}
+
+const anonymizeUsernameHistoryByIUID = `-- name: AnonymizeUsernameHistoryByIUID :exec
+UPDATE username_history SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUsernameHistoryByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUsernameHistoryByIUIDStmt, anonymizeUsernameHistoryByIUID, iuid)
+	return err
+}
+
+const anonymizeUsernameRulesByIUID = `-- name: AnonymizeUsernameRulesByIUID :exec
+UPDATE username_rules SET iuid = -1 WHERE iuid = ?
+`
+
+func (q *Queries) AnonymizeUsernameRulesByIUID(ctx context.Context, iuid int64) error {
+	_, err := q.exec(ctx, q.anonymizeUsernameRulesByIUIDStmt, anonymizeUsernameRulesByIUID, iuid)
+	return err
+}
+
+const createUserDeletion = `-- name: CreateUserDeletion :exec
+INSERT INTO user_deletions (delete_after, request_id, iuid, uid) VALUES (?, ?, ?, ?)
+`
+
+type CreateUserDeletionParams struct {
+	DeleteAfter int64
+	RequestID   string
+	IUID        int64
+	UID         int64
+}
+
+func (q *Queries) CreateUserDeletion(ctx context.Context, arg CreateUserDeletionParams) error {
+	_, err := q.exec(ctx, q.createUserDeletionStmt, createUserDeletion,
+		arg.DeleteAfter,
+		arg.RequestID,
+		arg.IUID,
+		arg.UID,
+	)
+	return err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteUserStmt, deleteUser, id)
+	return err
+}
+
+const deleteUserDeletion = `-- name: DeleteUserDeletion :exec
+DELETE FROM user_deletions WHERE uid = ?
+`
+
+func (q *Queries) DeleteUserDeletion(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.deleteUserDeletionStmt, deleteUserDeletion, uid)
+	return err
+}
+
+const getUserDeletion = `-- name: GetUserDeletion :one
+SELECT delete_after, request_id, iuid, uid, id, created_at FROM user_deletions WHERE uid = ?
+`
+
+func (q *Queries) GetUserDeletion(ctx context.Context, uid int64) (UserDeletion, error) {
+	row := q.queryRow(ctx, q.getUserDeletionStmt, getUserDeletion, uid)
+	var i UserDeletion
+	err := row.Scan(
+		&i.DeleteAfter,
+		&i.RequestID,
+		&i.IUID,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listDueUserDeletions = `-- name: ListDueUserDeletions :many
+SELECT delete_after, request_id, iuid, uid, id, created_at FROM user_deletions WHERE delete_after <= ? ORDER BY delete_after, id LIMIT ?
+`
+
+type ListDueUserDeletionsParams struct {
+	DeleteAfter int64
+	Limit       int64
+}
+
+func (q *Queries) ListDueUserDeletions(ctx context.Context, arg ListDueUserDeletionsParams) ([]UserDeletion, error) {
+	rows, err := q.query(ctx, q.listDueUserDeletionsStmt, listDueUserDeletions, arg.DeleteAfter, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserDeletion
+	for rows.Next() {
+		var i UserDeletion
+		if err := rows.Scan(
+			&i.DeleteAfter,
+			&i.RequestID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
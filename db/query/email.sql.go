@@ -84,6 +84,24 @@ func (q *Queries) GetEmailByAddressForUser(ctx context.Context, arg GetEmailByAd
 	return i, err
 }
 
+const getPrimaryEmail = `-- name: GetPrimaryEmail :one
+SELECT address, verified, uid, id, created_at, updated_at FROM emails WHERE uid = ? ORDER BY verified DESC, created_at, id LIMIT 1
+`
+
+func (q *Queries) GetPrimaryEmail(ctx context.Context, uid int64) (Email, error) {
+	row := q.queryRow(ctx, q.getPrimaryEmailStmt, getPrimaryEmail, uid)
+	var i Email
+	err := row.Scan(
+		&i.Address,
+		&i.Verified,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getVerifiedEmailByAddress = `-- name: GetVerifiedEmailByAddress :one
 SELECT address, verified, uid, id, created_at, updated_at FROM emails WHERE address = ? AND verified = true
 `
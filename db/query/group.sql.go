@@ -0,0 +1,359 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: group.sql
+
+package query
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createGroup = `-- name: CreateGroup :execresult
+INSERT INTO user_groups (name, about, iuid) VALUES (?, ?, ?)
+`
+
+type CreateGroupParams struct {
+	Name  string
+	About string
+	IUID  int64
+}
+
+func (q *Queries) CreateGroup(ctx context.Context, arg CreateGroupParams) (sql.Result, error) {
+	return q.exec(ctx, q.createGroupStmt, createGroup, arg.Name, arg.About, arg.IUID)
+}
+
+const createGroupMember = `-- name: CreateGroupMember :exec
+INSERT INTO user_group_members (group_id, uid, iuid) VALUES (?, ?, ?)
+`
+
+type CreateGroupMemberParams struct {
+	GroupID int64
+	UID     int64
+	IUID    int64
+}
+
+func (q *Queries) CreateGroupMember(ctx context.Context, arg CreateGroupMemberParams) error {
+	_, err := q.exec(ctx, q.createGroupMemberStmt, createGroupMember, arg.GroupID, arg.UID, arg.IUID)
+	return err
+}
+
+const createGroupMemberChange = `-- name: CreateGroupMemberChange :exec
+INSERT INTO user_group_member_changes (action, group_id, group_name, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateGroupMemberChangeParams struct {
+	Action    string
+	GroupID   int64
+	GroupName string
+	RequestID string
+	IUID      int64
+	UID       int64
+}
+
+func (q *Queries) CreateGroupMemberChange(ctx context.Context, arg CreateGroupMemberChangeParams) error {
+	_, err := q.exec(ctx, q.createGroupMemberChangeStmt, createGroupMemberChange,
+		arg.Action,
+		arg.GroupID,
+		arg.GroupName,
+		arg.RequestID,
+		arg.IUID,
+		arg.UID,
+	)
+	return err
+}
+
+const createGroupPermission = `-- name: CreateGroupPermission :exec
+INSERT INTO user_group_permissions (name, group_id) VALUES (?, ?)
+`
+
+type CreateGroupPermissionParams struct {
+	Name    string
+	GroupID int64
+}
+
+func (q *Queries) CreateGroupPermission(ctx context.Context, arg CreateGroupPermissionParams) error {
+	_, err := q.exec(ctx, q.createGroupPermissionStmt, createGroupPermission, arg.Name, arg.GroupID)
+	return err
+}
+
+const deleteGroup = `-- name: DeleteGroup :exec
+DELETE FROM user_groups WHERE id = ?
+`
+
+func (q *Queries) DeleteGroup(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteGroupStmt, deleteGroup, id)
+	return err
+}
+
+const deleteGroupMember = `-- name: DeleteGroupMember :exec
+DELETE FROM user_group_members WHERE uid = ? AND group_id = ?
+`
+
+type DeleteGroupMemberParams struct {
+	UID     int64
+	GroupID int64
+}
+
+func (q *Queries) DeleteGroupMember(ctx context.Context, arg DeleteGroupMemberParams) error {
+	_, err := q.exec(ctx, q.deleteGroupMemberStmt, deleteGroupMember, arg.UID, arg.GroupID)
+	return err
+}
+
+const deleteGroupPermissions = `-- name: DeleteGroupPermissions :exec
+DELETE FROM user_group_permissions WHERE group_id = ?
+`
+
+func (q *Queries) DeleteGroupPermissions(ctx context.Context, groupID int64) error {
+	_, err := q.exec(ctx, q.deleteGroupPermissionsStmt, deleteGroupPermissions, groupID)
+	return err
+}
+
+const getGroup = `-- name: GetGroup :one
+SELECT name, about, iuid, id, created_at, updated_at FROM user_groups WHERE id = ?
+`
+
+func (q *Queries) GetGroup(ctx context.Context, id int64) (UserGroup, error) {
+	row := q.queryRow(ctx, q.getGroupStmt, getGroup, id)
+	var i UserGroup
+	err := row.Scan(
+		&i.Name,
+		&i.About,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGroupByName = `-- name: GetGroupByName :one
+SELECT name, about, iuid, id, created_at, updated_at FROM user_groups WHERE name = ?
+`
+
+func (q *Queries) GetGroupByName(ctx context.Context, name string) (UserGroup, error) {
+	row := q.queryRow(ctx, q.getGroupByNameStmt, getGroupByName, name)
+	var i UserGroup
+	err := row.Scan(
+		&i.Name,
+		&i.About,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGroupMember = `-- name: GetGroupMember :one
+SELECT group_id, iuid, uid, id, created_at FROM user_group_members WHERE uid = ? AND group_id = ?
+`
+
+type GetGroupMemberParams struct {
+	UID     int64
+	GroupID int64
+}
+
+func (q *Queries) GetGroupMember(ctx context.Context, arg GetGroupMemberParams) (UserGroupMember, error) {
+	row := q.queryRow(ctx, q.getGroupMemberStmt, getGroupMember, arg.UID, arg.GroupID)
+	var i UserGroupMember
+	err := row.Scan(
+		&i.GroupID,
+		&i.IUID,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listGroupMemberChanges = `-- name: ListGroupMemberChanges :many
+SELECT "action", group_id, group_name, request_id, iuid, uid, id, created_at FROM user_group_member_changes WHERE uid = ?1 OR iuid = ?1 ORDER BY id
+`
+
+func (q *Queries) ListGroupMemberChanges(ctx context.Context, uid int64) ([]UserGroupMemberChange, error) {
+	rows, err := q.query(ctx, q.listGroupMemberChangesStmt, listGroupMemberChanges, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserGroupMemberChange
+	for rows.Next() {
+		var i UserGroupMemberChange
+		if err := rows.Scan(
+			&i.Action,
+			&i.GroupID,
+			&i.GroupName,
+			&i.RequestID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupMembers = `-- name: ListGroupMembers :many
+SELECT group_id, iuid, uid, id, created_at FROM user_group_members WHERE group_id = ? ORDER BY uid
+`
+
+func (q *Queries) ListGroupMembers(ctx context.Context, groupID int64) ([]UserGroupMember, error) {
+	rows, err := q.query(ctx, q.listGroupMembersStmt, listGroupMembers, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserGroupMember
+	for rows.Next() {
+		var i UserGroupMember
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupPermissions = `-- name: ListGroupPermissions :many
+SELECT name, group_id, id, created_at FROM user_group_permissions WHERE group_id = ? ORDER BY name
+`
+
+func (q *Queries) ListGroupPermissions(ctx context.Context, groupID int64) ([]UserGroupPermission, error) {
+	rows, err := q.query(ctx, q.listGroupPermissionsStmt, listGroupPermissions, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserGroupPermission
+	for rows.Next() {
+		var i UserGroupPermission
+		if err := rows.Scan(
+			&i.Name,
+			&i.GroupID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroups = `-- name: ListGroups :many
+SELECT name, about, iuid, id, created_at, updated_at FROM user_groups ORDER BY name
+`
+
+func (q *Queries) ListGroups(ctx context.Context) ([]UserGroup, error) {
+	rows, err := q.query(ctx, q.listGroupsStmt, listGroups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserGroup
+	for rows.Next() {
+		var i UserGroup
+		if err := rows.Scan(
+			&i.Name,
+			&i.About,
+			&i.IUID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserGroups = `-- name: ListUserGroups :many
+SELECT user_groups.name, user_groups.about, user_groups.iuid, user_groups.id, user_groups.created_at, user_groups.updated_at FROM user_groups JOIN user_group_members ON user_group_members.group_id = user_groups.id WHERE user_group_members.uid = ? ORDER BY user_groups.name
+`
+
+func (q *Queries) ListUserGroups(ctx context.Context, uid int64) ([]UserGroup, error) {
+	rows, err := q.query(ctx, q.listUserGroupsStmt, listUserGroups, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserGroup
+	for rows.Next() {
+		var i UserGroup
+		if err := rows.Scan(
+			&i.Name,
+			&i.About,
+			&i.IUID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateGroup = `-- name: UpdateGroup :exec
+UPDATE user_groups SET name = ?, about = ?, iuid = ? WHERE id = ?
+`
+
+type UpdateGroupParams struct {
+	Name  string
+	About string
+	IUID  int64
+	ID    int64
+}
+
+func (q *Queries) UpdateGroup(ctx context.Context, arg UpdateGroupParams) error {
+	_, err := q.exec(ctx, q.updateGroupStmt, updateGroup,
+		arg.Name,
+		arg.About,
+		arg.IUID,
+		arg.ID,
+	)
+	return err
+}
@@ -17,20 +17,92 @@ type Email struct {
 	UpdatedAt sql.NullInt64
 }
 
+type Role struct {
+	Name      string
+	About     string
+	IUID      int64
+	ID        int64
+	CreatedAt sql.NullInt64
+	UpdatedAt sql.NullInt64
+}
+
+type RolePermission struct {
+	Name      string
+	RoleID    int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
+type Theme struct {
+	Name      string
+	Palette   string
+	IUID      int64
+	ID        int64
+	CreatedAt sql.NullInt64
+	UpdatedAt sql.NullInt64
+}
+
 type User struct {
-	PwHash    string
-	Username  string
+	PwHash           string
+	Username         string
+	ID               int64
+	CreatedAt        sql.NullInt64
+	UpdatedAt        sql.NullInt64
+	UsernameSkeleton sql.NullString
+}
+
+type UserDeletion struct {
+	DeleteAfter int64
+	RequestID   string
+	IUID        int64
+	UID         int64
+	ID          int64
+	CreatedAt   sql.NullInt64
+}
+
+type UserGroup struct {
+	Name      string
+	About     string
+	IUID      int64
 	ID        int64
 	CreatedAt sql.NullInt64
 	UpdatedAt sql.NullInt64
 }
 
+type UserGroupMember struct {
+	GroupID   int64
+	IUID      int64
+	UID       int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
+type UserGroupMemberChange struct {
+	Action    string
+	GroupID   int64
+	GroupName string
+	RequestID string
+	IUID      int64
+	UID       int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
+type UserGroupPermission struct {
+	Name      string
+	GroupID   int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
 type UserPermission struct {
 	Name      string
 	IUID      int64
 	UID       int64
 	ID        int64
 	CreatedAt sql.NullInt64
+	ExpiresAt sql.NullInt64
+	Scope     string
 }
 
 type UserPermissionGrant struct {
@@ -39,6 +111,9 @@ type UserPermissionGrant struct {
 	UID       int64
 	ID        int64
 	CreatedAt sql.NullInt64
+	RequestID string
+	Scope     string
+	ExpiresAt sql.NullInt64
 }
 
 type UserPermissionRevocation struct {
@@ -47,12 +122,94 @@ type UserPermissionRevocation struct {
 	UID       int64
 	ID        int64
 	CreatedAt sql.NullInt64
+	RequestID string
+	Scope     string
+}
+
+type UserProfile struct {
+	DisplayName string
+	Bio         string
+	Pronouns    string
+	AvatarURL   string
+	UID         int64
+	ID          int64
+	CreatedAt   sql.NullInt64
+	UpdatedAt   sql.NullInt64
+}
+
+type UserRole struct {
+	RoleID    int64
+	IUID      int64
+	UID       int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
+type UserRoleChange struct {
+	Action    string
+	RoleID    int64
+	RoleName  string
+	RequestID string
+	IUID      int64
+	UID       int64
+	ID        int64
+	CreatedAt sql.NullInt64
 }
 
-type UserSetting struct {
-	Theme     string
+type UserSettingValue struct {
+	Key       string
+	Value     string
 	UID       int64
 	ID        int64
 	CreatedAt sql.NullInt64
 	UpdatedAt sql.NullInt64
 }
+
+type UserStatus struct {
+	Status    string
+	Reason    string
+	Until     sql.NullInt64
+	IUID      int64
+	UID       int64
+	ID        int64
+	CreatedAt sql.NullInt64
+	UpdatedAt sql.NullInt64
+}
+
+type UserStatusChange struct {
+	Status    string
+	Reason    string
+	Until     sql.NullInt64
+	RequestID string
+	IUID      int64
+	UID       int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
+type UsernameHistory struct {
+	OldUsername         string
+	NewUsername         string
+	ReservedUntil       int64
+	RequestID           string
+	IUID                int64
+	UID                 int64
+	ID                  int64
+	CreatedAt           sql.NullInt64
+	OldUsernameSkeleton string
+}
+
+type UsernameRule struct {
+	Kind      string
+	Pattern   string
+	Reason    string
+	IUID      int64
+	ID        int64
+	CreatedAt sql.NullInt64
+}
+
+type UsersSearch struct {
+	Username    string
+	DisplayName string
+	Emails      string
+}
@@ -0,0 +1,58 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: profile.sql
+
+package query
+
+import (
+	"context"
+)
+
+const getUserProfile = `-- name: GetUserProfile :one
+SELECT display_name, bio, pronouns, avatar_url, uid, id, created_at, updated_at FROM user_profiles WHERE uid = ?
+`
+
+func (q *Queries) GetUserProfile(ctx context.Context, uid int64) (UserProfile, error) {
+	row := q.queryRow(ctx, q.getUserProfileStmt, getUserProfile, uid)
+	var i UserProfile
+	err := row.Scan(
+		&i.DisplayName,
+		&i.Bio,
+		&i.Pronouns,
+		&i.AvatarURL,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setUserProfile = `-- name: SetUserProfile :exec
+INSERT INTO user_profiles (display_name, bio, pronouns, avatar_url, uid) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(uid) DO UPDATE SET
+  display_name = excluded.display_name,
+  bio = excluded.bio,
+  pronouns = excluded.pronouns,
+  avatar_url = excluded.avatar_url
+`
+
+type SetUserProfileParams struct {
+	DisplayName string
+	Bio         string
+	Pronouns    string
+	AvatarURL   string
+	UID         int64
+}
+
+func (q *Queries) SetUserProfile(ctx context.Context, arg SetUserProfileParams) error {
+	_, err := q.exec(ctx, q.setUserProfileStmt, setUserProfile,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Pronouns,
+		arg.AvatarURL,
+		arg.UID,
+	)
+	return err
+}
@@ -0,0 +1,359 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: role.sql
+
+package query
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createRole = `-- name: CreateRole :execresult
+INSERT INTO roles (name, about, iuid) VALUES (?, ?, ?)
+`
+
+type CreateRoleParams struct {
+	Name  string
+	About string
+	IUID  int64
+}
+
+func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (sql.Result, error) {
+	return q.exec(ctx, q.createRoleStmt, createRole, arg.Name, arg.About, arg.IUID)
+}
+
+const createRolePermission = `-- name: CreateRolePermission :exec
+INSERT INTO role_permissions (name, role_id) VALUES (?, ?)
+`
+
+type CreateRolePermissionParams struct {
+	Name   string
+	RoleID int64
+}
+
+func (q *Queries) CreateRolePermission(ctx context.Context, arg CreateRolePermissionParams) error {
+	_, err := q.exec(ctx, q.createRolePermissionStmt, createRolePermission, arg.Name, arg.RoleID)
+	return err
+}
+
+const createUserRole = `-- name: CreateUserRole :exec
+INSERT INTO user_roles (role_id, uid, iuid) VALUES (?, ?, ?)
+`
+
+type CreateUserRoleParams struct {
+	RoleID int64
+	UID    int64
+	IUID   int64
+}
+
+func (q *Queries) CreateUserRole(ctx context.Context, arg CreateUserRoleParams) error {
+	_, err := q.exec(ctx, q.createUserRoleStmt, createUserRole, arg.RoleID, arg.UID, arg.IUID)
+	return err
+}
+
+const createUserRoleChange = `-- name: CreateUserRoleChange :exec
+INSERT INTO user_role_changes (action, role_id, role_name, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateUserRoleChangeParams struct {
+	Action    string
+	RoleID    int64
+	RoleName  string
+	RequestID string
+	IUID      int64
+	UID       int64
+}
+
+func (q *Queries) CreateUserRoleChange(ctx context.Context, arg CreateUserRoleChangeParams) error {
+	_, err := q.exec(ctx, q.createUserRoleChangeStmt, createUserRoleChange,
+		arg.Action,
+		arg.RoleID,
+		arg.RoleName,
+		arg.RequestID,
+		arg.IUID,
+		arg.UID,
+	)
+	return err
+}
+
+const deleteRole = `-- name: DeleteRole :exec
+DELETE FROM roles WHERE id = ?
+`
+
+func (q *Queries) DeleteRole(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteRoleStmt, deleteRole, id)
+	return err
+}
+
+const deleteRolePermissions = `-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions WHERE role_id = ?
+`
+
+func (q *Queries) DeleteRolePermissions(ctx context.Context, roleID int64) error {
+	_, err := q.exec(ctx, q.deleteRolePermissionsStmt, deleteRolePermissions, roleID)
+	return err
+}
+
+const deleteUserRole = `-- name: DeleteUserRole :exec
+DELETE FROM user_roles WHERE uid = ? AND role_id = ?
+`
+
+type DeleteUserRoleParams struct {
+	UID    int64
+	RoleID int64
+}
+
+func (q *Queries) DeleteUserRole(ctx context.Context, arg DeleteUserRoleParams) error {
+	_, err := q.exec(ctx, q.deleteUserRoleStmt, deleteUserRole, arg.UID, arg.RoleID)
+	return err
+}
+
+const getRole = `-- name: GetRole :one
+SELECT name, about, iuid, id, created_at, updated_at FROM roles WHERE id = ?
+`
+
+func (q *Queries) GetRole(ctx context.Context, id int64) (Role, error) {
+	row := q.queryRow(ctx, q.getRoleStmt, getRole, id)
+	var i Role
+	err := row.Scan(
+		&i.Name,
+		&i.About,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getRoleByName = `-- name: GetRoleByName :one
+SELECT name, about, iuid, id, created_at, updated_at FROM roles WHERE name = ?
+`
+
+func (q *Queries) GetRoleByName(ctx context.Context, name string) (Role, error) {
+	row := q.queryRow(ctx, q.getRoleByNameStmt, getRoleByName, name)
+	var i Role
+	err := row.Scan(
+		&i.Name,
+		&i.About,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserRole = `-- name: GetUserRole :one
+SELECT role_id, iuid, uid, id, created_at FROM user_roles WHERE uid = ? AND role_id = ?
+`
+
+type GetUserRoleParams struct {
+	UID    int64
+	RoleID int64
+}
+
+func (q *Queries) GetUserRole(ctx context.Context, arg GetUserRoleParams) (UserRole, error) {
+	row := q.queryRow(ctx, q.getUserRoleStmt, getUserRole, arg.UID, arg.RoleID)
+	var i UserRole
+	err := row.Scan(
+		&i.RoleID,
+		&i.IUID,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listRolePermissions = `-- name: ListRolePermissions :many
+SELECT name, role_id, id, created_at FROM role_permissions WHERE role_id = ? ORDER BY name
+`
+
+func (q *Queries) ListRolePermissions(ctx context.Context, roleID int64) ([]RolePermission, error) {
+	rows, err := q.query(ctx, q.listRolePermissionsStmt, listRolePermissions, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RolePermission
+	for rows.Next() {
+		var i RolePermission
+		if err := rows.Scan(
+			&i.Name,
+			&i.RoleID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoles = `-- name: ListRoles :many
+SELECT name, about, iuid, id, created_at, updated_at FROM roles ORDER BY name
+`
+
+func (q *Queries) ListRoles(ctx context.Context) ([]Role, error) {
+	rows, err := q.query(ctx, q.listRolesStmt, listRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Role
+	for rows.Next() {
+		var i Role
+		if err := rows.Scan(
+			&i.Name,
+			&i.About,
+			&i.IUID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRoleChanges = `-- name: ListUserRoleChanges :many
+SELECT "action", role_id, role_name, request_id, iuid, uid, id, created_at FROM user_role_changes WHERE uid = ?1 OR iuid = ?1 ORDER BY id
+`
+
+func (q *Queries) ListUserRoleChanges(ctx context.Context, uid int64) ([]UserRoleChange, error) {
+	rows, err := q.query(ctx, q.listUserRoleChangesStmt, listUserRoleChanges, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRoleChange
+	for rows.Next() {
+		var i UserRoleChange
+		if err := rows.Scan(
+			&i.Action,
+			&i.RoleID,
+			&i.RoleName,
+			&i.RequestID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRoles = `-- name: ListUserRoles :many
+SELECT roles.name, roles.about, roles.iuid, roles.id, roles.created_at, roles.updated_at FROM roles JOIN user_roles ON user_roles.role_id = roles.id WHERE user_roles.uid = ? ORDER BY roles.name
+`
+
+func (q *Queries) ListUserRoles(ctx context.Context, uid int64) ([]Role, error) {
+	rows, err := q.query(ctx, q.listUserRolesStmt, listUserRoles, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Role
+	for rows.Next() {
+		var i Role
+		if err := rows.Scan(
+			&i.Name,
+			&i.About,
+			&i.IUID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRolesByRoleID = `-- name: ListUserRolesByRoleID :many
+SELECT role_id, iuid, uid, id, created_at FROM user_roles WHERE role_id = ?
+`
+
+func (q *Queries) ListUserRolesByRoleID(ctx context.Context, roleID int64) ([]UserRole, error) {
+	rows, err := q.query(ctx, q.listUserRolesByRoleIDStmt, listUserRolesByRoleID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRole
+	for rows.Next() {
+		var i UserRole
+		if err := rows.Scan(
+			&i.RoleID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateRole = `-- name: UpdateRole :exec
+UPDATE roles SET name = ?, about = ?, iuid = ? WHERE id = ?
+`
+
+type UpdateRoleParams struct {
+	Name  string
+	About string
+	IUID  int64
+	ID    int64
+}
+
+func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) error {
+	_, err := q.exec(ctx, q.updateRoleStmt, updateRole,
+		arg.Name,
+		arg.About,
+		arg.IUID,
+		arg.ID,
+	)
+	return err
+}
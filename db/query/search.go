@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+)
+
+// sqlc can't parse a MATCH against an FTS5 table itself, only against one of its columns,
+// which FTS5 treats as a column filter. This query is written by hand to fill the gap.
+const searchUsers = `-- name: SearchUsers :many
+SELECT users.pw_hash, users.username, users.id, users.created_at, users.updated_at, users.username_skeleton FROM users_search
+JOIN users ON users.id = users_search.rowid
+WHERE users_search MATCH ?1
+ORDER BY bm25(users_search, 10.0, 5.0, 1.0), users.id
+LIMIT ?2 OFFSET ?3
+`
+
+type SearchUsersParams struct {
+	Match  string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.query(ctx, nil, searchUsers, arg.Match, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,120 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: settings.sql
+
+package query
+
+import (
+	"context"
+)
+
+const deleteUserSettingValue = `-- name: DeleteUserSettingValue :exec
+DELETE FROM user_setting_values WHERE uid = ? AND key = ?
+`
+
+type DeleteUserSettingValueParams struct {
+	UID int64
+	Key string
+}
+
+func (q *Queries) DeleteUserSettingValue(ctx context.Context, arg DeleteUserSettingValueParams) error {
+	_, err := q.exec(ctx, q.deleteUserSettingValueStmt, deleteUserSettingValue, arg.UID, arg.Key)
+	return err
+}
+
+const listUserSettingValueUIDs = `-- name: ListUserSettingValueUIDs :many
+SELECT uid FROM user_setting_values WHERE key = ? AND value = ?
+`
+
+type ListUserSettingValueUIDsParams struct {
+	Key   string
+	Value string
+}
+
+func (q *Queries) ListUserSettingValueUIDs(ctx context.Context, arg ListUserSettingValueUIDsParams) ([]int64, error) {
+	rows, err := q.query(ctx, q.listUserSettingValueUIDsStmt, listUserSettingValueUIDs, arg.Key, arg.Value)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var uid int64
+		if err := rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		items = append(items, uid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserSettingValues = `-- name: ListUserSettingValues :many
+SELECT "key", value, uid, id, created_at, updated_at FROM user_setting_values WHERE uid = ? ORDER BY key
+`
+
+func (q *Queries) ListUserSettingValues(ctx context.Context, uid int64) ([]UserSettingValue, error) {
+	rows, err := q.query(ctx, q.listUserSettingValuesStmt, listUserSettingValues, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserSettingValue
+	for rows.Next() {
+		var i UserSettingValue
+		if err := rows.Scan(
+			&i.Key,
+			&i.Value,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resetUserSettingValues = `-- name: ResetUserSettingValues :exec
+DELETE FROM user_setting_values WHERE key = ? AND value = ?
+`
+
+type ResetUserSettingValuesParams struct {
+	Key   string
+	Value string
+}
+
+func (q *Queries) ResetUserSettingValues(ctx context.Context, arg ResetUserSettingValuesParams) error {
+	_, err := q.exec(ctx, q.resetUserSettingValuesStmt, resetUserSettingValues, arg.Key, arg.Value)
+	return err
+}
+
+const setUserSettingValue = `-- name: SetUserSettingValue :exec
+INSERT INTO user_setting_values (key, value, uid) VALUES (?, ?, ?)
+ON CONFLICT(uid, key) DO UPDATE SET value = excluded.value
+`
+
+type SetUserSettingValueParams struct {
+	Key   string
+	Value string
+	UID   int64
+}
+
+func (q *Queries) SetUserSettingValue(ctx context.Context, arg SetUserSettingValueParams) error {
+	_, err := q.exec(ctx, q.setUserSettingValueStmt, setUserSettingValue, arg.Key, arg.Value, arg.UID)
+	return err
+}
@@ -0,0 +1,125 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: status.sql
+
+package query
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createUserStatusChange = `-- name: CreateUserStatusChange :exec
+INSERT INTO user_status_changes (status, reason, until, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateUserStatusChangeParams struct {
+	Status    string
+	Reason    string
+	Until     sql.NullInt64
+	RequestID string
+	IUID      int64
+	UID       int64
+}
+
+func (q *Queries) CreateUserStatusChange(ctx context.Context, arg CreateUserStatusChangeParams) error {
+	_, err := q.exec(ctx, q.createUserStatusChangeStmt, createUserStatusChange,
+		arg.Status,
+		arg.Reason,
+		arg.Until,
+		arg.RequestID,
+		arg.IUID,
+		arg.UID,
+	)
+	return err
+}
+
+const deleteUserStatus = `-- name: DeleteUserStatus :exec
+DELETE FROM user_statuses WHERE uid = ?
+`
+
+func (q *Queries) DeleteUserStatus(ctx context.Context, uid int64) error {
+	_, err := q.exec(ctx, q.deleteUserStatusStmt, deleteUserStatus, uid)
+	return err
+}
+
+const getUserStatus = `-- name: GetUserStatus :one
+SELECT status, reason, until, iuid, uid, id, created_at, updated_at FROM user_statuses WHERE uid = ?
+`
+
+func (q *Queries) GetUserStatus(ctx context.Context, uid int64) (UserStatus, error) {
+	row := q.queryRow(ctx, q.getUserStatusStmt, getUserStatus, uid)
+	var i UserStatus
+	err := row.Scan(
+		&i.Status,
+		&i.Reason,
+		&i.Until,
+		&i.IUID,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listUserStatusChanges = `-- name: ListUserStatusChanges :many
+SELECT status, reason, until, request_id, iuid, uid, id, created_at FROM user_status_changes WHERE uid = ?1 OR iuid = ?1 ORDER BY id
+`
+
+func (q *Queries) ListUserStatusChanges(ctx context.Context, uid int64) ([]UserStatusChange, error) {
+	rows, err := q.query(ctx, q.listUserStatusChangesStmt, listUserStatusChanges, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserStatusChange
+	for rows.Next() {
+		var i UserStatusChange
+		if err := rows.Scan(
+			&i.Status,
+			&i.Reason,
+			&i.Until,
+			&i.RequestID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserStatus = `-- name: SetUserStatus :exec
+INSERT INTO user_statuses (status, reason, until, iuid, uid) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (uid) DO UPDATE SET status = excluded.status, reason = excluded.reason, until = excluded.until, iuid = excluded.iuid
+`
+
+type SetUserStatusParams struct {
+	Status string
+	Reason string
+	Until  sql.NullInt64
+	IUID   int64
+	UID    int64
+}
+
+func (q *Queries) SetUserStatus(ctx context.Context, arg SetUserStatusParams) error {
+	_, err := q.exec(ctx, q.setUserStatusStmt, setUserStatus,
+		arg.Status,
+		arg.Reason,
+		arg.Until,
+		arg.IUID,
+		arg.UID,
+	)
+	return err
+}
@@ -0,0 +1,101 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: theme.sql
+
+package query
+
+import (
+	"context"
+)
+
+const createTheme = `-- name: CreateTheme :exec
+INSERT INTO themes (name, palette, iuid) VALUES (?, ?, ?)
+`
+
+type CreateThemeParams struct {
+	Name    string
+	Palette string
+	IUID    int64
+}
+
+func (q *Queries) CreateTheme(ctx context.Context, arg CreateThemeParams) error {
+	_, err := q.exec(ctx, q.createThemeStmt, createTheme, arg.Name, arg.Palette, arg.IUID)
+	return err
+}
+
+const deleteTheme = `-- name: DeleteTheme :exec
+DELETE FROM themes WHERE name = ?
+`
+
+func (q *Queries) DeleteTheme(ctx context.Context, name string) error {
+	_, err := q.exec(ctx, q.deleteThemeStmt, deleteTheme, name)
+	return err
+}
+
+const getTheme = `-- name: GetTheme :one
+SELECT name, palette, iuid, id, created_at, updated_at FROM themes WHERE name = ?
+`
+
+func (q *Queries) GetTheme(ctx context.Context, name string) (Theme, error) {
+	row := q.queryRow(ctx, q.getThemeStmt, getTheme, name)
+	var i Theme
+	err := row.Scan(
+		&i.Name,
+		&i.Palette,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listThemes = `-- name: ListThemes :many
+SELECT name, palette, iuid, id, created_at, updated_at FROM themes ORDER BY name
+`
+
+func (q *Queries) ListThemes(ctx context.Context) ([]Theme, error) {
+	rows, err := q.query(ctx, q.listThemesStmt, listThemes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Theme
+	for rows.Next() {
+		var i Theme
+		if err := rows.Scan(
+			&i.Name,
+			&i.Palette,
+			&i.IUID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateThemePalette = `-- name: UpdateThemePalette :exec
+UPDATE themes SET palette = ?, iuid = ? WHERE name = ?
+`
+
+type UpdateThemePaletteParams struct {
+	Palette string
+	IUID    int64
+	Name    string
+}
+
+func (q *Queries) UpdateThemePalette(ctx context.Context, arg UpdateThemePaletteParams) error {
+	_, err := q.exec(ctx, q.updateThemePaletteStmt, updateThemePalette, arg.Palette, arg.IUID, arg.Name)
+	return err
+}
@@ -11,73 +11,86 @@ import (
 )
 
 const createUser = `-- name: CreateUser :execresult
-INSERT INTO users (username, pw_hash) VALUES (?, ?)
+INSERT INTO users (username, username_skeleton, pw_hash) VALUES (?, ?, ?)
 `
 
 type CreateUserParams struct {
-	Username string
-	PwHash   string
+	Username         string
+	UsernameSkeleton sql.NullString
+	PwHash           string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error) {
-	return q.exec(ctx, q.createUserStmt, createUser, arg.Username, arg.PwHash)
+	return q.exec(ctx, q.createUserStmt, createUser, arg.Username, arg.UsernameSkeleton, arg.PwHash)
 }
 
 const createUserPermission = `-- name: CreateUserPermission :execresult
-INSERT INTO user_permissions (name, uid, iuid) VALUES (?, ?, ?)
+INSERT INTO user_permissions (name, uid, iuid, expires_at, scope) VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateUserPermissionParams struct {
-	Name string
-	UID  int64
-	IUID int64
+	Name      string
+	UID       int64
+	IUID      int64
+	ExpiresAt sql.NullInt64
+	Scope     string
 }
 
 func (q *Queries) CreateUserPermission(ctx context.Context, arg CreateUserPermissionParams) (sql.Result, error) {
-	return q.exec(ctx, q.createUserPermissionStmt, createUserPermission, arg.Name, arg.UID, arg.IUID)
+	return q.exec(ctx, q.createUserPermissionStmt, createUserPermission,
+		arg.Name,
+		arg.UID,
+		arg.IUID,
+		arg.ExpiresAt,
+		arg.Scope,
+	)
 }
 
 const createUserPermissionGrant = `-- name: CreateUserPermissionGrant :exec
-INSERT INTO user_permission_grants (name, uid, iuid) VALUES (?, ?, ?)
+INSERT INTO user_permission_grants (name, uid, iuid, request_id, scope, expires_at) VALUES (?, ?, ?, ?, ?, ?)
 `
 
 type CreateUserPermissionGrantParams struct {
-	Name string
-	UID  int64
-	IUID int64
+	Name      string
+	UID       int64
+	IUID      int64
+	RequestID string
+	Scope     string
+	ExpiresAt sql.NullInt64
 }
 
 func (q *Queries) CreateUserPermissionGrant(ctx context.Context, arg CreateUserPermissionGrantParams) error {
-	_, err := q.exec(ctx, q.createUserPermissionGrantStmt, createUserPermissionGrant, arg.Name, arg.UID, arg.IUID)
+	_, err := q.exec(ctx, q.createUserPermissionGrantStmt, createUserPermissionGrant,
+		arg.Name,
+		arg.UID,
+		arg.IUID,
+		arg.RequestID,
+		arg.Scope,
+		arg.ExpiresAt,
+	)
 	return err
 }
 
 const createUserPermissionRevocation = `-- name: CreateUserPermissionRevocation :exec
-INSERT INTO user_permission_revocations (name, uid, iuid) VALUES (?, ?, ?)
+INSERT INTO user_permission_revocations (name, uid, iuid, request_id, scope) VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateUserPermissionRevocationParams struct {
-	Name string
-	UID  int64
-	IUID int64
+	Name      string
+	UID       int64
+	IUID      int64
+	RequestID string
+	Scope     string
 }
 
 func (q *Queries) CreateUserPermissionRevocation(ctx context.Context, arg CreateUserPermissionRevocationParams) error {
-	_, err := q.exec(ctx, q.createUserPermissionRevocationStmt, createUserPermissionRevocation, arg.Name, arg.UID, arg.IUID)
-	return err
-}
-
-const createUserSettings = `-- name: CreateUserSettings :exec
-INSERT INTO user_settings (theme, uid) VALUES (?, ?)
-`
-
-type CreateUserSettingsParams struct {
-	Theme string
-	UID   int64
-}
-
-func (q *Queries) CreateUserSettings(ctx context.Context, arg CreateUserSettingsParams) error {
-	_, err := q.exec(ctx, q.createUserSettingsStmt, createUserSettings, arg.Theme, arg.UID)
+	_, err := q.exec(ctx, q.createUserPermissionRevocationStmt, createUserPermissionRevocation,
+		arg.Name,
+		arg.UID,
+		arg.IUID,
+		arg.RequestID,
+		arg.Scope,
+	)
 	return err
 }
 
@@ -111,7 +124,7 @@ func (q *Queries) GetUSerUsernameById(ctx context.Context, id int64) (string, er
 }
 
 const getUser = `-- name: GetUser :one
-SELECT pw_hash, username, id, created_at, updated_at FROM users WHERE id = ?
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users WHERE id = ?
 `
 
 func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
@@ -123,12 +136,13 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsernameSkeleton,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT pw_hash, username, id, created_at, updated_at FROM users WHERE username = ?
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users WHERE username = ?
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -140,21 +154,23 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsernameSkeleton,
 	)
 	return i, err
 }
 
 const getUserPermissionByName = `-- name: GetUserPermissionByName :one
-SELECT name, iuid, uid, id, created_at FROM user_permissions WHERE name = ? AND uid = ?
+SELECT name, iuid, uid, id, created_at, expires_at, scope FROM user_permissions WHERE name = ? AND uid = ? AND scope = ?
 `
 
 type GetUserPermissionByNameParams struct {
-	Name string
-	UID  int64
+	Name  string
+	UID   int64
+	Scope string
 }
 
 func (q *Queries) GetUserPermissionByName(ctx context.Context, arg GetUserPermissionByNameParams) (UserPermission, error) {
-	row := q.queryRow(ctx, q.getUserPermissionByNameStmt, getUserPermissionByName, arg.Name, arg.UID)
+	row := q.queryRow(ctx, q.getUserPermissionByNameStmt, getUserPermissionByName, arg.Name, arg.UID, arg.Scope)
 	var i UserPermission
 	err := row.Scan(
 		&i.Name,
@@ -162,23 +178,8 @@ func (q *Queries) GetUserPermissionByName(ctx context.Context, arg GetUserPermis
 		&i.UID,
 		&i.ID,
 		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const getUserSettings = `-- name: GetUserSettings :one
-SELECT theme, uid, id, created_at, updated_at FROM user_settings WHERE uid = ?
-`
-
-func (q *Queries) GetUserSettings(ctx context.Context, uid int64) (UserSetting, error) {
-	row := q.queryRow(ctx, q.getUserSettingsStmt, getUserSettings, uid)
-	var i UserSetting
-	err := row.Scan(
-		&i.Theme,
-		&i.UID,
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scope,
 	)
 	return i, err
 }
@@ -194,12 +195,128 @@ func (q *Queries) GetUserUsername(ctx context.Context, id int64) (string, error)
 	return username, err
 }
 
+const listExpiredUserPermissions = `-- name: ListExpiredUserPermissions :many
+SELECT name, iuid, uid, id, created_at, expires_at, scope FROM user_permissions WHERE expires_at <= CAST(?1 AS INTEGER) ORDER BY expires_at LIMIT ?2
+`
+
+type ListExpiredUserPermissionsParams struct {
+	Now   int64
+	Limit int64
+}
+
+func (q *Queries) ListExpiredUserPermissions(ctx context.Context, arg ListExpiredUserPermissionsParams) ([]UserPermission, error) {
+	rows, err := q.query(ctx, q.listExpiredUserPermissionsStmt, listExpiredUserPermissions, arg.Now, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserPermission
+	for rows.Next() {
+		var i UserPermission
+		if err := rows.Scan(
+			&i.Name,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.Scope,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserPermissionGrants = `-- name: ListUserPermissionGrants :many
+SELECT name, iuid, uid, id, created_at, request_id, scope, expires_at FROM user_permission_grants WHERE uid = ?1 OR iuid = ?1 ORDER BY id
+`
+
+func (q *Queries) ListUserPermissionGrants(ctx context.Context, uid int64) ([]UserPermissionGrant, error) {
+	rows, err := q.query(ctx, q.listUserPermissionGrantsStmt, listUserPermissionGrants, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserPermissionGrant
+	for rows.Next() {
+		var i UserPermissionGrant
+		if err := rows.Scan(
+			&i.Name,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.RequestID,
+			&i.Scope,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserPermissionRevocations = `-- name: ListUserPermissionRevocations :many
+SELECT name, iuid, uid, id, created_at, request_id, scope FROM user_permission_revocations WHERE uid = ?1 OR iuid = ?1 ORDER BY id
+`
+
+func (q *Queries) ListUserPermissionRevocations(ctx context.Context, uid int64) ([]UserPermissionRevocation, error) {
+	rows, err := q.query(ctx, q.listUserPermissionRevocationsStmt, listUserPermissionRevocations, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserPermissionRevocation
+	for rows.Next() {
+		var i UserPermissionRevocation
+		if err := rows.Scan(
+			&i.Name,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.RequestID,
+			&i.Scope,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUserPermissions = `-- name: ListUserPermissions :many
-SELECT name, iuid, uid, id, created_at FROM user_permissions WHERE uid = ?
+SELECT name, iuid, uid, id, created_at, expires_at, scope FROM user_permissions WHERE uid = ? AND (expires_at IS NULL OR expires_at > CAST(?2 AS INTEGER))
 `
 
-func (q *Queries) ListUserPermissions(ctx context.Context, uid int64) ([]UserPermission, error) {
-	rows, err := q.query(ctx, q.listUserPermissionsStmt, listUserPermissions, uid)
+type ListUserPermissionsParams struct {
+	UID int64
+	Now int64
+}
+
+func (q *Queries) ListUserPermissions(ctx context.Context, arg ListUserPermissionsParams) ([]UserPermission, error) {
+	rows, err := q.query(ctx, q.listUserPermissionsStmt, listUserPermissions, arg.UID, arg.Now)
 	if err != nil {
 		return nil, err
 	}
@@ -213,6 +330,8 @@ func (q *Queries) ListUserPermissions(ctx context.Context, uid int64) ([]UserPer
 			&i.UID,
 			&i.ID,
 			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.Scope,
 		); err != nil {
 			return nil, err
 		}
@@ -228,7 +347,7 @@ func (q *Queries) ListUserPermissions(ctx context.Context, uid int64) ([]UserPer
 }
 
 const listUserPermissionsByName = `-- name: ListUserPermissionsByName :many
-SELECT name, iuid, uid, id, created_at FROM user_permissions WHERE name = ?
+SELECT name, iuid, uid, id, created_at, expires_at, scope FROM user_permissions WHERE name = ?
 `
 
 func (q *Queries) ListUserPermissionsByName(ctx context.Context, name string) ([]UserPermission, error) {
@@ -246,6 +365,8 @@ func (q *Queries) ListUserPermissionsByName(ctx context.Context, name string) ([
 			&i.UID,
 			&i.ID,
 			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.Scope,
 		); err != nil {
 			return nil, err
 		}
@@ -261,7 +382,7 @@ func (q *Queries) ListUserPermissionsByName(ctx context.Context, name string) ([
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT pw_hash, username, id, created_at, updated_at FROM users
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
 `
 
 func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
@@ -279,6 +400,357 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersOrderByCreatedAt = `-- name: ListUsersOrderByCreatedAt :many
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR users.username LIKE ?1 ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = ?2) OR ?2 IS NULL)
+  AND (CAST(?3 AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = ?3)
+  AND (users.created_at > ?4 OR ?4 IS NULL)
+  AND (users.created_at > ?5 OR (users.created_at = ?5 AND users.id > ?6) OR ?5 IS NULL)
+ORDER BY created_at, id
+LIMIT ?7
+`
+
+type ListUsersOrderByCreatedAtParams struct {
+	UsernamePattern sql.NullString
+	Permission      sql.NullString
+	Status          sql.NullString
+	CreatedAfter    sql.NullInt64
+	AfterCreatedAt  sql.NullInt64
+	AfterID         sql.NullInt64
+	Limit           int64
+}
+
+func (q *Queries) ListUsersOrderByCreatedAt(ctx context.Context, arg ListUsersOrderByCreatedAtParams) ([]User, error) {
+	rows, err := q.query(ctx, q.listUsersOrderByCreatedAtStmt, listUsersOrderByCreatedAt,
+		arg.UsernamePattern,
+		arg.Permission,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersOrderByCreatedAtDesc = `-- name: ListUsersOrderByCreatedAtDesc :many
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR users.username LIKE ?1 ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = ?2) OR ?2 IS NULL)
+  AND (CAST(?3 AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = ?3)
+  AND (users.created_at > ?4 OR ?4 IS NULL)
+  AND (users.created_at < ?5 OR (users.created_at = ?5 AND users.id < ?6) OR ?5 IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT ?7
+`
+
+type ListUsersOrderByCreatedAtDescParams struct {
+	UsernamePattern sql.NullString
+	Permission      sql.NullString
+	Status          sql.NullString
+	CreatedAfter    sql.NullInt64
+	AfterCreatedAt  sql.NullInt64
+	AfterID         sql.NullInt64
+	Limit           int64
+}
+
+func (q *Queries) ListUsersOrderByCreatedAtDesc(ctx context.Context, arg ListUsersOrderByCreatedAtDescParams) ([]User, error) {
+	rows, err := q.query(ctx, q.listUsersOrderByCreatedAtDescStmt, listUsersOrderByCreatedAtDesc,
+		arg.UsernamePattern,
+		arg.Permission,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersOrderByID = `-- name: ListUsersOrderByID :many
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR users.username LIKE ?1 ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = ?2) OR ?2 IS NULL)
+  AND (CAST(?3 AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = ?3)
+  AND (users.created_at > ?4 OR ?4 IS NULL)
+  AND (users.id > ?5 OR ?5 IS NULL)
+ORDER BY id
+LIMIT ?6
+`
+
+type ListUsersOrderByIDParams struct {
+	UsernamePattern sql.NullString
+	Permission      sql.NullString
+	Status          sql.NullString
+	CreatedAfter    sql.NullInt64
+	AfterID         sql.NullInt64
+	Limit           int64
+}
+
+func (q *Queries) ListUsersOrderByID(ctx context.Context, arg ListUsersOrderByIDParams) ([]User, error) {
+	rows, err := q.query(ctx, q.listUsersOrderByIDStmt, listUsersOrderByID,
+		arg.UsernamePattern,
+		arg.Permission,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersOrderByIDDesc = `-- name: ListUsersOrderByIDDesc :many
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR users.username LIKE ?1 ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = ?2) OR ?2 IS NULL)
+  AND (CAST(?3 AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = ?3)
+  AND (users.created_at > ?4 OR ?4 IS NULL)
+  AND (users.id < ?5 OR ?5 IS NULL)
+ORDER BY id DESC
+LIMIT ?6
+`
+
+type ListUsersOrderByIDDescParams struct {
+	UsernamePattern sql.NullString
+	Permission      sql.NullString
+	Status          sql.NullString
+	CreatedAfter    sql.NullInt64
+	AfterID         sql.NullInt64
+	Limit           int64
+}
+
+func (q *Queries) ListUsersOrderByIDDesc(ctx context.Context, arg ListUsersOrderByIDDescParams) ([]User, error) {
+	rows, err := q.query(ctx, q.listUsersOrderByIDDescStmt, listUsersOrderByIDDesc,
+		arg.UsernamePattern,
+		arg.Permission,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersOrderByUsername = `-- name: ListUsersOrderByUsername :many
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR users.username LIKE ?1 ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = ?2) OR ?2 IS NULL)
+  AND (CAST(?3 AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = ?3)
+  AND (users.created_at > ?4 OR ?4 IS NULL)
+  AND (users.username > ?5 OR (users.username = ?5 AND users.id > ?6) OR ?5 IS NULL)
+ORDER BY username, id
+LIMIT ?7
+`
+
+type ListUsersOrderByUsernameParams struct {
+	UsernamePattern sql.NullString
+	Permission      sql.NullString
+	Status          sql.NullString
+	CreatedAfter    sql.NullInt64
+	AfterUsername   sql.NullString
+	AfterID         sql.NullInt64
+	Limit           int64
+}
+
+func (q *Queries) ListUsersOrderByUsername(ctx context.Context, arg ListUsersOrderByUsernameParams) ([]User, error) {
+	rows, err := q.query(ctx, q.listUsersOrderByUsernameStmt, listUsersOrderByUsername,
+		arg.UsernamePattern,
+		arg.Permission,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.AfterUsername,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersOrderByUsernameDesc = `-- name: ListUsersOrderByUsernameDesc :many
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR users.username LIKE ?1 ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = ?2) OR ?2 IS NULL)
+  AND (CAST(?3 AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = ?3)
+  AND (users.created_at > ?4 OR ?4 IS NULL)
+  AND (users.username < ?5 OR (users.username = ?5 AND users.id < ?6) OR ?5 IS NULL)
+ORDER BY username DESC, id DESC
+LIMIT ?7
+`
+
+type ListUsersOrderByUsernameDescParams struct {
+	UsernamePattern sql.NullString
+	Permission      sql.NullString
+	Status          sql.NullString
+	CreatedAfter    sql.NullInt64
+	AfterUsername   sql.NullString
+	AfterID         sql.NullInt64
+	Limit           int64
+}
+
+func (q *Queries) ListUsersOrderByUsernameDesc(ctx context.Context, arg ListUsersOrderByUsernameDescParams) ([]User, error) {
+	rows, err := q.query(ctx, q.listUsersOrderByUsernameDescStmt, listUsersOrderByUsernameDesc,
+		arg.UsernamePattern,
+		arg.Permission,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.AfterUsername,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.PwHash,
+			&i.Username,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsernameSkeleton,
 		); err != nil {
 			return nil, err
 		}
@@ -294,7 +766,7 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 }
 
 const searchUsersByUsername = `-- name: SearchUsersByUsername :many
-SELECT pw_hash, username, id, created_at, updated_at FROM users WHERE username LIKE ?
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users WHERE username LIKE ?
 `
 
 func (q *Queries) SearchUsersByUsername(ctx context.Context, username string) ([]User, error) {
@@ -312,6 +784,7 @@ func (q *Queries) SearchUsersByUsername(ctx context.Context, username string) ([
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsernameSkeleton,
 		); err != nil {
 			return nil, err
 		}
@@ -339,16 +812,17 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 	return q.exec(ctx, q.updateUserPasswordStmt, updateUserPassword, arg.PwHash, arg.ID)
 }
 
-const updateUserSettingsTheme = `-- name: UpdateUserSettingsTheme :exec
-UPDATE user_settings SET theme = ? WHERE uid = ?
+const updateUserPermissionExpiry = `-- name: UpdateUserPermissionExpiry :exec
+UPDATE user_permissions SET expires_at = ?, iuid = ? WHERE id = ?
 `
 
-type UpdateUserSettingsThemeParams struct {
-	Theme string
-	UID   int64
+type UpdateUserPermissionExpiryParams struct {
+	ExpiresAt sql.NullInt64
+	IUID      int64
+	ID        int64
 }
 
-func (q *Queries) UpdateUserSettingsTheme(ctx context.Context, arg UpdateUserSettingsThemeParams) error {
-	_, err := q.exec(ctx, q.updateUserSettingsThemeStmt, updateUserSettingsTheme, arg.Theme, arg.UID)
+func (q *Queries) UpdateUserPermissionExpiry(ctx context.Context, arg UpdateUserPermissionExpiryParams) error {
+	_, err := q.exec(ctx, q.updateUserPermissionExpiryStmt, updateUserPermissionExpiry, arg.ExpiresAt, arg.IUID, arg.ID)
 	return err
 }
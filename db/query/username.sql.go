@@ -0,0 +1,262 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: username.sql
+
+package query
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createUsernameHistory = `-- name: CreateUsernameHistory :exec
+INSERT INTO username_history (old_username, old_username_skeleton, new_username, reserved_until, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateUsernameHistoryParams struct {
+	OldUsername         string
+	OldUsernameSkeleton string
+	NewUsername         string
+	ReservedUntil       int64
+	RequestID           string
+	IUID                int64
+	UID                 int64
+}
+
+func (q *Queries) CreateUsernameHistory(ctx context.Context, arg CreateUsernameHistoryParams) error {
+	_, err := q.exec(ctx, q.createUsernameHistoryStmt, createUsernameHistory,
+		arg.OldUsername,
+		arg.OldUsernameSkeleton,
+		arg.NewUsername,
+		arg.ReservedUntil,
+		arg.RequestID,
+		arg.IUID,
+		arg.UID,
+	)
+	return err
+}
+
+const createUsernameRule = `-- name: CreateUsernameRule :execresult
+INSERT INTO username_rules (kind, pattern, reason, iuid) VALUES (?, ?, ?, ?)
+`
+
+type CreateUsernameRuleParams struct {
+	Kind    string
+	Pattern string
+	Reason  string
+	IUID    int64
+}
+
+func (q *Queries) CreateUsernameRule(ctx context.Context, arg CreateUsernameRuleParams) (sql.Result, error) {
+	return q.exec(ctx, q.createUsernameRuleStmt, createUsernameRule,
+		arg.Kind,
+		arg.Pattern,
+		arg.Reason,
+		arg.IUID,
+	)
+}
+
+const deleteUsernameRule = `-- name: DeleteUsernameRule :exec
+DELETE FROM username_rules WHERE id = ?
+`
+
+func (q *Queries) DeleteUsernameRule(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteUsernameRuleStmt, deleteUsernameRule, id)
+	return err
+}
+
+const getLatestOwnUsernameHistory = `-- name: GetLatestOwnUsernameHistory :one
+SELECT old_username, new_username, reserved_until, request_id, iuid, uid, id, created_at, old_username_skeleton FROM username_history WHERE uid = ?1 AND iuid = ?1 ORDER BY created_at DESC, id DESC LIMIT 1
+`
+
+func (q *Queries) GetLatestOwnUsernameHistory(ctx context.Context, uid int64) (UsernameHistory, error) {
+	row := q.queryRow(ctx, q.getLatestOwnUsernameHistoryStmt, getLatestOwnUsernameHistory, uid)
+	var i UsernameHistory
+	err := row.Scan(
+		&i.OldUsername,
+		&i.NewUsername,
+		&i.ReservedUntil,
+		&i.RequestID,
+		&i.IUID,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.OldUsernameSkeleton,
+	)
+	return i, err
+}
+
+const getUserByUsernameSkeleton = `-- name: GetUserByUsernameSkeleton :one
+SELECT pw_hash, username, id, created_at, updated_at, username_skeleton FROM users WHERE username_skeleton = ?
+`
+
+func (q *Queries) GetUserByUsernameSkeleton(ctx context.Context, usernameSkeleton sql.NullString) (User, error) {
+	row := q.queryRow(ctx, q.getUserByUsernameSkeletonStmt, getUserByUsernameSkeleton, usernameSkeleton)
+	var i User
+	err := row.Scan(
+		&i.PwHash,
+		&i.Username,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UsernameSkeleton,
+	)
+	return i, err
+}
+
+const getUsernameReservation = `-- name: GetUsernameReservation :one
+SELECT old_username, new_username, reserved_until, request_id, iuid, uid, id, created_at, old_username_skeleton FROM username_history
+WHERE old_username_skeleton = ?1 AND reserved_until > ?2
+ORDER BY reserved_until DESC
+LIMIT 1
+`
+
+type GetUsernameReservationParams struct {
+	Skeleton string
+	Now      int64
+}
+
+func (q *Queries) GetUsernameReservation(ctx context.Context, arg GetUsernameReservationParams) (UsernameHistory, error) {
+	row := q.queryRow(ctx, q.getUsernameReservationStmt, getUsernameReservation, arg.Skeleton, arg.Now)
+	var i UsernameHistory
+	err := row.Scan(
+		&i.OldUsername,
+		&i.NewUsername,
+		&i.ReservedUntil,
+		&i.RequestID,
+		&i.IUID,
+		&i.UID,
+		&i.ID,
+		&i.CreatedAt,
+		&i.OldUsernameSkeleton,
+	)
+	return i, err
+}
+
+const getUsernameRule = `-- name: GetUsernameRule :one
+SELECT kind, pattern, reason, iuid, id, created_at FROM username_rules WHERE id = ?
+`
+
+func (q *Queries) GetUsernameRule(ctx context.Context, id int64) (UsernameRule, error) {
+	row := q.queryRow(ctx, q.getUsernameRuleStmt, getUsernameRule, id)
+	var i UsernameRule
+	err := row.Scan(
+		&i.Kind,
+		&i.Pattern,
+		&i.Reason,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUsernameRuleByPattern = `-- name: GetUsernameRuleByPattern :one
+SELECT kind, pattern, reason, iuid, id, created_at FROM username_rules WHERE kind = ? AND pattern = ?
+`
+
+type GetUsernameRuleByPatternParams struct {
+	Kind    string
+	Pattern string
+}
+
+func (q *Queries) GetUsernameRuleByPattern(ctx context.Context, arg GetUsernameRuleByPatternParams) (UsernameRule, error) {
+	row := q.queryRow(ctx, q.getUsernameRuleByPatternStmt, getUsernameRuleByPattern, arg.Kind, arg.Pattern)
+	var i UsernameRule
+	err := row.Scan(
+		&i.Kind,
+		&i.Pattern,
+		&i.Reason,
+		&i.IUID,
+		&i.ID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listUsernameHistory = `-- name: ListUsernameHistory :many
+SELECT old_username, new_username, reserved_until, request_id, iuid, uid, id, created_at, old_username_skeleton FROM username_history WHERE uid = ? ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListUsernameHistory(ctx context.Context, uid int64) ([]UsernameHistory, error) {
+	rows, err := q.query(ctx, q.listUsernameHistoryStmt, listUsernameHistory, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UsernameHistory
+	for rows.Next() {
+		var i UsernameHistory
+		if err := rows.Scan(
+			&i.OldUsername,
+			&i.NewUsername,
+			&i.ReservedUntil,
+			&i.RequestID,
+			&i.IUID,
+			&i.UID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.OldUsernameSkeleton,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsernameRules = `-- name: ListUsernameRules :many
+SELECT kind, pattern, reason, iuid, id, created_at FROM username_rules ORDER BY kind, pattern
+`
+
+func (q *Queries) ListUsernameRules(ctx context.Context) ([]UsernameRule, error) {
+	rows, err := q.query(ctx, q.listUsernameRulesStmt, listUsernameRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UsernameRule
+	for rows.Next() {
+		var i UsernameRule
+		if err := rows.Scan(
+			&i.Kind,
+			&i.Pattern,
+			&i.Reason,
+			&i.IUID,
+			&i.ID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUsername = `-- name: UpdateUsername :exec
+UPDATE users SET username = ?, username_skeleton = ? WHERE id = ?
+`
+
+type UpdateUsernameParams struct {
+	Username         string
+	UsernameSkeleton sql.NullString
+	ID               int64
+}
+
+func (q *Queries) UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error {
+	_, err := q.exec(ctx, q.updateUsernameStmt, updateUsername, arg.Username, arg.UsernameSkeleton, arg.ID)
+	return err
+}
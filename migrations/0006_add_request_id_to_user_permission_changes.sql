@@ -0,0 +1,5 @@
+ALTER TABLE user_permission_grants ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_permission_revocations ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX user_permission_grants_request_id ON user_permission_grants(request_id);
+CREATE INDEX user_permission_revocations_request_id ON user_permission_revocations(request_id);
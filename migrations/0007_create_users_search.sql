@@ -0,0 +1,58 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS users_search USING fts5
+(
+  username,
+  display_name,
+  emails,
+  tokenize = 'unicode61 remove_diacritics 2',
+  prefix = '2 3'
+);
+
+INSERT INTO users_search (rowid, username, display_name, emails)
+  SELECT
+    users.id,
+    users.username,
+    '',
+    coalesce((SELECT group_concat(emails.address, ' ') FROM emails WHERE emails.uid = users.id), '')
+  FROM users;
+
+CREATE TRIGGER users_search_users_insert AFTER INSERT ON users
+  BEGIN
+      INSERT INTO users_search (rowid, username, display_name, emails)
+      VALUES (new.id, new.username, '', '');
+  END;
+
+CREATE TRIGGER users_search_users_update AFTER UPDATE OF username ON users
+  BEGIN
+      UPDATE users_search
+      SET username = new.username
+      WHERE rowid = old.id;
+  END;
+
+CREATE TRIGGER users_search_users_delete AFTER DELETE ON users
+  BEGIN
+      DELETE FROM users_search WHERE rowid = old.id;
+  END;
+
+CREATE TRIGGER users_search_emails_insert AFTER INSERT ON emails
+  BEGIN
+      UPDATE users_search
+      SET emails = coalesce((SELECT group_concat(address, ' ') FROM emails WHERE uid = new.uid), '')
+      WHERE rowid = new.uid;
+  END;
+
+CREATE TRIGGER users_search_emails_update AFTER UPDATE OF address, uid ON emails
+  BEGIN
+      UPDATE users_search
+      SET emails = coalesce((SELECT group_concat(address, ' ') FROM emails WHERE uid = old.uid), '')
+      WHERE rowid = old.uid;
+      UPDATE users_search
+      SET emails = coalesce((SELECT group_concat(address, ' ') FROM emails WHERE uid = new.uid), '')
+      WHERE rowid = new.uid;
+  END;
+
+CREATE TRIGGER users_search_emails_delete AFTER DELETE ON emails
+  BEGIN
+      UPDATE users_search
+      SET emails = coalesce((SELECT group_concat(address, ' ') FROM emails WHERE uid = old.uid), '')
+      WHERE rowid = old.uid;
+  END;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS user_statuses
+(
+  status      TEXT NOT NULL,
+  reason      TEXT NOT NULL DEFAULT '',
+  until       INTEGER,
+  iuid        INTEGER NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  updated_at  INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_statuses_uid ON user_statuses(uid);
+CREATE INDEX user_statuses_status ON user_statuses(status);
+
+CREATE TRIGGER user_statuses_updated_at AFTER UPDATE ON user_statuses
+  BEGIN
+      UPDATE user_statuses
+      SET updated_at = unixepoch('now')
+      WHERE id = old.id;
+  END;
+
+CREATE TABLE IF NOT EXISTS user_status_changes
+(
+  status      TEXT NOT NULL,
+  reason      TEXT NOT NULL DEFAULT '',
+  until       INTEGER,
+  request_id  TEXT NOT NULL DEFAULT '',
+  iuid        INTEGER NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE INDEX user_status_changes_uid ON user_status_changes(uid);
+CREATE INDEX user_status_changes_iuid ON user_status_changes(iuid);
+CREATE INDEX user_status_changes_request_id ON user_status_changes(request_id);
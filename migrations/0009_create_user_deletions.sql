@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_deletions
+(
+  delete_after  INTEGER NOT NULL,
+  request_id    TEXT NOT NULL DEFAULT '',
+  iuid          INTEGER NOT NULL,
+  uid           INTEGER NOT NULL,
+  id            INTEGER PRIMARY KEY,
+  created_at    INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_deletions_uid ON user_deletions(uid);
+CREATE INDEX user_deletions_delete_after ON user_deletions(delete_after);
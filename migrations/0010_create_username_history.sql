@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS username_history
+(
+  old_username    TEXT NOT NULL,
+  new_username    TEXT NOT NULL,
+  reserved_until  INTEGER NOT NULL,
+  request_id      TEXT NOT NULL DEFAULT '',
+  iuid            INTEGER NOT NULL,
+  uid             INTEGER NOT NULL,
+  id              INTEGER PRIMARY KEY,
+  created_at      INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX username_history_uid ON username_history(uid, created_at);
+CREATE INDEX username_history_old_username ON username_history(old_username, reserved_until);
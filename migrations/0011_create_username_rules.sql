@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS username_rules
+(
+  kind        TEXT NOT NULL CHECK(kind IN ('reserved', 'blocked')),
+  pattern     TEXT NOT NULL,
+  reason      TEXT NOT NULL DEFAULT '',
+  iuid        INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE UNIQUE INDEX username_rules_kind_pattern ON username_rules(kind, pattern);
@@ -0,0 +1,20 @@
+-- The skeleton of a username folds characters that look alike, so usernames that would be
+-- mistaken for each other can't both be taken. Existing usernames are all lowercase ASCII,
+-- where the only folds are 0 to o, 1 to l and m to rn.
+ALTER TABLE users ADD COLUMN username_skeleton TEXT;
+
+UPDATE users SET username_skeleton = replace(replace(replace(username, '0', 'o'), '1', 'l'), 'm', 'rn');
+
+-- Existing users whose usernames now look alike keep them, but only the oldest holds the skeleton.
+UPDATE users SET username_skeleton = NULL
+WHERE id NOT IN (SELECT min(id) FROM users GROUP BY username_skeleton);
+
+CREATE UNIQUE INDEX users_username_skeleton ON users(username_skeleton);
+
+ALTER TABLE username_history ADD COLUMN old_username_skeleton TEXT NOT NULL DEFAULT '';
+
+UPDATE username_history
+SET old_username_skeleton = replace(replace(replace(old_username, '0', 'o'), '1', 'l'), 'm', 'rn');
+
+DROP INDEX username_history_old_username;
+CREATE INDEX username_history_old_username_skeleton ON username_history(old_username_skeleton, reserved_until);
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS user_profiles
+(
+  display_name  TEXT NOT NULL DEFAULT '',
+  bio           TEXT NOT NULL DEFAULT '',
+  pronouns      TEXT NOT NULL DEFAULT '',
+  avatar_url    TEXT NOT NULL DEFAULT '',
+  uid           INTEGER NOT NULL,
+  id            INTEGER PRIMARY KEY,
+  created_at    INTEGER DEFAULT(unixepoch('now')),
+  updated_at    INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_profiles_uid ON user_profiles(uid);
+
+CREATE TRIGGER user_profiles_updated_at AFTER UPDATE ON user_profiles
+  BEGIN
+      UPDATE user_profiles
+      SET updated_at = unixepoch('now')
+      WHERE id = old.id;
+  END;
+
+CREATE TRIGGER users_search_user_profiles_insert AFTER INSERT ON user_profiles
+  BEGIN
+      UPDATE users_search
+      SET display_name = new.display_name
+      WHERE rowid = new.uid;
+  END;
+
+CREATE TRIGGER users_search_user_profiles_update AFTER UPDATE OF display_name ON user_profiles
+  BEGIN
+      UPDATE users_search
+      SET display_name = new.display_name
+      WHERE rowid = new.uid;
+  END;
+
+CREATE TRIGGER users_search_user_profiles_delete AFTER DELETE ON user_profiles
+  BEGIN
+      UPDATE users_search
+      SET display_name = ''
+      WHERE rowid = old.uid;
+  END;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS user_setting_values
+(
+  key         TEXT NOT NULL,
+  value       TEXT NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  updated_at  INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_setting_values_uid_key ON user_setting_values(uid, key);
+
+CREATE TRIGGER user_setting_values_updated_at AFTER UPDATE ON user_setting_values
+  BEGIN
+      UPDATE user_setting_values
+      SET updated_at = unixepoch('now')
+      WHERE id = old.id;
+  END;
+
+INSERT INTO user_setting_values (key, value, uid, created_at, updated_at)
+  SELECT 'theme', theme, uid, created_at, updated_at FROM user_settings;
+
+DROP TABLE user_settings;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS themes
+(
+  name        TEXT NOT NULL UNIQUE,
+  palette     TEXT NOT NULL,
+  iuid        INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  updated_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE TRIGGER themes_updated_at AFTER UPDATE ON themes
+  BEGIN
+      UPDATE themes
+      SET updated_at = unixepoch('now')
+      WHERE id = old.id;
+  END;
@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS roles
+(
+  name        TEXT NOT NULL UNIQUE,
+  about       TEXT NOT NULL DEFAULT '',
+  iuid        INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  updated_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE TRIGGER roles_updated_at AFTER UPDATE ON roles
+  BEGIN
+      UPDATE roles
+      SET updated_at = unixepoch('now')
+      WHERE id = old.id;
+  END;
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+  name        TEXT NOT NULL,
+  role_id     INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX role_permissions_role_id_name ON role_permissions(role_id, name);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+  role_id     INTEGER NOT NULL,
+  iuid        INTEGER NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_roles_uid_role_id ON user_roles(uid, role_id);
+CREATE INDEX user_roles_role_id ON user_roles(role_id);
+CREATE INDEX user_roles_iuid ON user_roles(iuid);
+
+CREATE TABLE IF NOT EXISTS user_role_changes
+(
+  action      TEXT NOT NULL CHECK(action IN ('assign', 'unassign')),
+  role_id     INTEGER NOT NULL,
+  role_name   TEXT NOT NULL,
+  request_id  TEXT NOT NULL DEFAULT '',
+  iuid        INTEGER NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE INDEX user_role_changes_uid ON user_role_changes(uid);
+CREATE INDEX user_role_changes_iuid ON user_role_changes(iuid);
+CREATE INDEX user_role_changes_request_id ON user_role_changes(request_id);
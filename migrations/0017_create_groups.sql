@@ -0,0 +1,59 @@
+-- Named user_groups, since GROUPS is a keyword in SQLite.
+CREATE TABLE IF NOT EXISTS user_groups
+(
+  name        TEXT NOT NULL UNIQUE,
+  about       TEXT NOT NULL DEFAULT '',
+  iuid        INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  updated_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE TRIGGER user_groups_updated_at AFTER UPDATE ON user_groups
+  BEGIN
+      UPDATE user_groups
+      SET updated_at = unixepoch('now')
+      WHERE id = old.id;
+  END;
+
+CREATE TABLE IF NOT EXISTS user_group_permissions
+(
+  name        TEXT NOT NULL,
+  group_id    INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_group_permissions_group_id_name ON user_group_permissions(group_id, name);
+
+CREATE TABLE IF NOT EXISTS user_group_members
+(
+  group_id    INTEGER NOT NULL,
+  iuid        INTEGER NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now')),
+  FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE CASCADE,
+  FOREIGN KEY (uid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX user_group_members_uid_group_id ON user_group_members(uid, group_id);
+CREATE INDEX user_group_members_group_id ON user_group_members(group_id);
+CREATE INDEX user_group_members_iuid ON user_group_members(iuid);
+
+CREATE TABLE IF NOT EXISTS user_group_member_changes
+(
+  action      TEXT NOT NULL CHECK(action IN ('add', 'remove')),
+  group_id    INTEGER NOT NULL,
+  group_name  TEXT NOT NULL,
+  request_id  TEXT NOT NULL DEFAULT '',
+  iuid        INTEGER NOT NULL,
+  uid         INTEGER NOT NULL,
+  id          INTEGER PRIMARY KEY,
+  created_at  INTEGER DEFAULT(unixepoch('now'))
+);
+
+CREATE INDEX user_group_member_changes_uid ON user_group_member_changes(uid);
+CREATE INDEX user_group_member_changes_iuid ON user_group_member_changes(iuid);
+CREATE INDEX user_group_member_changes_request_id ON user_group_member_changes(request_id);
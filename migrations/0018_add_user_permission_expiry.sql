@@ -0,0 +1,4 @@
+-- A NULL expires_at means the permission is held until it's revoked.
+ALTER TABLE user_permissions ADD COLUMN expires_at INTEGER;
+
+CREATE INDEX user_permissions_expires_at ON user_permissions(expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- A scope limits a permission to matching resources, like room:42 or area:north-*. An empty scope means everywhere.
+ALTER TABLE user_permissions ADD COLUMN scope TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_permission_grants ADD COLUMN scope TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_permission_revocations ADD COLUMN scope TEXT NOT NULL DEFAULT '';
+
+DROP INDEX user_permissions_name;
+CREATE UNIQUE INDEX user_permissions_name ON user_permissions(uid, name, scope);
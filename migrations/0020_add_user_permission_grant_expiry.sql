@@ -0,0 +1,3 @@
+-- The expiry each grant was made with, so extending a grant or making it permanent shows in the log.
+-- A NULL expires_at means the grant was made until it's revoked.
+ALTER TABLE user_permission_grants ADD COLUMN expires_at INTEGER;
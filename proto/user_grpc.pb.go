@@ -23,11 +23,49 @@ const (
 	User_Login_FullMethodName                     = "/user.User/Login"
 	User_UserSettings_FullMethodName              = "/user.User/UserSettings"
 	User_SetUserSettingsTheme_FullMethodName      = "/user.User/SetUserSettingsTheme"
+	User_GetSettings_FullMethodName               = "/user.User/GetSettings"
+	User_WatchUserSettings_FullMethodName         = "/user.User/WatchUserSettings"
+	User_UpdateSettings_FullMethodName            = "/user.User/UpdateSettings"
 	User_Users_FullMethodName                     = "/user.User/Users"
+	User_GetUser_FullMethodName                   = "/user.User/GetUser"
+	User_GetUserByUsername_FullMethodName         = "/user.User/GetUserByUsername"
+	User_GetProfile_FullMethodName                = "/user.User/GetProfile"
+	User_UpdateProfile_FullMethodName             = "/user.User/UpdateProfile"
+	User_SearchUsers_FullMethodName               = "/user.User/SearchUsers"
 	User_UserPermissionDefinitions_FullMethodName = "/user.User/UserPermissionDefinitions"
 	User_UserPermissions_FullMethodName           = "/user.User/UserPermissions"
 	User_GrantUserPermission_FullMethodName       = "/user.User/GrantUserPermission"
 	User_RevokeUserPermission_FullMethodName      = "/user.User/RevokeUserPermission"
+	User_CheckUserPermission_FullMethodName       = "/user.User/CheckUserPermission"
+	User_Roles_FullMethodName                     = "/user.User/Roles"
+	User_CreateRole_FullMethodName                = "/user.User/CreateRole"
+	User_UpdateRole_FullMethodName                = "/user.User/UpdateRole"
+	User_DeleteRole_FullMethodName                = "/user.User/DeleteRole"
+	User_UserRoles_FullMethodName                 = "/user.User/UserRoles"
+	User_AssignRole_FullMethodName                = "/user.User/AssignRole"
+	User_UnassignRole_FullMethodName              = "/user.User/UnassignRole"
+	User_Groups_FullMethodName                    = "/user.User/Groups"
+	User_CreateGroup_FullMethodName               = "/user.User/CreateGroup"
+	User_UpdateGroup_FullMethodName               = "/user.User/UpdateGroup"
+	User_DeleteGroup_FullMethodName               = "/user.User/DeleteGroup"
+	User_GroupMembers_FullMethodName              = "/user.User/GroupMembers"
+	User_UserGroups_FullMethodName                = "/user.User/UserGroups"
+	User_AddGroupMember_FullMethodName            = "/user.User/AddGroupMember"
+	User_RemoveGroupMember_FullMethodName         = "/user.User/RemoveGroupMember"
+	User_SuspendUser_FullMethodName               = "/user.User/SuspendUser"
+	User_BanUser_FullMethodName                   = "/user.User/BanUser"
+	User_ReinstateUser_FullMethodName             = "/user.User/ReinstateUser"
+	User_DeleteAccount_FullMethodName             = "/user.User/DeleteAccount"
+	User_CancelAccountDeletion_FullMethodName     = "/user.User/CancelAccountDeletion"
+	User_ChangeUsername_FullMethodName            = "/user.User/ChangeUsername"
+	User_UsernameRules_FullMethodName             = "/user.User/UsernameRules"
+	User_AddUsernameRule_FullMethodName           = "/user.User/AddUsernameRule"
+	User_RemoveUsernameRule_FullMethodName        = "/user.User/RemoveUsernameRule"
+	User_Themes_FullMethodName                    = "/user.User/Themes"
+	User_CreateTheme_FullMethodName               = "/user.User/CreateTheme"
+	User_UpdateTheme_FullMethodName               = "/user.User/UpdateTheme"
+	User_DeleteTheme_FullMethodName               = "/user.User/DeleteTheme"
+	User_ExportUserData_FullMethodName            = "/user.User/ExportUserData"
 )
 
 // UserClient is the client API for User service.
@@ -37,12 +75,75 @@ type UserClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterReply, error)
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginReply, error)
 	UserSettings(ctx context.Context, in *UserSettingsRequest, opts ...grpc.CallOption) (*UserSettingsReply, error)
+	// The theme can be any in the catalogue; see Themes.
 	SetUserSettingsTheme(ctx context.Context, in *SetUserSettingsThemeRequest, opts ...grpc.CallOption) (*SetUserSettingsThemeReply, error)
+	// With no keys, returns every setting.
+	GetSettings(ctx context.Context, in *GetSettingsRequest, opts ...grpc.CallOption) (*GetSettingsReply, error)
+	// Sends every setting, then again each time the user's settings change, with heartbeats in between.
+	// Over REST, replies are newline-delimited JSON objects with the reply under "result".
+	WatchUserSettings(ctx context.Context, in *WatchUserSettingsRequest, opts ...grpc.CallOption) (User_WatchUserSettingsClient, error)
+	// Applies every update or, if any is invalid, none of them.
+	UpdateSettings(ctx context.Context, in *UpdateSettingsRequest, opts ...grpc.CallOption) (*UpdateSettingsReply, error)
 	Users(ctx context.Context, in *UsersRequest, opts ...grpc.CallOption) (*UsersReply, error)
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*UserProfile, error)
+	GetUserByUsername(ctx context.Context, in *GetUserByUsernameRequest, opts ...grpc.CallOption) (*UserProfile, error)
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*Profile, error)
+	// Over REST, the update mask defaults to the fields in the request body.
+	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*Profile, error)
+	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*SearchUsersReply, error)
 	UserPermissionDefinitions(ctx context.Context, in *UserPermissionDefinitionsRequest, opts ...grpc.CallOption) (*UserPermissionDefinitionsReply, error)
+	// Lists the user's effective permissions: those granted directly, and those from their roles and groups.
 	UserPermissions(ctx context.Context, in *UserPermissionsRequest, opts ...grpc.CallOption) (*UserPermissionsReply, error)
 	GrantUserPermission(ctx context.Context, in *GrantUserPermissionRequest, opts ...grpc.CallOption) (*GrantUserPermissionReply, error)
 	RevokeUserPermission(ctx context.Context, in *RevokeUserPermissionRequest, opts ...grpc.CallOption) (*RevokeUserPermissionReply, error)
+	// Reports whether the user may use a permission on a resource, through a grant everywhere or a matching scope.
+	CheckUserPermission(ctx context.Context, in *CheckUserPermissionRequest, opts ...grpc.CallOption) (*CheckUserPermissionReply, error)
+	Roles(ctx context.Context, in *RolesRequest, opts ...grpc.CallOption) (*RolesReply, error)
+	// Needs manage-roles, and permission to grant everything in the role.
+	CreateRole(ctx context.Context, in *CreateRoleRequest, opts ...grpc.CallOption) (*Role, error)
+	// Replaces the role's name, about and permissions. Needs manage-roles, and permission to grant the
+	// permissions added and revoke those removed.
+	UpdateRole(ctx context.Context, in *UpdateRoleRequest, opts ...grpc.CallOption) (*Role, error)
+	// Unassigns the role from everyone who has it.
+	DeleteRole(ctx context.Context, in *DeleteRoleRequest, opts ...grpc.CallOption) (*DeleteRoleReply, error)
+	UserRoles(ctx context.Context, in *UserRolesRequest, opts ...grpc.CallOption) (*UserRolesReply, error)
+	// Needs permission to grant every permission in the role. Assigning a role the user has does nothing.
+	AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*AssignRoleReply, error)
+	// Needs permission to revoke every permission in the role. Unassigning a role the user doesn't have does nothing.
+	UnassignRole(ctx context.Context, in *UnassignRoleRequest, opts ...grpc.CallOption) (*UnassignRoleReply, error)
+	Groups(ctx context.Context, in *GroupsRequest, opts ...grpc.CallOption) (*GroupsReply, error)
+	// Needs manage-groups, and permission to grant everything granted to the group.
+	CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// Replaces the group's name, about and permissions. Needs manage-groups, and permission to grant the
+	// permissions added and revoke those removed.
+	UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// Removes everyone from the group.
+	DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*DeleteGroupReply, error)
+	GroupMembers(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*GroupMembersReply, error)
+	UserGroups(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (*UserGroupsReply, error)
+	// Needs permission to grant every permission granted to the group. Adding a member again does nothing.
+	AddGroupMember(ctx context.Context, in *AddGroupMemberRequest, opts ...grpc.CallOption) (*AddGroupMemberReply, error)
+	// Needs permission to revoke every permission granted to the group. Removing a non-member does nothing.
+	RemoveGroupMember(ctx context.Context, in *RemoveGroupMemberRequest, opts ...grpc.CallOption) (*RemoveGroupMemberReply, error)
+	SuspendUser(ctx context.Context, in *SuspendUserRequest, opts ...grpc.CallOption) (*UserStatus, error)
+	BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*UserStatus, error)
+	ReinstateUser(ctx context.Context, in *ReinstateUserRequest, opts ...grpc.CallOption) (*UserStatus, error)
+	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*AccountDeletion, error)
+	CancelAccountDeletion(ctx context.Context, in *CancelAccountDeletionRequest, opts ...grpc.CallOption) (*CancelAccountDeletionReply, error)
+	ChangeUsername(ctx context.Context, in *ChangeUsernameRequest, opts ...grpc.CallOption) (*UsernameChange, error)
+	UsernameRules(ctx context.Context, in *UsernameRulesRequest, opts ...grpc.CallOption) (*UsernameRulesReply, error)
+	AddUsernameRule(ctx context.Context, in *AddUsernameRuleRequest, opts ...grpc.CallOption) (*UsernameRule, error)
+	RemoveUsernameRule(ctx context.Context, in *RemoveUsernameRuleRequest, opts ...grpc.CallOption) (*RemoveUsernameRuleReply, error)
+	// Lists built-in themes, then custom ones.
+	Themes(ctx context.Context, in *ThemesRequest, opts ...grpc.CallOption) (*ThemesReply, error)
+	CreateTheme(ctx context.Context, in *CreateThemeRequest, opts ...grpc.CallOption) (*Theme, error)
+	// Replaces a custom theme's palette.
+	UpdateTheme(ctx context.Context, in *UpdateThemeRequest, opts ...grpc.CallOption) (*Theme, error)
+	// Users who had picked the theme go back to the default.
+	DeleteTheme(ctx context.Context, in *DeleteThemeRequest, opts ...grpc.CallOption) (*DeleteThemeReply, error)
+	// Served over REST as a download at GET /v1/users/{uid}/export by a handler in the server, since the
+	// generated gateway handler would add a delimiter between the streamed chunks.
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (User_ExportUserDataClient, error)
 }
 
 type userClient struct {
@@ -89,6 +190,56 @@ func (c *userClient) SetUserSettingsTheme(ctx context.Context, in *SetUserSettin
 	return out, nil
 }
 
+func (c *userClient) GetSettings(ctx context.Context, in *GetSettingsRequest, opts ...grpc.CallOption) (*GetSettingsReply, error) {
+	out := new(GetSettingsReply)
+	err := c.cc.Invoke(ctx, User_GetSettings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) WatchUserSettings(ctx context.Context, in *WatchUserSettingsRequest, opts ...grpc.CallOption) (User_WatchUserSettingsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &User_ServiceDesc.Streams[0], User_WatchUserSettings_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userWatchUserSettingsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type User_WatchUserSettingsClient interface {
+	Recv() (*WatchUserSettingsReply, error)
+	grpc.ClientStream
+}
+
+type userWatchUserSettingsClient struct {
+	grpc.ClientStream
+}
+
+func (x *userWatchUserSettingsClient) Recv() (*WatchUserSettingsReply, error) {
+	m := new(WatchUserSettingsReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *userClient) UpdateSettings(ctx context.Context, in *UpdateSettingsRequest, opts ...grpc.CallOption) (*UpdateSettingsReply, error) {
+	out := new(UpdateSettingsReply)
+	err := c.cc.Invoke(ctx, User_UpdateSettings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userClient) Users(ctx context.Context, in *UsersRequest, opts ...grpc.CallOption) (*UsersReply, error) {
 	out := new(UsersReply)
 	err := c.cc.Invoke(ctx, User_Users_FullMethodName, in, out, opts...)
@@ -98,264 +249,1469 @@ func (c *userClient) Users(ctx context.Context, in *UsersRequest, opts ...grpc.C
 	return out, nil
 }
 
-func (c *userClient) UserPermissionDefinitions(ctx context.Context, in *UserPermissionDefinitionsRequest, opts ...grpc.CallOption) (*UserPermissionDefinitionsReply, error) {
-	out := new(UserPermissionDefinitionsReply)
-	err := c.cc.Invoke(ctx, User_UserPermissionDefinitions_FullMethodName, in, out, opts...)
-	if err != nil {
+func (c *userClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*UserProfile, error) {
+	out := new(UserProfile)
+	err := c.cc.Invoke(ctx, User_GetUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GetUserByUsername(ctx context.Context, in *GetUserByUsernameRequest, opts ...grpc.CallOption) (*UserProfile, error) {
+	out := new(UserProfile)
+	err := c.cc.Invoke(ctx, User_GetUserByUsername_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, User_GetProfile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, User_UpdateProfile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*SearchUsersReply, error) {
+	out := new(SearchUsersReply)
+	err := c.cc.Invoke(ctx, User_SearchUsers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UserPermissionDefinitions(ctx context.Context, in *UserPermissionDefinitionsRequest, opts ...grpc.CallOption) (*UserPermissionDefinitionsReply, error) {
+	out := new(UserPermissionDefinitionsReply)
+	err := c.cc.Invoke(ctx, User_UserPermissionDefinitions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UserPermissions(ctx context.Context, in *UserPermissionsRequest, opts ...grpc.CallOption) (*UserPermissionsReply, error) {
+	out := new(UserPermissionsReply)
+	err := c.cc.Invoke(ctx, User_UserPermissions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GrantUserPermission(ctx context.Context, in *GrantUserPermissionRequest, opts ...grpc.CallOption) (*GrantUserPermissionReply, error) {
+	out := new(GrantUserPermissionReply)
+	err := c.cc.Invoke(ctx, User_GrantUserPermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RevokeUserPermission(ctx context.Context, in *RevokeUserPermissionRequest, opts ...grpc.CallOption) (*RevokeUserPermissionReply, error) {
+	out := new(RevokeUserPermissionReply)
+	err := c.cc.Invoke(ctx, User_RevokeUserPermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CheckUserPermission(ctx context.Context, in *CheckUserPermissionRequest, opts ...grpc.CallOption) (*CheckUserPermissionReply, error) {
+	out := new(CheckUserPermissionReply)
+	err := c.cc.Invoke(ctx, User_CheckUserPermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Roles(ctx context.Context, in *RolesRequest, opts ...grpc.CallOption) (*RolesReply, error) {
+	out := new(RolesReply)
+	err := c.cc.Invoke(ctx, User_Roles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CreateRole(ctx context.Context, in *CreateRoleRequest, opts ...grpc.CallOption) (*Role, error) {
+	out := new(Role)
+	err := c.cc.Invoke(ctx, User_CreateRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UpdateRole(ctx context.Context, in *UpdateRoleRequest, opts ...grpc.CallOption) (*Role, error) {
+	out := new(Role)
+	err := c.cc.Invoke(ctx, User_UpdateRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DeleteRole(ctx context.Context, in *DeleteRoleRequest, opts ...grpc.CallOption) (*DeleteRoleReply, error) {
+	out := new(DeleteRoleReply)
+	err := c.cc.Invoke(ctx, User_DeleteRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UserRoles(ctx context.Context, in *UserRolesRequest, opts ...grpc.CallOption) (*UserRolesReply, error) {
+	out := new(UserRolesReply)
+	err := c.cc.Invoke(ctx, User_UserRoles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*AssignRoleReply, error) {
+	out := new(AssignRoleReply)
+	err := c.cc.Invoke(ctx, User_AssignRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UnassignRole(ctx context.Context, in *UnassignRoleRequest, opts ...grpc.CallOption) (*UnassignRoleReply, error) {
+	out := new(UnassignRoleReply)
+	err := c.cc.Invoke(ctx, User_UnassignRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Groups(ctx context.Context, in *GroupsRequest, opts ...grpc.CallOption) (*GroupsReply, error) {
+	out := new(GroupsReply)
+	err := c.cc.Invoke(ctx, User_Groups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, User_CreateGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, User_UpdateGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*DeleteGroupReply, error) {
+	out := new(DeleteGroupReply)
+	err := c.cc.Invoke(ctx, User_DeleteGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GroupMembers(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*GroupMembersReply, error) {
+	out := new(GroupMembersReply)
+	err := c.cc.Invoke(ctx, User_GroupMembers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UserGroups(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (*UserGroupsReply, error) {
+	out := new(UserGroupsReply)
+	err := c.cc.Invoke(ctx, User_UserGroups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) AddGroupMember(ctx context.Context, in *AddGroupMemberRequest, opts ...grpc.CallOption) (*AddGroupMemberReply, error) {
+	out := new(AddGroupMemberReply)
+	err := c.cc.Invoke(ctx, User_AddGroupMember_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RemoveGroupMember(ctx context.Context, in *RemoveGroupMemberRequest, opts ...grpc.CallOption) (*RemoveGroupMemberReply, error) {
+	out := new(RemoveGroupMemberReply)
+	err := c.cc.Invoke(ctx, User_RemoveGroupMember_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) SuspendUser(ctx context.Context, in *SuspendUserRequest, opts ...grpc.CallOption) (*UserStatus, error) {
+	out := new(UserStatus)
+	err := c.cc.Invoke(ctx, User_SuspendUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*UserStatus, error) {
+	out := new(UserStatus)
+	err := c.cc.Invoke(ctx, User_BanUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ReinstateUser(ctx context.Context, in *ReinstateUserRequest, opts ...grpc.CallOption) (*UserStatus, error) {
+	out := new(UserStatus)
+	err := c.cc.Invoke(ctx, User_ReinstateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*AccountDeletion, error) {
+	out := new(AccountDeletion)
+	err := c.cc.Invoke(ctx, User_DeleteAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CancelAccountDeletion(ctx context.Context, in *CancelAccountDeletionRequest, opts ...grpc.CallOption) (*CancelAccountDeletionReply, error) {
+	out := new(CancelAccountDeletionReply)
+	err := c.cc.Invoke(ctx, User_CancelAccountDeletion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ChangeUsername(ctx context.Context, in *ChangeUsernameRequest, opts ...grpc.CallOption) (*UsernameChange, error) {
+	out := new(UsernameChange)
+	err := c.cc.Invoke(ctx, User_ChangeUsername_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UsernameRules(ctx context.Context, in *UsernameRulesRequest, opts ...grpc.CallOption) (*UsernameRulesReply, error) {
+	out := new(UsernameRulesReply)
+	err := c.cc.Invoke(ctx, User_UsernameRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) AddUsernameRule(ctx context.Context, in *AddUsernameRuleRequest, opts ...grpc.CallOption) (*UsernameRule, error) {
+	out := new(UsernameRule)
+	err := c.cc.Invoke(ctx, User_AddUsernameRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RemoveUsernameRule(ctx context.Context, in *RemoveUsernameRuleRequest, opts ...grpc.CallOption) (*RemoveUsernameRuleReply, error) {
+	out := new(RemoveUsernameRuleReply)
+	err := c.cc.Invoke(ctx, User_RemoveUsernameRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Themes(ctx context.Context, in *ThemesRequest, opts ...grpc.CallOption) (*ThemesReply, error) {
+	out := new(ThemesReply)
+	err := c.cc.Invoke(ctx, User_Themes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CreateTheme(ctx context.Context, in *CreateThemeRequest, opts ...grpc.CallOption) (*Theme, error) {
+	out := new(Theme)
+	err := c.cc.Invoke(ctx, User_CreateTheme_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UpdateTheme(ctx context.Context, in *UpdateThemeRequest, opts ...grpc.CallOption) (*Theme, error) {
+	out := new(Theme)
+	err := c.cc.Invoke(ctx, User_UpdateTheme_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DeleteTheme(ctx context.Context, in *DeleteThemeRequest, opts ...grpc.CallOption) (*DeleteThemeReply, error) {
+	out := new(DeleteThemeReply)
+	err := c.cc.Invoke(ctx, User_DeleteTheme_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (User_ExportUserDataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &User_ServiceDesc.Streams[1], User_ExportUserData_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userExportUserDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type User_ExportUserDataClient interface {
+	Recv() (*ExportUserDataReply, error)
+	grpc.ClientStream
+}
+
+type userExportUserDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *userExportUserDataClient) Recv() (*ExportUserDataReply, error) {
+	m := new(ExportUserDataReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UserServer is the server API for User service.
+// All implementations must embed UnimplementedUserServer
+// for forward compatibility
+type UserServer interface {
+	Register(context.Context, *RegisterRequest) (*RegisterReply, error)
+	Login(context.Context, *LoginRequest) (*LoginReply, error)
+	UserSettings(context.Context, *UserSettingsRequest) (*UserSettingsReply, error)
+	// The theme can be any in the catalogue; see Themes.
+	SetUserSettingsTheme(context.Context, *SetUserSettingsThemeRequest) (*SetUserSettingsThemeReply, error)
+	// With no keys, returns every setting.
+	GetSettings(context.Context, *GetSettingsRequest) (*GetSettingsReply, error)
+	// Sends every setting, then again each time the user's settings change, with heartbeats in between.
+	// Over REST, replies are newline-delimited JSON objects with the reply under "result".
+	WatchUserSettings(*WatchUserSettingsRequest, User_WatchUserSettingsServer) error
+	// Applies every update or, if any is invalid, none of them.
+	UpdateSettings(context.Context, *UpdateSettingsRequest) (*UpdateSettingsReply, error)
+	Users(context.Context, *UsersRequest) (*UsersReply, error)
+	GetUser(context.Context, *GetUserRequest) (*UserProfile, error)
+	GetUserByUsername(context.Context, *GetUserByUsernameRequest) (*UserProfile, error)
+	GetProfile(context.Context, *GetProfileRequest) (*Profile, error)
+	// Over REST, the update mask defaults to the fields in the request body.
+	UpdateProfile(context.Context, *UpdateProfileRequest) (*Profile, error)
+	SearchUsers(context.Context, *SearchUsersRequest) (*SearchUsersReply, error)
+	UserPermissionDefinitions(context.Context, *UserPermissionDefinitionsRequest) (*UserPermissionDefinitionsReply, error)
+	// Lists the user's effective permissions: those granted directly, and those from their roles and groups.
+	UserPermissions(context.Context, *UserPermissionsRequest) (*UserPermissionsReply, error)
+	GrantUserPermission(context.Context, *GrantUserPermissionRequest) (*GrantUserPermissionReply, error)
+	RevokeUserPermission(context.Context, *RevokeUserPermissionRequest) (*RevokeUserPermissionReply, error)
+	// Reports whether the user may use a permission on a resource, through a grant everywhere or a matching scope.
+	CheckUserPermission(context.Context, *CheckUserPermissionRequest) (*CheckUserPermissionReply, error)
+	Roles(context.Context, *RolesRequest) (*RolesReply, error)
+	// Needs manage-roles, and permission to grant everything in the role.
+	CreateRole(context.Context, *CreateRoleRequest) (*Role, error)
+	// Replaces the role's name, about and permissions. Needs manage-roles, and permission to grant the
+	// permissions added and revoke those removed.
+	UpdateRole(context.Context, *UpdateRoleRequest) (*Role, error)
+	// Unassigns the role from everyone who has it.
+	DeleteRole(context.Context, *DeleteRoleRequest) (*DeleteRoleReply, error)
+	UserRoles(context.Context, *UserRolesRequest) (*UserRolesReply, error)
+	// Needs permission to grant every permission in the role. Assigning a role the user has does nothing.
+	AssignRole(context.Context, *AssignRoleRequest) (*AssignRoleReply, error)
+	// Needs permission to revoke every permission in the role. Unassigning a role the user doesn't have does nothing.
+	UnassignRole(context.Context, *UnassignRoleRequest) (*UnassignRoleReply, error)
+	Groups(context.Context, *GroupsRequest) (*GroupsReply, error)
+	// Needs manage-groups, and permission to grant everything granted to the group.
+	CreateGroup(context.Context, *CreateGroupRequest) (*Group, error)
+	// Replaces the group's name, about and permissions. Needs manage-groups, and permission to grant the
+	// permissions added and revoke those removed.
+	UpdateGroup(context.Context, *UpdateGroupRequest) (*Group, error)
+	// Removes everyone from the group.
+	DeleteGroup(context.Context, *DeleteGroupRequest) (*DeleteGroupReply, error)
+	GroupMembers(context.Context, *GroupMembersRequest) (*GroupMembersReply, error)
+	UserGroups(context.Context, *UserGroupsRequest) (*UserGroupsReply, error)
+	// Needs permission to grant every permission granted to the group. Adding a member again does nothing.
+	AddGroupMember(context.Context, *AddGroupMemberRequest) (*AddGroupMemberReply, error)
+	// Needs permission to revoke every permission granted to the group. Removing a non-member does nothing.
+	RemoveGroupMember(context.Context, *RemoveGroupMemberRequest) (*RemoveGroupMemberReply, error)
+	SuspendUser(context.Context, *SuspendUserRequest) (*UserStatus, error)
+	BanUser(context.Context, *BanUserRequest) (*UserStatus, error)
+	ReinstateUser(context.Context, *ReinstateUserRequest) (*UserStatus, error)
+	DeleteAccount(context.Context, *DeleteAccountRequest) (*AccountDeletion, error)
+	CancelAccountDeletion(context.Context, *CancelAccountDeletionRequest) (*CancelAccountDeletionReply, error)
+	ChangeUsername(context.Context, *ChangeUsernameRequest) (*UsernameChange, error)
+	UsernameRules(context.Context, *UsernameRulesRequest) (*UsernameRulesReply, error)
+	AddUsernameRule(context.Context, *AddUsernameRuleRequest) (*UsernameRule, error)
+	RemoveUsernameRule(context.Context, *RemoveUsernameRuleRequest) (*RemoveUsernameRuleReply, error)
+	// Lists built-in themes, then custom ones.
+	Themes(context.Context, *ThemesRequest) (*ThemesReply, error)
+	CreateTheme(context.Context, *CreateThemeRequest) (*Theme, error)
+	// Replaces a custom theme's palette.
+	UpdateTheme(context.Context, *UpdateThemeRequest) (*Theme, error)
+	// Users who had picked the theme go back to the default.
+	DeleteTheme(context.Context, *DeleteThemeRequest) (*DeleteThemeReply, error)
+	// Served over REST as a download at GET /v1/users/{uid}/export by a handler in the server, since the
+	// generated gateway handler would add a delimiter between the streamed chunks.
+	ExportUserData(*ExportUserDataRequest, User_ExportUserDataServer) error
+	mustEmbedUnimplementedUserServer()
+}
+
+// UnimplementedUserServer must be embedded to have forward compatible implementations.
+type UnimplementedUserServer struct {
+}
+
+func (UnimplementedUserServer) Register(context.Context, *RegisterRequest) (*RegisterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedUserServer) Login(context.Context, *LoginRequest) (*LoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedUserServer) UserSettings(context.Context, *UserSettingsRequest) (*UserSettingsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserSettings not implemented")
+}
+func (UnimplementedUserServer) SetUserSettingsTheme(context.Context, *SetUserSettingsThemeRequest) (*SetUserSettingsThemeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserSettingsTheme not implemented")
+}
+func (UnimplementedUserServer) GetSettings(context.Context, *GetSettingsRequest) (*GetSettingsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSettings not implemented")
+}
+func (UnimplementedUserServer) WatchUserSettings(*WatchUserSettingsRequest, User_WatchUserSettingsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUserSettings not implemented")
+}
+func (UnimplementedUserServer) UpdateSettings(context.Context, *UpdateSettingsRequest) (*UpdateSettingsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSettings not implemented")
+}
+func (UnimplementedUserServer) Users(context.Context, *UsersRequest) (*UsersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Users not implemented")
+}
+func (UnimplementedUserServer) GetUser(context.Context, *GetUserRequest) (*UserProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServer) GetUserByUsername(context.Context, *GetUserByUsernameRequest) (*UserProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByUsername not implemented")
+}
+func (UnimplementedUserServer) GetProfile(context.Context, *GetProfileRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedUserServer) UpdateProfile(context.Context, *UpdateProfileRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (UnimplementedUserServer) SearchUsers(context.Context, *SearchUsersRequest) (*SearchUsersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUsers not implemented")
+}
+func (UnimplementedUserServer) UserPermissionDefinitions(context.Context, *UserPermissionDefinitionsRequest) (*UserPermissionDefinitionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserPermissionDefinitions not implemented")
+}
+func (UnimplementedUserServer) UserPermissions(context.Context, *UserPermissionsRequest) (*UserPermissionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserPermissions not implemented")
+}
+func (UnimplementedUserServer) GrantUserPermission(context.Context, *GrantUserPermissionRequest) (*GrantUserPermissionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantUserPermission not implemented")
+}
+func (UnimplementedUserServer) RevokeUserPermission(context.Context, *RevokeUserPermissionRequest) (*RevokeUserPermissionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserPermission not implemented")
+}
+func (UnimplementedUserServer) CheckUserPermission(context.Context, *CheckUserPermissionRequest) (*CheckUserPermissionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckUserPermission not implemented")
+}
+func (UnimplementedUserServer) Roles(context.Context, *RolesRequest) (*RolesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Roles not implemented")
+}
+func (UnimplementedUserServer) CreateRole(context.Context, *CreateRoleRequest) (*Role, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRole not implemented")
+}
+func (UnimplementedUserServer) UpdateRole(context.Context, *UpdateRoleRequest) (*Role, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRole not implemented")
+}
+func (UnimplementedUserServer) DeleteRole(context.Context, *DeleteRoleRequest) (*DeleteRoleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRole not implemented")
+}
+func (UnimplementedUserServer) UserRoles(context.Context, *UserRolesRequest) (*UserRolesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserRoles not implemented")
+}
+func (UnimplementedUserServer) AssignRole(context.Context, *AssignRoleRequest) (*AssignRoleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedUserServer) UnassignRole(context.Context, *UnassignRoleRequest) (*UnassignRoleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnassignRole not implemented")
+}
+func (UnimplementedUserServer) Groups(context.Context, *GroupsRequest) (*GroupsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Groups not implemented")
+}
+func (UnimplementedUserServer) CreateGroup(context.Context, *CreateGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedUserServer) UpdateGroup(context.Context, *UpdateGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGroup not implemented")
+}
+func (UnimplementedUserServer) DeleteGroup(context.Context, *DeleteGroupRequest) (*DeleteGroupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedUserServer) GroupMembers(context.Context, *GroupMembersRequest) (*GroupMembersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GroupMembers not implemented")
+}
+func (UnimplementedUserServer) UserGroups(context.Context, *UserGroupsRequest) (*UserGroupsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserGroups not implemented")
+}
+func (UnimplementedUserServer) AddGroupMember(context.Context, *AddGroupMemberRequest) (*AddGroupMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroupMember not implemented")
+}
+func (UnimplementedUserServer) RemoveGroupMember(context.Context, *RemoveGroupMemberRequest) (*RemoveGroupMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGroupMember not implemented")
+}
+func (UnimplementedUserServer) SuspendUser(context.Context, *SuspendUserRequest) (*UserStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendUser not implemented")
+}
+func (UnimplementedUserServer) BanUser(context.Context, *BanUserRequest) (*UserStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanUser not implemented")
+}
+func (UnimplementedUserServer) ReinstateUser(context.Context, *ReinstateUserRequest) (*UserStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReinstateUser not implemented")
+}
+func (UnimplementedUserServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*AccountDeletion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
+}
+func (UnimplementedUserServer) CancelAccountDeletion(context.Context, *CancelAccountDeletionRequest) (*CancelAccountDeletionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAccountDeletion not implemented")
+}
+func (UnimplementedUserServer) ChangeUsername(context.Context, *ChangeUsernameRequest) (*UsernameChange, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeUsername not implemented")
+}
+func (UnimplementedUserServer) UsernameRules(context.Context, *UsernameRulesRequest) (*UsernameRulesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UsernameRules not implemented")
+}
+func (UnimplementedUserServer) AddUsernameRule(context.Context, *AddUsernameRuleRequest) (*UsernameRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddUsernameRule not implemented")
+}
+func (UnimplementedUserServer) RemoveUsernameRule(context.Context, *RemoveUsernameRuleRequest) (*RemoveUsernameRuleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveUsernameRule not implemented")
+}
+func (UnimplementedUserServer) Themes(context.Context, *ThemesRequest) (*ThemesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Themes not implemented")
+}
+func (UnimplementedUserServer) CreateTheme(context.Context, *CreateThemeRequest) (*Theme, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTheme not implemented")
+}
+func (UnimplementedUserServer) UpdateTheme(context.Context, *UpdateThemeRequest) (*Theme, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTheme not implemented")
+}
+func (UnimplementedUserServer) DeleteTheme(context.Context, *DeleteThemeRequest) (*DeleteThemeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTheme not implemented")
+}
+func (UnimplementedUserServer) ExportUserData(*ExportUserDataRequest, User_ExportUserDataServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedUserServer) mustEmbedUnimplementedUserServer() {}
+
+// UnsafeUserServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServer will
+// result in compilation errors.
+type UnsafeUserServer interface {
+	mustEmbedUnimplementedUserServer()
+}
+
+func RegisterUserServer(s grpc.ServiceRegistrar, srv UserServer) {
+	s.RegisterService(&User_ServiceDesc, srv)
+}
+
+func _User_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UserSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UserSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UserSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UserSettings(ctx, req.(*UserSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_SetUserSettingsTheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserSettingsThemeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).SetUserSettingsTheme(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_SetUserSettingsTheme_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).SetUserSettingsTheme(ctx, req.(*SetUserSettingsThemeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GetSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetSettings(ctx, req.(*GetSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_WatchUserSettings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUserSettingsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServer).WatchUserSettings(m, &userWatchUserSettingsServer{stream})
+}
+
+type User_WatchUserSettingsServer interface {
+	Send(*WatchUserSettingsReply) error
+	grpc.ServerStream
+}
+
+type userWatchUserSettingsServer struct {
+	grpc.ServerStream
+}
+
+func (x *userWatchUserSettingsServer) Send(m *WatchUserSettingsReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _User_UpdateSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UpdateSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UpdateSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UpdateSettings(ctx, req.(*UpdateSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Users_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Users(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Users_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Users(ctx, req.(*UsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetUserByUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserByUsernameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetUserByUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GetUserByUsername_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetUserByUsername(ctx, req.(*GetUserByUsernameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UpdateProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UpdateProfile(ctx, req.(*UpdateProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_SearchUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).SearchUsers(ctx, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UserPermissionDefinitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPermissionDefinitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UserPermissionDefinitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UserPermissionDefinitions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UserPermissionDefinitions(ctx, req.(*UserPermissionDefinitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UserPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UserPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UserPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UserPermissions(ctx, req.(*UserPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GrantUserPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantUserPermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GrantUserPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GrantUserPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GrantUserPermission(ctx, req.(*GrantUserPermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RevokeUserPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserPermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).RevokeUserPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_RevokeUserPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RevokeUserPermission(ctx, req.(*RevokeUserPermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CheckUserPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckUserPermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CheckUserPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_CheckUserPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CheckUserPermission(ctx, req.(*CheckUserPermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Roles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Roles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Roles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Roles(ctx, req.(*RolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CreateRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CreateRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_CreateRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CreateRole(ctx, req.(*CreateRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UpdateRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UpdateRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UpdateRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UpdateRole(ctx, req.(*UpdateRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_DeleteRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).DeleteRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_DeleteRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).DeleteRole(ctx, req.(*DeleteRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UserRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UserRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UserRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UserRoles(ctx, req.(*UserRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_AssignRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).AssignRole(ctx, req.(*AssignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UnassignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnassignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UnassignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UnassignRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UnassignRole(ctx, req.(*UnassignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Groups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Groups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Groups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Groups(ctx, req.(*GroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_CreateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CreateGroup(ctx, req.(*CreateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UpdateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UpdateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UpdateGroup(ctx, req.(*UpdateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_DeleteGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).DeleteGroup(ctx, req.(*DeleteGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GroupMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GroupMembers(ctx, req.(*GroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UserGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UserGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_UserGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UserGroups(ctx, req.(*UserGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_AddGroupMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddGroupMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).AddGroupMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_AddGroupMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).AddGroupMember(ctx, req.(*AddGroupMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RemoveGroupMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveGroupMemberRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(UserServer).RemoveGroupMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_RemoveGroupMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RemoveGroupMember(ctx, req.(*RemoveGroupMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *userClient) UserPermissions(ctx context.Context, in *UserPermissionsRequest, opts ...grpc.CallOption) (*UserPermissionsReply, error) {
-	out := new(UserPermissionsReply)
-	err := c.cc.Invoke(ctx, User_UserPermissions_FullMethodName, in, out, opts...)
-	if err != nil {
+func _User_SuspendUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendUserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(UserServer).SuspendUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_SuspendUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).SuspendUser(ctx, req.(*SuspendUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *userClient) GrantUserPermission(ctx context.Context, in *GrantUserPermissionRequest, opts ...grpc.CallOption) (*GrantUserPermissionReply, error) {
-	out := new(GrantUserPermissionReply)
-	err := c.cc.Invoke(ctx, User_GrantUserPermission_FullMethodName, in, out, opts...)
-	if err != nil {
+func _User_BanUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanUserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(UserServer).BanUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_BanUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).BanUser(ctx, req.(*BanUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *userClient) RevokeUserPermission(ctx context.Context, in *RevokeUserPermissionRequest, opts ...grpc.CallOption) (*RevokeUserPermissionReply, error) {
-	out := new(RevokeUserPermissionReply)
-	err := c.cc.Invoke(ctx, User_RevokeUserPermission_FullMethodName, in, out, opts...)
-	if err != nil {
+func _User_ReinstateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReinstateUserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// UserServer is the server API for User service.
-// All implementations must embed UnimplementedUserServer
-// for forward compatibility
-type UserServer interface {
-	Register(context.Context, *RegisterRequest) (*RegisterReply, error)
-	Login(context.Context, *LoginRequest) (*LoginReply, error)
-	UserSettings(context.Context, *UserSettingsRequest) (*UserSettingsReply, error)
-	SetUserSettingsTheme(context.Context, *SetUserSettingsThemeRequest) (*SetUserSettingsThemeReply, error)
-	Users(context.Context, *UsersRequest) (*UsersReply, error)
-	UserPermissionDefinitions(context.Context, *UserPermissionDefinitionsRequest) (*UserPermissionDefinitionsReply, error)
-	UserPermissions(context.Context, *UserPermissionsRequest) (*UserPermissionsReply, error)
-	GrantUserPermission(context.Context, *GrantUserPermissionRequest) (*GrantUserPermissionReply, error)
-	RevokeUserPermission(context.Context, *RevokeUserPermissionRequest) (*RevokeUserPermissionReply, error)
-	mustEmbedUnimplementedUserServer()
-}
-
-// UnimplementedUserServer must be embedded to have forward compatible implementations.
-type UnimplementedUserServer struct {
-}
-
-func (UnimplementedUserServer) Register(context.Context, *RegisterRequest) (*RegisterReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
-}
-func (UnimplementedUserServer) Login(context.Context, *LoginRequest) (*LoginReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
-}
-func (UnimplementedUserServer) UserSettings(context.Context, *UserSettingsRequest) (*UserSettingsReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UserSettings not implemented")
-}
-func (UnimplementedUserServer) SetUserSettingsTheme(context.Context, *SetUserSettingsThemeRequest) (*SetUserSettingsThemeReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SetUserSettingsTheme not implemented")
-}
-func (UnimplementedUserServer) Users(context.Context, *UsersRequest) (*UsersReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Users not implemented")
-}
-func (UnimplementedUserServer) UserPermissionDefinitions(context.Context, *UserPermissionDefinitionsRequest) (*UserPermissionDefinitionsReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UserPermissionDefinitions not implemented")
-}
-func (UnimplementedUserServer) UserPermissions(context.Context, *UserPermissionsRequest) (*UserPermissionsReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UserPermissions not implemented")
-}
-func (UnimplementedUserServer) GrantUserPermission(context.Context, *GrantUserPermissionRequest) (*GrantUserPermissionReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GrantUserPermission not implemented")
-}
-func (UnimplementedUserServer) RevokeUserPermission(context.Context, *RevokeUserPermissionRequest) (*RevokeUserPermissionReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserPermission not implemented")
-}
-func (UnimplementedUserServer) mustEmbedUnimplementedUserServer() {}
-
-// UnsafeUserServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to UserServer will
-// result in compilation errors.
-type UnsafeUserServer interface {
-	mustEmbedUnimplementedUserServer()
+	if interceptor == nil {
+		return srv.(UserServer).ReinstateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_ReinstateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ReinstateUser(ctx, req.(*ReinstateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterUserServer(s grpc.ServiceRegistrar, srv UserServer) {
-	s.RegisterService(&User_ServiceDesc, srv)
+func _User_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).DeleteAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_DeleteAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).DeleteAccount(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _User_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterRequest)
+func _User_CancelAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelAccountDeletionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).Register(ctx, in)
+		return srv.(UserServer).CancelAccountDeletion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_Register_FullMethodName,
+		FullMethod: User_CancelAccountDeletion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Register(ctx, req.(*RegisterRequest))
+		return srv.(UserServer).CancelAccountDeletion(ctx, req.(*CancelAccountDeletionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
+func _User_ChangeUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeUsernameRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).Login(ctx, in)
+		return srv.(UserServer).ChangeUsername(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_Login_FullMethodName,
+		FullMethod: User_ChangeUsername_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Login(ctx, req.(*LoginRequest))
+		return srv.(UserServer).ChangeUsername(ctx, req.(*ChangeUsernameRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_UserSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserSettingsRequest)
+func _User_UsernameRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsernameRulesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).UserSettings(ctx, in)
+		return srv.(UserServer).UsernameRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_UserSettings_FullMethodName,
+		FullMethod: User_UsernameRules_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).UserSettings(ctx, req.(*UserSettingsRequest))
+		return srv.(UserServer).UsernameRules(ctx, req.(*UsernameRulesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_SetUserSettingsTheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetUserSettingsThemeRequest)
+func _User_AddUsernameRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddUsernameRuleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).SetUserSettingsTheme(ctx, in)
+		return srv.(UserServer).AddUsernameRule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_SetUserSettingsTheme_FullMethodName,
+		FullMethod: User_AddUsernameRule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).SetUserSettingsTheme(ctx, req.(*SetUserSettingsThemeRequest))
+		return srv.(UserServer).AddUsernameRule(ctx, req.(*AddUsernameRuleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_Users_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UsersRequest)
+func _User_RemoveUsernameRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveUsernameRuleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).Users(ctx, in)
+		return srv.(UserServer).RemoveUsernameRule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_Users_FullMethodName,
+		FullMethod: User_RemoveUsernameRule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Users(ctx, req.(*UsersRequest))
+		return srv.(UserServer).RemoveUsernameRule(ctx, req.(*RemoveUsernameRuleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_UserPermissionDefinitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserPermissionDefinitionsRequest)
+func _User_Themes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).UserPermissionDefinitions(ctx, in)
+		return srv.(UserServer).Themes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_UserPermissionDefinitions_FullMethodName,
+		FullMethod: User_Themes_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).UserPermissionDefinitions(ctx, req.(*UserPermissionDefinitionsRequest))
+		return srv.(UserServer).Themes(ctx, req.(*ThemesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_UserPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserPermissionsRequest)
+func _User_CreateTheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateThemeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).UserPermissions(ctx, in)
+		return srv.(UserServer).CreateTheme(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_UserPermissions_FullMethodName,
+		FullMethod: User_CreateTheme_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).UserPermissions(ctx, req.(*UserPermissionsRequest))
+		return srv.(UserServer).CreateTheme(ctx, req.(*CreateThemeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_GrantUserPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GrantUserPermissionRequest)
+func _User_UpdateTheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateThemeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).GrantUserPermission(ctx, in)
+		return srv.(UserServer).UpdateTheme(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_GrantUserPermission_FullMethodName,
+		FullMethod: User_UpdateTheme_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).GrantUserPermission(ctx, req.(*GrantUserPermissionRequest))
+		return srv.(UserServer).UpdateTheme(ctx, req.(*UpdateThemeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _User_RevokeUserPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RevokeUserPermissionRequest)
+func _User_DeleteTheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteThemeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServer).RevokeUserPermission(ctx, in)
+		return srv.(UserServer).DeleteTheme(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: User_RevokeUserPermission_FullMethodName,
+		FullMethod: User_DeleteTheme_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).RevokeUserPermission(ctx, req.(*RevokeUserPermissionRequest))
+		return srv.(UserServer).DeleteTheme(ctx, req.(*DeleteThemeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _User_ExportUserData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUserDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServer).ExportUserData(m, &userExportUserDataServer{stream})
+}
+
+type User_ExportUserDataServer interface {
+	Send(*ExportUserDataReply) error
+	grpc.ServerStream
+}
+
+type userExportUserDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *userExportUserDataServer) Send(m *ExportUserDataReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // User_ServiceDesc is the grpc.ServiceDesc for User service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -379,10 +1735,38 @@ var User_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetUserSettingsTheme",
 			Handler:    _User_SetUserSettingsTheme_Handler,
 		},
+		{
+			MethodName: "GetSettings",
+			Handler:    _User_GetSettings_Handler,
+		},
+		{
+			MethodName: "UpdateSettings",
+			Handler:    _User_UpdateSettings_Handler,
+		},
 		{
 			MethodName: "Users",
 			Handler:    _User_Users_Handler,
 		},
+		{
+			MethodName: "GetUser",
+			Handler:    _User_GetUser_Handler,
+		},
+		{
+			MethodName: "GetUserByUsername",
+			Handler:    _User_GetUserByUsername_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _User_GetProfile_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _User_UpdateProfile_Handler,
+		},
+		{
+			MethodName: "SearchUsers",
+			Handler:    _User_SearchUsers_Handler,
+		},
 		{
 			MethodName: "UserPermissionDefinitions",
 			Handler:    _User_UserPermissionDefinitions_Handler,
@@ -399,7 +1783,134 @@ var User_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RevokeUserPermission",
 			Handler:    _User_RevokeUserPermission_Handler,
 		},
+		{
+			MethodName: "CheckUserPermission",
+			Handler:    _User_CheckUserPermission_Handler,
+		},
+		{
+			MethodName: "Roles",
+			Handler:    _User_Roles_Handler,
+		},
+		{
+			MethodName: "CreateRole",
+			Handler:    _User_CreateRole_Handler,
+		},
+		{
+			MethodName: "UpdateRole",
+			Handler:    _User_UpdateRole_Handler,
+		},
+		{
+			MethodName: "DeleteRole",
+			Handler:    _User_DeleteRole_Handler,
+		},
+		{
+			MethodName: "UserRoles",
+			Handler:    _User_UserRoles_Handler,
+		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _User_AssignRole_Handler,
+		},
+		{
+			MethodName: "UnassignRole",
+			Handler:    _User_UnassignRole_Handler,
+		},
+		{
+			MethodName: "Groups",
+			Handler:    _User_Groups_Handler,
+		},
+		{
+			MethodName: "CreateGroup",
+			Handler:    _User_CreateGroup_Handler,
+		},
+		{
+			MethodName: "UpdateGroup",
+			Handler:    _User_UpdateGroup_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _User_DeleteGroup_Handler,
+		},
+		{
+			MethodName: "GroupMembers",
+			Handler:    _User_GroupMembers_Handler,
+		},
+		{
+			MethodName: "UserGroups",
+			Handler:    _User_UserGroups_Handler,
+		},
+		{
+			MethodName: "AddGroupMember",
+			Handler:    _User_AddGroupMember_Handler,
+		},
+		{
+			MethodName: "RemoveGroupMember",
+			Handler:    _User_RemoveGroupMember_Handler,
+		},
+		{
+			MethodName: "SuspendUser",
+			Handler:    _User_SuspendUser_Handler,
+		},
+		{
+			MethodName: "BanUser",
+			Handler:    _User_BanUser_Handler,
+		},
+		{
+			MethodName: "ReinstateUser",
+			Handler:    _User_ReinstateUser_Handler,
+		},
+		{
+			MethodName: "DeleteAccount",
+			Handler:    _User_DeleteAccount_Handler,
+		},
+		{
+			MethodName: "CancelAccountDeletion",
+			Handler:    _User_CancelAccountDeletion_Handler,
+		},
+		{
+			MethodName: "ChangeUsername",
+			Handler:    _User_ChangeUsername_Handler,
+		},
+		{
+			MethodName: "UsernameRules",
+			Handler:    _User_UsernameRules_Handler,
+		},
+		{
+			MethodName: "AddUsernameRule",
+			Handler:    _User_AddUsernameRule_Handler,
+		},
+		{
+			MethodName: "RemoveUsernameRule",
+			Handler:    _User_RemoveUsernameRule_Handler,
+		},
+		{
+			MethodName: "Themes",
+			Handler:    _User_Themes_Handler,
+		},
+		{
+			MethodName: "CreateTheme",
+			Handler:    _User_CreateTheme_Handler,
+		},
+		{
+			MethodName: "UpdateTheme",
+			Handler:    _User_UpdateTheme_Handler,
+		},
+		{
+			MethodName: "DeleteTheme",
+			Handler:    _User_DeleteTheme_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUserSettings",
+			Handler:       _User_WatchUserSettings_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportUserData",
+			Handler:       _User_ExportUserData_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
 }
@@ -0,0 +1,68 @@
+-- name: CreateUserDeletion :exec
+INSERT INTO user_deletions (delete_after, request_id, iuid, uid) VALUES (?, ?, ?, ?);
+
+-- name: GetUserDeletion :one
+SELECT * FROM user_deletions WHERE uid = ?;
+
+-- name: DeleteUserDeletion :exec
+DELETE FROM user_deletions WHERE uid = ?;
+
+-- name: ListDueUserDeletions :many
+SELECT * FROM user_deletions WHERE delete_after <= ? ORDER BY delete_after, id LIMIT ?;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = ?;
+
+-- name: AnonymizeUserPermissionGrantsByUID :exec
+UPDATE user_permission_grants SET uid = -1 WHERE uid = ?;
+
+-- name: AnonymizeUserPermissionGrantsByIUID :exec
+UPDATE user_permission_grants SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUserPermissionRevocationsByUID :exec
+UPDATE user_permission_revocations SET uid = -1 WHERE uid = ?;
+
+-- name: AnonymizeUserPermissionRevocationsByIUID :exec
+UPDATE user_permission_revocations SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUserStatusChangesByUID :exec
+UPDATE user_status_changes SET uid = -1, reason = '' WHERE uid = ?;
+
+-- name: AnonymizeUserStatusChangesByIUID :exec
+UPDATE user_status_changes SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUserDeletionsByIUID :exec
+UPDATE user_deletions SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUsernameHistoryByIUID :exec
+UPDATE username_history SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUsernameRulesByIUID :exec
+UPDATE username_rules SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeThemesByIUID :exec
+UPDATE themes SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeRolesByIUID :exec
+UPDATE roles SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUserRolesByIUID :exec
+UPDATE user_roles SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeUserRoleChangesByUID :exec
+UPDATE user_role_changes SET uid = -1 WHERE uid = ?;
+
+-- name: AnonymizeUserRoleChangesByIUID :exec
+UPDATE user_role_changes SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeGroupsByIUID :exec
+UPDATE user_groups SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeGroupMembersByIUID :exec
+UPDATE user_group_members SET iuid = -1 WHERE iuid = ?;
+
+-- name: AnonymizeGroupMemberChangesByUID :exec
+UPDATE user_group_member_changes SET uid = -1 WHERE uid = ?;
+
+-- name: AnonymizeGroupMemberChangesByIUID :exec
+UPDATE user_group_member_changes SET iuid = -1 WHERE iuid = ?;
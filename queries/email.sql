@@ -24,3 +24,6 @@ SELECT COUNT(*) FROM emails WHERE uid = ?;
 
 -- name: DeleteEmail :exec
 DELETE FROM emails WHERE id = ?;
+
+-- name: GetPrimaryEmail :one
+SELECT * FROM emails WHERE uid = ? ORDER BY verified DESC, created_at, id LIMIT 1;
@@ -0,0 +1,47 @@
+-- name: CreateGroup :execresult
+INSERT INTO user_groups (name, about, iuid) VALUES (?, ?, ?);
+
+-- name: GetGroup :one
+SELECT * FROM user_groups WHERE id = ?;
+
+-- name: GetGroupByName :one
+SELECT * FROM user_groups WHERE name = ?;
+
+-- name: ListGroups :many
+SELECT * FROM user_groups ORDER BY name;
+
+-- name: UpdateGroup :exec
+UPDATE user_groups SET name = ?, about = ?, iuid = ? WHERE id = ?;
+
+-- name: DeleteGroup :exec
+DELETE FROM user_groups WHERE id = ?;
+
+-- name: CreateGroupPermission :exec
+INSERT INTO user_group_permissions (name, group_id) VALUES (?, ?);
+
+-- name: ListGroupPermissions :many
+SELECT * FROM user_group_permissions WHERE group_id = ? ORDER BY name;
+
+-- name: DeleteGroupPermissions :exec
+DELETE FROM user_group_permissions WHERE group_id = ?;
+
+-- name: CreateGroupMember :exec
+INSERT INTO user_group_members (group_id, uid, iuid) VALUES (?, ?, ?);
+
+-- name: GetGroupMember :one
+SELECT * FROM user_group_members WHERE uid = ? AND group_id = ?;
+
+-- name: DeleteGroupMember :exec
+DELETE FROM user_group_members WHERE uid = ? AND group_id = ?;
+
+-- name: ListGroupMembers :many
+SELECT * FROM user_group_members WHERE group_id = ? ORDER BY uid;
+
+-- name: ListUserGroups :many
+SELECT user_groups.* FROM user_groups JOIN user_group_members ON user_group_members.group_id = user_groups.id WHERE user_group_members.uid = ? ORDER BY user_groups.name;
+
+-- name: CreateGroupMemberChange :exec
+INSERT INTO user_group_member_changes (action, group_id, group_name, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListGroupMemberChanges :many
+SELECT * FROM user_group_member_changes WHERE uid = sqlc.arg(uid) OR iuid = sqlc.arg(uid) ORDER BY id;
@@ -0,0 +1,10 @@
+-- name: GetUserProfile :one
+SELECT * FROM user_profiles WHERE uid = ?;
+
+-- name: SetUserProfile :exec
+INSERT INTO user_profiles (display_name, bio, pronouns, avatar_url, uid) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(uid) DO UPDATE SET
+  display_name = excluded.display_name,
+  bio = excluded.bio,
+  pronouns = excluded.pronouns,
+  avatar_url = excluded.avatar_url;
@@ -0,0 +1,47 @@
+-- name: CreateRole :execresult
+INSERT INTO roles (name, about, iuid) VALUES (?, ?, ?);
+
+-- name: GetRole :one
+SELECT * FROM roles WHERE id = ?;
+
+-- name: GetRoleByName :one
+SELECT * FROM roles WHERE name = ?;
+
+-- name: ListRoles :many
+SELECT * FROM roles ORDER BY name;
+
+-- name: UpdateRole :exec
+UPDATE roles SET name = ?, about = ?, iuid = ? WHERE id = ?;
+
+-- name: DeleteRole :exec
+DELETE FROM roles WHERE id = ?;
+
+-- name: CreateRolePermission :exec
+INSERT INTO role_permissions (name, role_id) VALUES (?, ?);
+
+-- name: ListRolePermissions :many
+SELECT * FROM role_permissions WHERE role_id = ? ORDER BY name;
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions WHERE role_id = ?;
+
+-- name: CreateUserRole :exec
+INSERT INTO user_roles (role_id, uid, iuid) VALUES (?, ?, ?);
+
+-- name: GetUserRole :one
+SELECT * FROM user_roles WHERE uid = ? AND role_id = ?;
+
+-- name: DeleteUserRole :exec
+DELETE FROM user_roles WHERE uid = ? AND role_id = ?;
+
+-- name: ListUserRoles :many
+SELECT roles.* FROM roles JOIN user_roles ON user_roles.role_id = roles.id WHERE user_roles.uid = ? ORDER BY roles.name;
+
+-- name: CreateUserRoleChange :exec
+INSERT INTO user_role_changes (action, role_id, role_name, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListUserRoleChanges :many
+SELECT * FROM user_role_changes WHERE uid = sqlc.arg(uid) OR iuid = sqlc.arg(uid) ORDER BY id;
+
+-- name: ListUserRolesByRoleID :many
+SELECT * FROM user_roles WHERE role_id = ?;
@@ -0,0 +1,15 @@
+-- name: ListUserSettingValues :many
+SELECT * FROM user_setting_values WHERE uid = ? ORDER BY key;
+
+-- name: SetUserSettingValue :exec
+INSERT INTO user_setting_values (key, value, uid) VALUES (?, ?, ?)
+ON CONFLICT(uid, key) DO UPDATE SET value = excluded.value;
+
+-- name: DeleteUserSettingValue :exec
+DELETE FROM user_setting_values WHERE uid = ? AND key = ?;
+
+-- name: ResetUserSettingValues :exec
+DELETE FROM user_setting_values WHERE key = ? AND value = ?;
+
+-- name: ListUserSettingValueUIDs :many
+SELECT uid FROM user_setting_values WHERE key = ? AND value = ?;
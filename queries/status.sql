@@ -0,0 +1,15 @@
+-- name: GetUserStatus :one
+SELECT * FROM user_statuses WHERE uid = ?;
+
+-- name: SetUserStatus :exec
+INSERT INTO user_statuses (status, reason, until, iuid, uid) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (uid) DO UPDATE SET status = excluded.status, reason = excluded.reason, until = excluded.until, iuid = excluded.iuid;
+
+-- name: DeleteUserStatus :exec
+DELETE FROM user_statuses WHERE uid = ?;
+
+-- name: CreateUserStatusChange :exec
+INSERT INTO user_status_changes (status, reason, until, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListUserStatusChanges :many
+SELECT * FROM user_status_changes WHERE uid = sqlc.arg(uid) OR iuid = sqlc.arg(uid) ORDER BY id;
@@ -0,0 +1,14 @@
+-- name: CreateTheme :exec
+INSERT INTO themes (name, palette, iuid) VALUES (?, ?, ?);
+
+-- name: GetTheme :one
+SELECT * FROM themes WHERE name = ?;
+
+-- name: ListThemes :many
+SELECT * FROM themes ORDER BY name;
+
+-- name: UpdateThemePalette :exec
+UPDATE themes SET palette = ?, iuid = ? WHERE name = ?;
+
+-- name: DeleteTheme :exec
+DELETE FROM themes WHERE name = ?;
@@ -1,5 +1,5 @@
 -- name: CreateUser :execresult
-INSERT INTO users (username, pw_hash) VALUES (?, ?);
+INSERT INTO users (username, username_skeleton, pw_hash) VALUES (?, ?, ?);
 
 -- name: UpdateUserPassword :execresult
 UPDATE users SET pw_hash = ? WHERE id = ?;
@@ -7,6 +7,66 @@ UPDATE users SET pw_hash = ? WHERE id = ?;
 -- name: ListUsers :many
 SELECT * FROM users;
 
+-- name: ListUsersOrderByID :many
+SELECT * FROM users
+WHERE (CAST(sqlc.narg(username_pattern) AS TEXT) IS NULL OR users.username LIKE sqlc.narg(username_pattern) ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = sqlc.narg(permission)) OR sqlc.narg(permission) IS NULL)
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = sqlc.narg(status))
+  AND (users.created_at > sqlc.narg(created_after) OR sqlc.narg(created_after) IS NULL)
+  AND (users.id > sqlc.narg(after_id) OR sqlc.narg(after_id) IS NULL)
+ORDER BY id
+LIMIT sqlc.arg(limit);
+
+-- name: ListUsersOrderByIDDesc :many
+SELECT * FROM users
+WHERE (CAST(sqlc.narg(username_pattern) AS TEXT) IS NULL OR users.username LIKE sqlc.narg(username_pattern) ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = sqlc.narg(permission)) OR sqlc.narg(permission) IS NULL)
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = sqlc.narg(status))
+  AND (users.created_at > sqlc.narg(created_after) OR sqlc.narg(created_after) IS NULL)
+  AND (users.id < sqlc.narg(after_id) OR sqlc.narg(after_id) IS NULL)
+ORDER BY id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListUsersOrderByUsername :many
+SELECT * FROM users
+WHERE (CAST(sqlc.narg(username_pattern) AS TEXT) IS NULL OR users.username LIKE sqlc.narg(username_pattern) ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = sqlc.narg(permission)) OR sqlc.narg(permission) IS NULL)
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = sqlc.narg(status))
+  AND (users.created_at > sqlc.narg(created_after) OR sqlc.narg(created_after) IS NULL)
+  AND (users.username > sqlc.narg(after_username) OR (users.username = sqlc.narg(after_username) AND users.id > sqlc.narg(after_id)) OR sqlc.narg(after_username) IS NULL)
+ORDER BY username, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListUsersOrderByUsernameDesc :many
+SELECT * FROM users
+WHERE (CAST(sqlc.narg(username_pattern) AS TEXT) IS NULL OR users.username LIKE sqlc.narg(username_pattern) ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = sqlc.narg(permission)) OR sqlc.narg(permission) IS NULL)
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = sqlc.narg(status))
+  AND (users.created_at > sqlc.narg(created_after) OR sqlc.narg(created_after) IS NULL)
+  AND (users.username < sqlc.narg(after_username) OR (users.username = sqlc.narg(after_username) AND users.id < sqlc.narg(after_id)) OR sqlc.narg(after_username) IS NULL)
+ORDER BY username DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListUsersOrderByCreatedAt :many
+SELECT * FROM users
+WHERE (CAST(sqlc.narg(username_pattern) AS TEXT) IS NULL OR users.username LIKE sqlc.narg(username_pattern) ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = sqlc.narg(permission)) OR sqlc.narg(permission) IS NULL)
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = sqlc.narg(status))
+  AND (users.created_at > sqlc.narg(created_after) OR sqlc.narg(created_after) IS NULL)
+  AND (users.created_at > sqlc.narg(after_created_at) OR (users.created_at = sqlc.narg(after_created_at) AND users.id > sqlc.narg(after_id)) OR sqlc.narg(after_created_at) IS NULL)
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListUsersOrderByCreatedAtDesc :many
+SELECT * FROM users
+WHERE (CAST(sqlc.narg(username_pattern) AS TEXT) IS NULL OR users.username LIKE sqlc.narg(username_pattern) ESCAPE '!')
+  AND (EXISTS (SELECT 1 FROM user_permissions AS p WHERE p.uid = users.id AND p.name = sqlc.narg(permission)) OR sqlc.narg(permission) IS NULL)
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR coalesce((SELECT s.status FROM user_statuses AS s WHERE s.uid = users.id AND (s.until IS NULL OR s.until > unixepoch('now'))), 'active') = sqlc.narg(status))
+  AND (users.created_at > sqlc.narg(created_after) OR sqlc.narg(created_after) IS NULL)
+  AND (users.created_at < sqlc.narg(after_created_at) OR (users.created_at = sqlc.narg(after_created_at) AND users.id < sqlc.narg(after_id)) OR sqlc.narg(after_created_at) IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
 -- name: GetUser :one
 SELECT * FROM users WHERE id = ?;
 
@@ -23,10 +83,13 @@ SELECT username FROM users WHERE id = ?;
 SELECT * FROM users WHERE username LIKE ?;
 
 -- name: CreateUserPermission :execresult
-INSERT INTO user_permissions (name, uid, iuid) VALUES (?, ?, ?);
+INSERT INTO user_permissions (name, uid, iuid, expires_at, scope) VALUES (?, ?, ?, ?, ?);
 
 -- name: GetUserPermissionByName :one
-SELECT * FROM user_permissions WHERE name = ? AND uid = ?;
+SELECT * FROM user_permissions WHERE name = ? AND uid = ? AND scope = ?;
+
+-- name: UpdateUserPermissionExpiry :exec
+UPDATE user_permissions SET expires_at = ?, iuid = ? WHERE id = ?;
 
 -- name: DeleteUserPermission :exec
 DELETE FROM user_permissions WHERE id = ?;
@@ -38,19 +101,20 @@ SELECT * FROM user_permissions WHERE name = ?;
 DELETE FROM user_permissions WHERE name = ?;
 
 -- name: ListUserPermissions :many
-SELECT * FROM user_permissions WHERE uid = ?;
+SELECT * FROM user_permissions WHERE uid = ? AND (expires_at IS NULL OR expires_at > CAST(sqlc.arg(now) AS INTEGER));
+
+-- name: ListExpiredUserPermissions :many
+SELECT * FROM user_permissions WHERE expires_at <= CAST(sqlc.arg(now) AS INTEGER) ORDER BY expires_at LIMIT sqlc.arg(limit);
 
 -- name: CreateUserPermissionGrant :exec
-INSERT INTO user_permission_grants (name, uid, iuid) VALUES (?, ?, ?);
+INSERT INTO user_permission_grants (name, uid, iuid, request_id, scope, expires_at) VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: CreateUserPermissionRevocation :exec
-INSERT INTO user_permission_revocations (name, uid, iuid) VALUES (?, ?, ?);
+INSERT INTO user_permission_revocations (name, uid, iuid, request_id, scope) VALUES (?, ?, ?, ?, ?);
 
--- name: CreateUserSettings :exec
-INSERT INTO user_settings (theme, uid) VALUES (?, ?);
+-- name: ListUserPermissionGrants :many
+SELECT * FROM user_permission_grants WHERE uid = sqlc.arg(uid) OR iuid = sqlc.arg(uid) ORDER BY id;
 
--- name: GetUserSettings :one
-SELECT * FROM user_settings WHERE uid = ?;
+-- name: ListUserPermissionRevocations :many
+SELECT * FROM user_permission_revocations WHERE uid = sqlc.arg(uid) OR iuid = sqlc.arg(uid) ORDER BY id;
 
--- name: UpdateUserSettingsTheme :exec
-UPDATE user_settings SET theme = ? WHERE uid = ?;
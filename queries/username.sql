@@ -0,0 +1,35 @@
+-- name: UpdateUsername :exec
+UPDATE users SET username = ?, username_skeleton = ? WHERE id = ?;
+
+-- name: GetUserByUsernameSkeleton :one
+SELECT * FROM users WHERE username_skeleton = ?;
+
+-- name: CreateUsernameHistory :exec
+INSERT INTO username_history (old_username, old_username_skeleton, new_username, reserved_until, request_id, iuid, uid) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLatestOwnUsernameHistory :one
+SELECT * FROM username_history WHERE uid = sqlc.arg(uid) AND iuid = sqlc.arg(uid) ORDER BY created_at DESC, id DESC LIMIT 1;
+
+-- name: ListUsernameHistory :many
+SELECT * FROM username_history WHERE uid = ? ORDER BY created_at DESC, id DESC;
+
+-- name: GetUsernameReservation :one
+SELECT * FROM username_history
+WHERE old_username_skeleton = sqlc.arg(skeleton) AND reserved_until > sqlc.arg(now)
+ORDER BY reserved_until DESC
+LIMIT 1;
+
+-- name: CreateUsernameRule :execresult
+INSERT INTO username_rules (kind, pattern, reason, iuid) VALUES (?, ?, ?, ?);
+
+-- name: GetUsernameRule :one
+SELECT * FROM username_rules WHERE id = ?;
+
+-- name: GetUsernameRuleByPattern :one
+SELECT * FROM username_rules WHERE kind = ? AND pattern = ?;
+
+-- name: ListUsernameRules :many
+SELECT * FROM username_rules ORDER BY kind, pattern;
+
+-- name: DeleteUsernameRule :exec
+DELETE FROM username_rules WHERE id = ?;
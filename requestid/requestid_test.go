@@ -0,0 +1,44 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIsValidAndUnique(t *testing.T) {
+	a, b := New(), New()
+	require.True(t, IsValid(a))
+	require.NotEqual(t, a, b)
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{"web-1234", true},
+		{"6f1c1b3e-1c4e-4a0b-9f77-2f3f5b7e9c10", true},
+		{"", false},
+		{"has space", false},
+		{"line\nbreak", false},
+		{"ünïcode", false},
+		{strings.Repeat("a", MaxLength), true},
+		{strings.Repeat("a", MaxLength+1), false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.valid, IsValid(test.id), test.id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	require.False(t, ok)
+
+	id, ok := FromContext(NewContext(context.Background(), "web-1234"))
+	require.True(t, ok)
+	require.Equal(t, "web-1234", id)
+}
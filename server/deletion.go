@@ -0,0 +1,15 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/afteralec/grpc-user/services/user"
+)
+
+// eraseDueAccounts erases accounts past their deletion grace period at startup and then every interval,
+// until ctx is done.
+func eraseDueAccounts(ctx context.Context, logger *slog.Logger, us *user.Service, interval time.Duration) {
+	sweep(ctx, logger, "erase due accounts", interval, user.EraseBatchSize, us.EraseDueAccounts)
+}
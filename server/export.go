@@ -0,0 +1,212 @@
+package server
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/afteralec/grpc-user/proto"
+	"github.com/afteralec/grpc-user/services/user"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize keeps each ExportUserDataReply well under the default 4MiB message limit.
+const exportChunkSize = 64 * 1024
+
+var exportContentTypes = map[string]string{
+	"json": "application/json",
+	"zip":  "application/zip",
+}
+
+func (s *server) ExportUserData(in *proto.ExportUserDataRequest, stream proto.User_ExportUserDataServer) error {
+	format := in.Format
+	if format == "" {
+		format = "json"
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return status.Error(codes.InvalidArgument, "the export format must be json or zip")
+	}
+
+	data, err := s.user.ExportUserData(stream.Context(), in.Uid, in.Iuid)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			return status.Error(codes.NotFound, "no user exists with this id")
+		case errors.Is(err, user.ErrCannotExportUserData):
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		// TODO: Implement Error Details
+		return status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	w := &exportWriter{stream: stream, contentType: contentType}
+	if format == "zip" {
+		err = writeUserDataZip(w, data)
+	} else {
+		err = json.NewEncoder(w).Encode(data)
+	}
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// writeUserDataZip writes an archive with a JSON file for each kind of data, dated when the export was taken.
+func writeUserDataZip(w io.Writer, data *user.UserData) error {
+	files := []struct {
+		name  string
+		value any
+	}{
+		{"user.json", data.User},
+		{"profile.json", data.Profile},
+		{"settings.json", data.Settings},
+		{"username_history.json", data.UsernameHistory},
+		{"emails.json", data.Emails},
+		{"permissions.json", data.Permissions},
+		{"permission_grants.json", data.PermissionGrants},
+		{"permission_revocations.json", data.PermissionRevocations},
+		{"roles.json", data.Roles},
+		{"role_changes.json", data.RoleChanges},
+		{"groups.json", data.Groups},
+		{"group_changes.json", data.GroupChanges},
+		{"status.json", data.Status},
+		{"status_changes.json", data.StatusChanges},
+	}
+	if data.Deletion != nil {
+		files = append(files, struct {
+			name  string
+			value any
+		}{"deletion.json", data.Deletion})
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: data.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.value); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// exportWriter sends what's written to it as ExportUserDataReply messages of exportChunkSize bytes.
+// Flush sends whatever is left over.
+type exportWriter struct {
+	stream      proto.User_ExportUserDataServer
+	contentType string
+	buf         []byte
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= exportChunkSize {
+		if err := w.send(w.buf[:exportChunkSize]); err != nil {
+			return 0, err
+		}
+		w.buf = append(w.buf[:0], w.buf[exportChunkSize:]...)
+	}
+	return len(p), nil
+}
+
+func (w *exportWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+func (w *exportWriter) send(data []byte) error {
+	return w.stream.Send(&proto.ExportUserDataReply{ContentType: w.contentType, Data: data})
+}
+
+// exportGatewayHandler serves ExportUserData over REST as a file download, writing the streamed
+// chunks back to back with the export's content type.
+func exportGatewayHandler(mux *runtime.ServeMux, client proto.UserClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, "/user.User/ExportUserData", runtime.WithHTTPPathPattern("/v1/users/{uid}/export"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		in := &proto.ExportUserDataRequest{Format: r.URL.Query().Get("format")}
+		if in.Uid, err = strconv.ParseInt(pathParams["uid"], 10, 64); err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "uid must be an integer"))
+			return
+		}
+		if iuid := r.URL.Query().Get("iuid"); iuid != "" {
+			if in.Iuid, err = strconv.ParseInt(iuid, 10, 64); err != nil {
+				runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "iuid must be an integer"))
+				return
+			}
+		}
+
+		stream, err := client.ExportUserData(ctx, in)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		header, err := stream.Header()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: header})
+
+		// Errors come back on the first receive, before anything has been written.
+		reply, err := stream.Recv()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		for key, values := range header {
+			if name, ok := gatewayOutgoingHeader(key); ok {
+				for _, value := range values {
+					w.Header().Add(name, value)
+				}
+			}
+		}
+		extension := "json"
+		if reply.ContentType == exportContentTypes["zip"] {
+			extension = "zip"
+		}
+		w.Header().Set("Content-Type", reply.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d.%s"`, in.Uid, extension))
+
+		for {
+			if _, err := w.Write(reply.Data); err != nil {
+				return
+			}
+			reply, err = stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "gateway: export stream failed after the response started", "err", err)
+				return
+			}
+		}
+	}
+}
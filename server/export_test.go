@@ -0,0 +1,137 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/afteralec/grpc-user/requestid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func readExport(t *testing.T, client pb.UserClient, in *pb.ExportUserDataRequest) ([]byte, string, error) {
+	stream, err := client.ExportUserData(context.Background(), in)
+	require.NoError(t, err)
+	var data []byte
+	var contentType string
+	for {
+		reply, err := stream.Recv()
+		if err == io.EOF {
+			return data, contentType, nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		contentType = reply.ContentType
+		data = append(data, reply.Data...)
+	}
+}
+
+func TestExportUserData(t *testing.T) {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryRequestIDInterceptor()),
+		grpc.ChainStreamInterceptor(streamRequestIDInterceptor()),
+	)
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	conn := newTestConn(t, s)
+	client := pb.NewUserClient(conn)
+
+	reply, err := client.Register(context.Background(), &pb.RegisterRequest{Username: "testexport", Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(context.Background(), &pb.RegisterRequest{Username: "testnosy", Password: TestPassword})
+	require.NoError(t, err)
+
+	t.Run("JSON", func(t *testing.T) {
+		data, contentType, err := readExport(t, client, &pb.ExportUserDataRequest{Uid: reply.Id, Iuid: reply.Id})
+		require.NoError(t, err)
+		require.Equal(t, "application/json", contentType)
+		require.NotContains(t, string(data), "pw_hash")
+		require.NotContains(t, string(data), "argon2")
+
+		var export map[string]any
+		require.NoError(t, json.Unmarshal(data, &export))
+		require.Equal(t, "testexport", export["user"].(map[string]any)["username"])
+		require.Equal(t, "active", export["status"].(map[string]any)["status"])
+	})
+
+	t.Run("Zip", func(t *testing.T) {
+		data, contentType, err := readExport(t, client, &pb.ExportUserDataRequest{Uid: reply.Id, Iuid: reply.Id, Format: "zip"})
+		require.NoError(t, err)
+		require.Equal(t, "application/zip", contentType)
+
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		names := []string{}
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+		}
+		require.Contains(t, names, "user.json")
+		require.Contains(t, names, "permission_grants.json")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, _, err := readExport(t, client, &pb.ExportUserDataRequest{Uid: reply.Id, Iuid: other.Id})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, _, err = readExport(t, client, &pb.ExportUserDataRequest{Uid: reply.Id, Iuid: reply.Id, Format: "xml"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Gateway", func(t *testing.T) {
+		gateway, err := newGateway(context.Background(), conn)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+strconv.FormatInt(reply.Id, 10)+"/export?iuid="+strconv.FormatInt(reply.Id, 10)+"&format=zip", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		require.Equal(t, `attachment; filename="user-`+strconv.FormatInt(reply.Id, 10)+`.zip"`, w.Header().Get("Content-Disposition"))
+		require.True(t, requestid.IsValid(w.Header().Get("X-Request-Id")))
+		_, err = zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		require.NoError(t, err)
+
+		w = httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+strconv.FormatInt(reply.Id, 10)+"/export?iuid="+strconv.FormatInt(other.Id, 10), nil))
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.True(t, requestid.IsValid(w.Header().Get("X-Request-Id")))
+	})
+}
+
+type fakeExportStream struct {
+	grpc.ServerStream
+	replies []*pb.ExportUserDataReply
+}
+
+func (s *fakeExportStream) Send(reply *pb.ExportUserDataReply) error {
+	s.replies = append(s.replies, &pb.ExportUserDataReply{ContentType: reply.ContentType, Data: append([]byte{}, reply.Data...)})
+	return nil
+}
+
+func TestExportWriterChunks(t *testing.T) {
+	stream := &fakeExportStream{}
+	w := &exportWriter{stream: stream, contentType: "application/json"}
+	data := bytes.Repeat([]byte("x"), 2*exportChunkSize+10)
+	_, err := w.Write(data[:100])
+	require.NoError(t, err)
+	_, err = w.Write(data[100:])
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+
+	require.Len(t, stream.replies, 3)
+	require.Len(t, stream.replies[0].Data, exportChunkSize)
+	require.Len(t, stream.replies[2].Data, 10)
+	joined := []byte{}
+	for _, reply := range stream.replies {
+		joined = append(joined, reply.Data...)
+	}
+	require.Equal(t, data, joined)
+}
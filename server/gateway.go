@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/afteralec/grpc-user/requestid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var requestIDHeader = http.CanonicalHeaderKey(requestid.Key)
+
+// gatewayError is the JSON body written for every failed gateway request.
+type gatewayError struct {
+	Code    int32             `json:"code"`
+	Status  string            `json:"status"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details"`
+}
+
+func newGateway(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+		runtime.WithErrorHandler(gatewayErrorHandler),
+		runtime.WithIncomingHeaderMatcher(gatewayIncomingHeader),
+		runtime.WithOutgoingHeaderMatcher(gatewayOutgoingHeader),
+		runtime.WithHealthzEndpoint(healthpb.NewHealthClient(conn)),
+	)
+	if err := pb.RegisterUserHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := mux.HandlePath(http.MethodGet, "/v1/users/{uid}/export", exportGatewayHandler(mux, pb.NewUserClient(conn))); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+func gatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := 0
+	var customStatus *runtime.HTTPStatusError
+	if errors.As(err, &customStatus) {
+		httpStatus = customStatus.HTTPStatus
+		err = customStatus.Err
+	}
+
+	s := status.Convert(err)
+	if httpStatus == 0 {
+		httpStatus = runtime.HTTPStatusFromCode(s.Code())
+	}
+
+	body := gatewayError{
+		Code:    int32(s.Code()),
+		Status:  code.Code(s.Code()).String(),
+		Message: s.Message(),
+		Details: []json.RawMessage{},
+	}
+	for _, detail := range s.Proto().GetDetails() {
+		b, err := protojson.Marshal(detail)
+		if err != nil {
+			slog.ErrorContext(ctx, "gateway: failed to marshal error detail", "type_url", detail.GetTypeUrl(), "err", err)
+			continue
+		}
+		body.Details = append(body.Details, b)
+	}
+
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		if ids := md.HeaderMD.Get(requestid.Key); len(ids) > 0 {
+			w.Header().Set(requestIDHeader, ids[0])
+		}
+	}
+	w.Header().Del("Trailer")
+	w.Header().Del("Transfer-Encoding")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.ErrorContext(ctx, "gateway: failed to write error response", "err", err)
+	}
+}
+
+func gatewayIncomingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, requestid.Key) {
+		return requestid.Key, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func gatewayOutgoingHeader(key string) (string, bool) {
+	if key == requestid.Key {
+		return requestIDHeader, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGatewayErrorHandler(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "the username provided isn't valid").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "username", Description: "too long"},
+		},
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/users", nil)
+	gatewayErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, st.Err())
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body struct {
+		Code    int32            `json:"code"`
+		Status  string           `json:"status"`
+		Message string           `json:"message"`
+		Details []map[string]any `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, int32(codes.InvalidArgument), body.Code)
+	require.Equal(t, "INVALID_ARGUMENT", body.Status)
+	require.Equal(t, "the username provided isn't valid", body.Message)
+	require.Len(t, body.Details, 1)
+	require.Equal(t, "type.googleapis.com/google.rpc.BadRequest", body.Details[0]["@type"])
+}
+
+func TestGatewayErrorHandlerWithoutDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/1/settings", nil)
+	gatewayErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, status.Error(codes.Unauthenticated, "nope"))
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.JSONEq(t, `{"code":16,"status":"UNAUTHENTICATED","message":"nope","details":[]}`, w.Body.String())
+}
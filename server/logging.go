@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/afteralec/grpc-user/config"
+	"github.com/afteralec/grpc-user/requestid"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newLogger(cfg config.Log, w io.Writer) *slog.Logger {
+	var level slog.Level
+	switch cfg.Level {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "text" {
+		return slog.New(contextHandler{slog.NewTextHandler(w, opts)})
+	}
+	return slog.New(contextHandler{slog.NewJSONHandler(w, opts)})
+}
+
+// contextHandler adds the request ID to every record logged with a request's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := requestid.FromContext(ctx); ok {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+type logFieldsKey struct{}
+
+// logFields collects attributes that handlers learn while serving a request, such as the uid
+// of a user who just logged in, so the interceptor can include them in the request's log line.
+type logFields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+func withLogFields(ctx context.Context) (context.Context, *logFields) {
+	fields := &logFields{}
+	return context.WithValue(ctx, logFieldsKey{}, fields), fields
+}
+
+// addLogAttrs attaches attrs to the log line for the request in ctx.
+func addLogAttrs(ctx context.Context, attrs ...slog.Attr) {
+	fields, ok := ctx.Value(logFieldsKey{}).(*logFields)
+	if !ok {
+		return
+	}
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	fields.attrs = append(fields.attrs, attrs...)
+}
+
+func (f *logFields) list() []slog.Attr {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slog.Attr{}, f.attrs...)
+}
+
+// issuer is implemented by requests made on behalf of another user, like GrantUserPermission.
+// The iuid is whoever the caller claims to be, so it is logged apart from the authenticated uid.
+type issuer interface {
+	GetIuid() int64
+}
+
+// Request and reply messages are never logged, since several of them carry passphrases.
+func unaryLoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, fields := withLogFields(ctx)
+		if r, ok := req.(issuer); ok {
+			addLogAttrs(ctx, slog.Int64("iuid", r.GetIuid()))
+		}
+
+		reply, err := handler(ctx, req)
+		logRequest(ctx, logger, info.FullMethod, start, fields, err)
+		return reply, err
+	}
+}
+
+func streamLoggingInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, fields := withLogFields(ss.Context())
+
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		logRequest(ctx, logger, info.FullMethod, start, fields, err)
+		return err
+	}
+}
+
+// contextServerStream overrides the context of a wrapped grpc.ServerStream.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func logRequest(ctx context.Context, logger *slog.Logger, method string, start time.Time, fields *logFields, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", strings.TrimPrefix(method, "/")),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("code", code.String()),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+	attrs = append(attrs, fields.list()...)
+	if err != nil {
+		attrs = append(attrs, slog.String("err", status.Convert(err).Message()))
+	}
+
+	logger.LogAttrs(ctx, logLevel(code), "rpc", attrs...)
+}
+
+func logLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/afteralec/grpc-user/config"
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestLoggedClient(t *testing.T, logs *bytes.Buffer) pb.UserClient {
+	logger := newLogger(config.Log{Format: "json", Level: "info"}, logs)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLoggingInterceptor(logger)),
+		grpc.ChainStreamInterceptor(streamLoggingInterceptor(logger)),
+	)
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	return newTestClient(t, s)
+}
+
+// newTestClient serves s over an in-memory listener and returns a client connected to it.
+func newTestClient(t *testing.T, s *grpc.Server) pb.UserClient {
+	return pb.NewUserClient(newTestConn(t, s))
+}
+
+func newTestConn(t *testing.T, s *grpc.Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn
+}
+
+func logLines(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	lines := []map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		entry := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	logs := &bytes.Buffer{}
+	client := newTestLoggedClient(t, logs)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testlogging", Password: TestPassword})
+	require.NoError(t, err)
+	_, err = client.Login(ctx, &pb.LoginRequest{Username: "testlogging", Password: TestPassword})
+	require.NoError(t, err)
+	_, err = client.Login(ctx, &pb.LoginRequest{Username: "testlogging", Password: "Wr0ng_passphrase"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	require.NotContains(t, logs.String(), TestPassword)
+	require.NotContains(t, logs.String(), "Wr0ng_passphrase")
+
+	lines := logLines(t, logs)
+	require.Len(t, lines, 3)
+
+	require.Equal(t, "user.User/Register", lines[0]["method"])
+	require.Equal(t, codes.OK.String(), lines[0]["code"])
+	require.Equal(t, "INFO", lines[0]["level"])
+	require.Contains(t, lines[0], "duration")
+	require.Contains(t, lines[0], "peer")
+
+	require.Equal(t, "user.User/Login", lines[1]["method"])
+	require.Equal(t, codes.OK.String(), lines[1]["code"])
+	require.Equal(t, float64(reply.Id), lines[1]["uid"])
+
+	require.Equal(t, "user.User/Login", lines[2]["method"])
+	require.Equal(t, codes.Unauthenticated.String(), lines[2]["code"])
+	require.Equal(t, "WARN", lines[2]["level"])
+	require.NotContains(t, lines[2], "uid")
+
+	// The iuid on a request is only the caller's claim, so it's never logged as the uid.
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: reply.Id, Iuid: reply.Id + 1, Name: "grant-all"})
+	require.Error(t, err)
+	lines = logLines(t, logs)
+	require.Len(t, lines, 4)
+	require.Equal(t, float64(reply.Id+1), lines[3]["iuid"])
+	require.NotContains(t, lines[3], "uid")
+}
+
+func TestNewLoggerFormatAndLevel(t *testing.T) {
+	logs := &bytes.Buffer{}
+	logger := newLogger(config.Log{Format: "text", Level: "warn"}, logs)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "uid", 1)
+
+	require.NotContains(t, logs.String(), "dropped")
+	require.Contains(t, logs.String(), "level=WARN msg=kept uid=1")
+}
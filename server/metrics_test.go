@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRPCMetrics(t *testing.T) {
+	rpc := newRPCMetrics()
+	reg, err := newRegistry(newTestDB(t), rpc)
+	require.NoError(t, err)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpc.unaryInterceptor()),
+		grpc.ChainStreamInterceptor(rpc.streamInterceptor()),
+	)
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	_, err = client.Register(ctx, &pb.RegisterRequest{Username: "testmetrics", Password: TestPassword})
+	require.NoError(t, err)
+	_, err = client.Login(ctx, &pb.LoginRequest{Username: "testmetrics", Password: "Wr0ng_passphrase"})
+	require.Error(t, err)
+
+	require.Equal(t, float64(1), testutil.ToFloat64(rpc.handled.WithLabelValues("user.User", "Register", codes.OK.String())))
+	require.Equal(t, float64(1), testutil.ToFloat64(rpc.handled.WithLabelValues("user.User", "Login", codes.Unauthenticated.String())))
+	require.Equal(t, 2, testutil.CollectAndCount(rpc.duration))
+
+	w := httptest.NewRecorder()
+	newMetricsHandler(reg).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `grpc_server_handled_total{grpc_code="Unauthenticated",grpc_method="Login",grpc_service="user.User"} 1`)
+	require.Contains(t, w.Body.String(), `go_sql_open_connections{db_name="user"}`)
+}
+
+func TestSplitMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		service    string
+		method     string
+	}{
+		{"/user.User/Login", "user.User", "Login"},
+		{"/grpc.health.v1.Health/Check", "grpc.health.v1.Health", "Check"},
+		{"nonsense", "unknown", "unknown"},
+	}
+
+	for _, test := range tests {
+		service, method := splitMethod(test.fullMethod)
+		require.Equal(t, test.service, service)
+		require.Equal(t, test.method, method)
+	}
+}
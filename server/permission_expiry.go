@@ -0,0 +1,15 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/afteralec/grpc-user/services/user"
+)
+
+// expireUserPermissions revokes permission grants past their expiry at startup and then every interval,
+// until ctx is done.
+func expireUserPermissions(ctx context.Context, logger *slog.Logger, us *user.Service, interval time.Duration) {
+	sweep(ctx, logger, "expire user permissions", interval, user.ExpireBatchSize, us.ExpireUserPermissions)
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+
+	"connectrpc.com/vanguard/vanguardgrpc"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// newProtocolHandler serves every service registered on s over gRPC, gRPC-Web and Connect.
+// Requests in protocols other than gRPC are transcoded, so they pass through the same
+// server options and interceptors as native gRPC calls.
+func newProtocolHandler(s *grpc.Server) (http.Handler, error) {
+	transcoder, err := vanguardgrpc.NewTranscoder(s)
+	if err != nil {
+		return nil, err
+	}
+	return h2c.NewHandler(transcoder, &http2.Server{}), nil
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/afteralec/grpc-user/db"
+	"github.com/afteralec/grpc-user/migrations"
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/afteralec/grpc-user/services/user"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	TestRootUsername = "tested"
+	TestPassword     = "T3sted_tested"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	conn, err := db.Open(filepath.Join(t.TempDir(), "user.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	entries, err := fs.Glob(migrations.FS, "*.sql")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		b, err := fs.ReadFile(migrations.FS, entry)
+		require.NoError(t, err)
+		_, err = conn.Exec(string(b))
+		require.NoError(t, err, entry)
+	}
+	return conn
+}
+
+func newTestService(t *testing.T, conn *sql.DB) *user.Service {
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	us, err := user.New(conn, user.WithConfig(config))
+	require.NoError(t, err)
+	return &us
+}
+
+func newTestProtocolServer(t *testing.T) *httptest.Server {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	handler, err := newProtocolHandler(s)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// protocolClient calls the User service the way one kind of client would.
+type protocolClient struct {
+	name     string
+	register func(ctx context.Context, in *pb.RegisterRequest) (*pb.RegisterReply, error)
+	defs     func(ctx context.Context, in *pb.UserPermissionDefinitionsRequest) (*pb.UserPermissionDefinitionsReply, error)
+}
+
+func newConnectClient(name string, httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) protocolClient {
+	register := connect.NewClient[pb.RegisterRequest, pb.RegisterReply](httpClient, baseURL+"/user.User/Register", opts...)
+	defs := connect.NewClient[pb.UserPermissionDefinitionsRequest, pb.UserPermissionDefinitionsReply](httpClient, baseURL+"/user.User/UserPermissionDefinitions", opts...)
+	return protocolClient{
+		name: name,
+		register: func(ctx context.Context, in *pb.RegisterRequest) (*pb.RegisterReply, error) {
+			res, err := register.CallUnary(ctx, connect.NewRequest(in))
+			if err != nil {
+				return nil, err
+			}
+			return res.Msg, nil
+		},
+		defs: func(ctx context.Context, in *pb.UserPermissionDefinitionsRequest) (*pb.UserPermissionDefinitionsReply, error) {
+			res, err := defs.CallUnary(ctx, connect.NewRequest(in))
+			if err != nil {
+				return nil, err
+			}
+			return res.Msg, nil
+		},
+	}
+}
+
+func errorCode(err error) codes.Code {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return codes.Code(connectErr.Code())
+	}
+	return status.Code(err)
+}
+
+func TestProtocolConformance(t *testing.T) {
+	ts := newTestProtocolServer(t)
+
+	h2cClient := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(ts.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	native := pb.NewUserClient(conn)
+
+	clients := []protocolClient{
+		{
+			name: "native gRPC",
+			register: func(ctx context.Context, in *pb.RegisterRequest) (*pb.RegisterReply, error) {
+				return native.Register(ctx, in)
+			},
+			defs: func(ctx context.Context, in *pb.UserPermissionDefinitionsRequest) (*pb.UserPermissionDefinitionsReply, error) {
+				return native.UserPermissionDefinitions(ctx, in)
+			},
+		},
+		newConnectClient("gRPC over h2c", h2cClient, ts.URL, connect.WithGRPC()),
+		newConnectClient("gRPC-Web", ts.Client(), ts.URL, connect.WithGRPCWeb()),
+		newConnectClient("Connect", ts.Client(), ts.URL),
+		newConnectClient("Connect JSON", ts.Client(), ts.URL, connect.WithProtoJSON()),
+	}
+
+	for i, client := range clients {
+		client := client
+		username := "testproto" + string(rune('a'+i))
+		t.Run(client.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			reply, err := client.register(ctx, &pb.RegisterRequest{Username: username, Password: TestPassword})
+			require.NoError(t, err)
+			require.Greater(t, reply.Id, int64(0))
+
+			_, err = client.register(ctx, &pb.RegisterRequest{Username: "testfieduntiltheendoftime", Password: TestPassword})
+			require.Error(t, err)
+			require.Equal(t, codes.InvalidArgument, errorCode(err))
+
+			defs, err := client.defs(ctx, &pb.UserPermissionDefinitionsRequest{})
+			require.NoError(t, err)
+			require.Len(t, defs.Permissions, len(user.AllPermissions))
+		})
+	}
+}
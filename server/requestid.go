@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+
+	"github.com/afteralec/grpc-user/requestid"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// incomingRequestID returns the caller's x-request-id, or a new one if it's missing or unusable.
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestid.Key); len(ids) > 0 && requestid.IsValid(ids[0]) {
+			return ids[0]
+		}
+	}
+	return requestid.New()
+}
+
+// withRequestInfo adds the request ID to the error details sent back to the caller.
+func withRequestInfo(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	detailed, derr := st.WithDetails(&errdetails.RequestInfo{RequestId: id})
+	if derr != nil {
+		return err
+	}
+	return detailed.Err()
+}
+
+func unaryRequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := incomingRequestID(ctx)
+		ctx = requestid.NewContext(ctx, id)
+		grpc.SetHeader(ctx, metadata.Pairs(requestid.Key, id))
+
+		reply, err := handler(ctx, req)
+		return reply, withRequestInfo(err, id)
+	}
+}
+
+func streamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incomingRequestID(ss.Context())
+		ctx := requestid.NewContext(ss.Context(), id)
+		ss.SetHeader(metadata.Pairs(requestid.Key, id))
+
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		return withRequestInfo(err, id)
+	}
+}
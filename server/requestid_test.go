@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/afteralec/grpc-user/config"
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/afteralec/grpc-user/requestid"
+	"github.com/afteralec/grpc-user/services/user"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func requestInfo(t *testing.T, err error) *errdetails.RequestInfo {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RequestInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("no RequestInfo in the details of %v", err)
+	return nil
+}
+
+func TestRequestID(t *testing.T) {
+	logs := &bytes.Buffer{}
+	logger := newLogger(config.Log{Format: "json", Level: "info"}, logs)
+	conn := newTestDB(t)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryRequestIDInterceptor(), unaryLoggingInterceptor(logger)),
+		grpc.ChainStreamInterceptor(streamRequestIDInterceptor(), streamLoggingInterceptor(logger)),
+	)
+	pb.RegisterUserServer(s, &server{user: newTestService(t, conn)})
+	client := newTestClient(t, s)
+
+	root, err := client.Register(context.Background(), &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	reply, err := client.Register(context.Background(), &pb.RegisterRequest{Username: "testrequestid", Password: TestPassword})
+	require.NoError(t, err)
+	name := user.PermissionViewAllRooms.Name
+
+	t.Run("Generated", func(t *testing.T) {
+		var header metadata.MD
+		_, err := client.UserPermissionDefinitions(context.Background(), &pb.UserPermissionDefinitionsRequest{}, grpc.Header(&header))
+		require.NoError(t, err)
+		ids := header.Get(requestid.Key)
+		require.Len(t, ids, 1)
+		require.True(t, requestid.IsValid(ids[0]))
+	})
+
+	t.Run("FailingGrant", func(t *testing.T) {
+		logs.Reset()
+		ctx := metadata.AppendToOutgoingContext(context.Background(), requestid.Key, "web-failing")
+		var header metadata.MD
+		_, err := client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: root.Id, Iuid: reply.Id, Name: name}, grpc.Header(&header))
+		require.Error(t, err)
+		require.Equal(t, codes.Internal, status.Code(err))
+		require.Equal(t, "web-failing", requestInfo(t, err).RequestId)
+		require.Equal(t, []string{"web-failing"}, header.Get(requestid.Key))
+
+		lines := logLines(t, logs)
+		require.Len(t, lines, 1)
+		require.Equal(t, "web-failing", lines[0]["request_id"])
+	})
+
+	t.Run("GrantAuditRow", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), requestid.Key, "web-granting")
+		_, err := client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: reply.Id, Iuid: root.Id, Name: name})
+		require.NoError(t, err)
+
+		var id string
+		err = conn.QueryRow("SELECT request_id FROM user_permission_grants WHERE uid = ? AND name = ?;", reply.Id, name).Scan(&id)
+		require.NoError(t, err)
+		require.Equal(t, "web-granting", id)
+	})
+
+	t.Run("InvalidReplaced", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), requestid.Key, "has space")
+		var header metadata.MD
+		_, err := client.UserPermissionDefinitions(ctx, &pb.UserPermissionDefinitionsRequest{}, grpc.Header(&header))
+		require.NoError(t, err)
+		require.NotEqual(t, []string{"has space"}, header.Get(requestid.Key))
+	})
+}
+
+func TestGatewayRequestIDHeaders(t *testing.T) {
+	key, ok := gatewayIncomingHeader("X-Request-Id")
+	require.True(t, ok)
+	require.Equal(t, requestid.Key, key)
+
+	key, ok = gatewayOutgoingHeader(requestid.Key)
+	require.True(t, ok)
+	require.Equal(t, "X-Request-Id", key)
+
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{
+		HeaderMD: metadata.Pairs(requestid.Key, "web-1234"),
+	})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/1/settings", nil)
+	gatewayErrorHandler(ctx, runtime.NewServeMux(), &runtime.JSONPb{}, w, r, withRequestInfo(status.Error(codes.Internal, "nope"), "web-1234"))
+
+	require.Equal(t, "web-1234", w.Header().Get("X-Request-Id"))
+	require.Contains(t, w.Body.String(), `"requestId":"web-1234"`)
+}
@@ -2,60 +2,278 @@ package server
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
+	"time"
 
+	"github.com/afteralec/grpc-user/config"
 	"github.com/afteralec/grpc-user/db"
+	"github.com/afteralec/grpc-user/migrations"
 	pb "github.com/afteralec/grpc-user/proto"
 	"github.com/afteralec/grpc-user/services/user"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
-func Run(ctx context.Context, config *viper.Viper) error {
+func Run(ctx context.Context, v *viper.Viper) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
-	lis, err := net.Listen("tcp", ":8009")
+	cfg, err := config.New(v)
 	if err != nil {
 		return err
 	}
 
-	db, err := db.Open("/var/db/user.db")
+	logger := newLogger(cfg.Log, os.Stderr)
+	slog.SetDefault(logger)
+
+	tp, err := newTracerProvider(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	conn, err := db.Open(cfg.DB.Path, db.WithPragmas(cfg.DB.Pragmas()))
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
-	us, err := user.New(db, user.WithConfig(config))
+	rpc := newRPCMetrics()
+	reg, err := newRegistry(conn, rpc)
 	if err != nil {
 		return err
 	}
-	us.SyncRootPermissions(ctx)
 
-	s := grpc.NewServer()
-	pb.RegisterUserServer(s, &server{user: &us})
+	us, err := user.New(conn, user.WithConfig(v), user.WithLogger(logger), user.WithMetrics(reg))
+	if err != nil {
+		return err
+	}
+
+	s := grpc.NewServer(serverOptions(cfg, logger, rpc)...)
+	pb.RegisterUserServer(s, &server{user: &us, settingsHeartbeat: cfg.Settings.HeartbeatInterval})
+
+	hs := health.NewServer()
+	setServingStatus(hs, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, hs)
+
+	if cfg.GRPC.Reflection {
+		reflection.Register(s)
+	}
+
+	var handler *http.Server
+	if cfg.GRPC.Connect {
+		h, err := newProtocolHandler(s)
+		if err != nil {
+			return err
+		}
+		handler = &http.Server{Handler: h}
+	}
+
+	var lc net.ListenConfig
+	if cfg.GRPC.Connect {
+		// net/http's HTTP/2 server never pings clients, so dead connections are found by TCP keepalive instead.
+		lc.KeepAlive = cfg.GRPC.Keepalive.Time
+	}
+
+	// Listen last, so nothing above can return with the listener left open.
+	lis, err := lc.Listen(ctx, "tcp", cfg.ListenAddress)
+	if err != nil {
+		return err
+	}
+	forceStop := func() {
+		if handler != nil {
+			handler.Close()
+		}
+		s.Stop()
+	}
 
 	go func() {
-		log.Printf("gRPC server listening at %v", lis.Addr())
+		if handler != nil {
+			logger.Info("gRPC, gRPC-Web and Connect server listening", "addr", lis.Addr().String())
+			if err := handler.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("error listening and serving", "err", err)
+			}
+			return
+		}
+		logger.Info("gRPC server listening", "addr", lis.Addr().String())
 		if err := s.Serve(lis); err != nil {
-			fmt.Fprintf(os.Stderr, "error listening and serving: %s\n", err)
+			logger.Error("error listening and serving", "err", err)
 		}
 	}()
 
+	var gateway *http.Server
+	if cfg.Gateway.Enabled {
+		gateway, err = serveGateway(ctx, logger, cfg.Gateway.ListenAddress, lis.Addr().String())
+		if err != nil {
+			forceStop()
+			return err
+		}
+	}
+
+	var metrics *http.Server
+	if cfg.Metrics.Enabled {
+		metrics, err = serveMetrics(logger, cfg.Metrics.ListenAddress, reg)
+		if err != nil {
+			forceStop()
+			return err
+		}
+	}
+
+	if err := db.VerifyMigrations(ctx, conn, migrations.FS); err != nil {
+		forceStop()
+		return err
+	}
+	if err := us.SyncRootPermissions(ctx); err != nil {
+		forceStop()
+		return err
+	}
+	setServingStatus(hs, healthpb.HealthCheckResponse_SERVING)
+
 	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		eraseDueAccounts(ctx, logger, &us, cfg.Deletion.SweepInterval)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		expireUserPermissions(ctx, logger, &us, cfg.Permissions.ExpirySweepInterval)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		<-ctx.Done()
 		defer cancel()
-		s.GracefulStop()
+		hs.Shutdown()
+		if gateway != nil {
+			shutdown(logger, gateway, cfg.ShutdownTimeout)
+		}
+		if metrics != nil {
+			shutdown(logger, metrics, cfg.ShutdownTimeout)
+		}
+		if handler != nil {
+			shutdown(logger, handler, cfg.ShutdownTimeout)
+		}
+		stop(logger, s, cfg.ShutdownTimeout)
+		flushTraces(logger, tp, cfg.ShutdownTimeout)
 	}()
 	wg.Wait()
 
 	return nil
 }
+
+func setServingStatus(hs *health.Server, status healthpb.HealthCheckResponse_ServingStatus) {
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(pb.User_ServiceDesc.ServiceName, status)
+}
+
+func serverOptions(cfg config.Config, logger *slog.Logger, rpc *rpcMetrics) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryRequestIDInterceptor(), unaryLoggingInterceptor(logger), rpc.unaryInterceptor()),
+		grpc.ChainStreamInterceptor(streamRequestIDInterceptor(), streamLoggingInterceptor(logger), rpc.streamInterceptor()),
+		grpc.MaxRecvMsgSize(cfg.GRPC.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.GRPC.MaxSendMsgSize),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.GRPC.Keepalive.Time,
+			Timeout: cfg.GRPC.Keepalive.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.GRPC.Keepalive.MinTime,
+			PermitWithoutStream: cfg.GRPC.Keepalive.PermitWithoutStream,
+		}),
+	}
+}
+
+// stop drains in-flight RPCs, falling back to a hard stop once the timeout elapses.
+func stop(logger *slog.Logger, s *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		logger.Warn("graceful stop timed out, forcing stop", "timeout", timeout)
+		s.Stop()
+	}
+}
+
+// serveGateway proxies the REST/JSON gateway through a client connection to the gRPC server at target.
+func serveGateway(ctx context.Context, logger *slog.Logger, addr, target string) (*http.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := grpc.NewClient(
+		"passthrough:///"+target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		lis.Close()
+		return nil, err
+	}
+
+	handler, err := newGateway(ctx, cc)
+	if err != nil {
+		cc.Close()
+		lis.Close()
+		return nil, err
+	}
+
+	gateway := &http.Server{Handler: otelhttp.NewHandler(handler, "gateway")}
+	gateway.RegisterOnShutdown(func() {
+		cc.Close()
+	})
+
+	go func() {
+		logger.Info("REST gateway listening", "addr", lis.Addr().String())
+		if err := gateway.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error listening and serving gateway", "err", err)
+		}
+	}()
+
+	return gateway, nil
+}
+
+func shutdown(logger *slog.Logger, srv *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("http server shutdown", "err", err)
+	}
+}
+
+// flushTraces exports any spans still buffered by tp before the process exits.
+func flushTraces(logger *slog.Logger, tp *sdktrace.TracerProvider, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		logger.Error("trace provider shutdown", "err", err)
+	}
+}
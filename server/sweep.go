@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// sweep calls fn at startup and then every interval, until ctx is done. fn returns how many rows it handled,
+// and a sweep keeps calling it while it handles full batches of batchSize. Errors are logged under name.
+func sweep(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, batchSize int, fn func(context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := fn(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.ErrorContext(ctx, name, "err", err)
+				}
+				break
+			}
+			if n < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
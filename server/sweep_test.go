@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepDrainsFullBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		sweep(ctx, slog.Default(), "test sweep", time.Hour, 10, func(context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 10, nil
+			}
+			cancel()
+			return 0, nil
+		})
+		close(done)
+	}()
+	<-done
+	require.Equal(t, 3, calls)
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+
+	"github.com/afteralec/grpc-user/config"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func newTracerProvider(ctx context.Context, cfg config.Tracing) (*sdktrace.TracerProvider, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("user"))),
+	}
+
+	if cfg.Exporter != "otlp" {
+		// Nothing would read the spans, so don't spend time recording them.
+		opts = append(opts, sdktrace.WithSampler(sdktrace.NeverSample()))
+		return sdktrace.NewTracerProvider(opts...), nil
+	}
+
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts,
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithBatcher(exporter),
+	)
+	return sdktrace.NewTracerProvider(opts...), nil
+}
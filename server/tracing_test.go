@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+)
+
+// newTestTracer installs a tracer provider that records finished spans in memory for the length of the test.
+func newTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestTracing(t *testing.T) {
+	exporter := newTestTracer(t)
+
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+
+	_, err := client.Register(context.Background(), &pb.RegisterRequest{Username: "testtracing", Password: TestPassword})
+	require.NoError(t, err)
+	exporter.Reset()
+
+	_, err = client.Login(context.Background(), &pb.LoginRequest{Username: "testtracing", Password: TestPassword})
+	require.NoError(t, err)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	rpc, ok := spans["user.User/Login"]
+	require.True(t, ok)
+	method, ok := spans["user.Service.Authenticate"]
+	require.True(t, ok)
+	query, ok := spans["query.GetUserByUsername"]
+	require.True(t, ok)
+	argon2, ok := spans["argon2.Verify"]
+	require.True(t, ok)
+
+	require.Equal(t, rpc.SpanContext.SpanID(), method.Parent.SpanID())
+	require.Equal(t, method.SpanContext.SpanID(), query.Parent.SpanID())
+	require.Equal(t, method.SpanContext.SpanID(), argon2.Parent.SpanID())
+	require.Equal(t, rpc.SpanContext.TraceID(), argon2.SpanContext.TraceID())
+}
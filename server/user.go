@@ -2,23 +2,35 @@ package server
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/afteralec/grpc-user/proto"
 	"github.com/afteralec/grpc-user/services/user"
 	"github.com/afteralec/grpc-user/services/user/passphrase"
-	"github.com/afteralec/grpc-user/services/user/username"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// defaultSettingsHeartbeat is used when settingsHeartbeat isn't set.
+const defaultSettingsHeartbeat = 30 * time.Second
+
 type server struct {
 	proto.UnimplementedUserServer
 	user *user.Service
+	// settingsHeartbeat is how often WatchUserSettings sends a heartbeat while nothing changes.
+	settingsHeartbeat time.Duration
 }
 
 func (s *server) Register(ctx context.Context, in *proto.RegisterRequest) (*proto.RegisterReply, error) {
-	if err := username.IsValid(in.Username); err != nil {
+	if err := s.user.IsValidUsername(in.Username); err != nil {
 		// TODO: Add reason metadata
 		return nil, status.Error(codes.InvalidArgument, "the username provided isn't valid")
 	}
@@ -27,8 +39,14 @@ func (s *server) Register(ctx context.Context, in *proto.RegisterRequest) (*prot
 		return nil, status.Error(codes.InvalidArgument, "the passphrase provided isn't valid")
 	}
 
-	uid, err := s.user.Register(in.Username, in.Password)
+	uid, err := s.user.Register(ctx, in.Username, in.Password)
 	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrUsernameTaken), errors.Is(err, user.ErrUsernameConfusable), errors.Is(err, user.ErrUsernameReserved):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		case errors.Is(err, user.ErrUsernameBlocked):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -36,51 +54,337 @@ func (s *server) Register(ctx context.Context, in *proto.RegisterRequest) (*prot
 }
 
 func (s *server) Login(ctx context.Context, in *proto.LoginRequest) (*proto.LoginReply, error) {
-	uid, err := s.user.Authenticate(in.Username, in.Password)
+	uid, err := s.user.Authenticate(ctx, in.Username, in.Password)
 	if err != nil {
+		var inactiveErr *user.InactiveAccountError
+		if errors.As(err, &inactiveErr) {
+			return nil, inactiveAccountError(inactiveErr)
+		}
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.Unauthenticated, "this error message is unimplemented")
 	}
 
+	addLogAttrs(ctx, slog.Int64("uid", uid))
+
 	return &proto.LoginReply{Verified: true, Id: uid}, nil
 }
 
 func (s *server) UserSettings(ctx context.Context, in *proto.UserSettingsRequest) (*proto.UserSettingsReply, error) {
 	settings, err := s.user.UserSettings(ctx, in.Uid)
 	if err != nil {
-		// TODO: Implement Error Details
-		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+		return nil, settingsError(err)
 	}
 
-	return &proto.UserSettingsReply{Id: settings.ID, Uid: settings.UID, Theme: settings.Theme}, nil
+	return &proto.UserSettingsReply{Uid: settings.UID, Theme: settings.Theme}, nil
 }
 
 func (s *server) SetUserSettingsTheme(ctx context.Context, in *proto.SetUserSettingsThemeRequest) (*proto.SetUserSettingsThemeReply, error) {
 	settings, err := s.user.SetUserSettingsTheme(ctx, in.Uid, in.Theme)
 	if err != nil {
-		// TODO: Implement Error Details
-		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+		return nil, settingsError(err)
+	}
+
+	return &proto.SetUserSettingsThemeReply{Uid: settings.UID, Theme: settings.Theme}, nil
+}
+
+func (s *server) GetSettings(ctx context.Context, in *proto.GetSettingsRequest) (*proto.GetSettingsReply, error) {
+	values, err := s.user.Settings(ctx, in.Uid, in.Keys...)
+	if err != nil {
+		return nil, settingsError(err)
+	}
+
+	return &proto.GetSettingsReply{Settings: settingsReply(values)}, nil
+}
+
+func (s *server) UpdateSettings(ctx context.Context, in *proto.UpdateSettingsRequest) (*proto.UpdateSettingsReply, error) {
+	updates := make([]user.SettingUpdate, 0, len(in.Settings))
+	for _, setting := range in.Settings {
+		update := user.SettingUpdate{Key: setting.Key}
+		switch v := setting.Value.(type) {
+		case *proto.Setting_StringValue:
+			update.Value = v.StringValue
+		case *proto.Setting_BoolValue:
+			update.Value = strconv.FormatBool(v.BoolValue)
+		case *proto.Setting_IntValue:
+			update.Value = strconv.FormatInt(v.IntValue, 10)
+		default:
+			update.Reset = true
+		}
+		updates = append(updates, update)
+	}
+
+	values, err := s.user.UpdateSettings(ctx, in.Uid, updates)
+	if err != nil {
+		return nil, settingsError(err)
+	}
+
+	return &proto.UpdateSettingsReply{Settings: settingsReply(values)}, nil
+}
+
+func (s *server) WatchUserSettings(in *proto.WatchUserSettingsRequest, stream proto.User_WatchUserSettingsServer) error {
+	ctx := stream.Context()
+	updates, err := s.user.WatchSettings(ctx, in.Uid)
+	if err != nil {
+		return settingsError(err)
+	}
+
+	interval := s.settingsHeartbeat
+	if interval <= 0 {
+		interval = defaultSettingsHeartbeat
 	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
 
-	return &proto.SetUserSettingsThemeReply{Id: settings.ID, Uid: settings.UID, Theme: settings.Theme}, nil
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case values, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&proto.WatchUserSettingsReply{Settings: settingsReply(values)}); err != nil {
+				return err
+			}
+			heartbeat.Reset(interval)
+		case now := <-heartbeat.C:
+			if err := stream.Send(&proto.WatchUserSettingsReply{HeartbeatAt: timestamppb.New(now)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func settingsError(err error) error {
+	var invalid *user.InvalidSettingError
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		return status.Error(codes.NotFound, "no user exists with this id")
+	case errors.Is(err, user.ErrUnknownSetting):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &invalid):
+		br := &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: invalid.Key, Description: invalid.Error()},
+			},
+		}
+		st, derr := status.New(codes.InvalidArgument, invalid.Error()).WithDetails(br)
+		if derr != nil {
+			return status.Error(codes.InvalidArgument, invalid.Error())
+		}
+		return st.Err()
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+// settingsReply converts setting values to their typed form. Stored values are always valid for their type.
+func settingsReply(values []user.SettingValue) []*proto.Setting {
+	settings := make([]*proto.Setting, 0, len(values))
+	for _, value := range values {
+		setting := &proto.Setting{
+			Key:       value.Setting.Key,
+			Type:      value.Setting.Type,
+			IsDefault: value.IsDefault,
+		}
+		switch value.Setting.Type {
+		case user.SettingTypeBool:
+			b, _ := strconv.ParseBool(value.Value)
+			setting.Value = &proto.Setting_BoolValue{BoolValue: b}
+		case user.SettingTypeInt:
+			n, _ := strconv.ParseInt(value.Value, 10, 64)
+			setting.Value = &proto.Setting_IntValue{IntValue: n}
+		default:
+			setting.Value = &proto.Setting_StringValue{StringValue: value.Value}
+		}
+		settings = append(settings, setting)
+	}
+	return settings
 }
 
 func (s *server) Users(ctx context.Context, in *proto.UsersRequest) (*proto.UsersReply, error) {
-	users, err := s.user.Users(ctx)
+	params := user.UsersParams{
+		PageSize:       int(in.PageSize),
+		PageToken:      in.PageToken,
+		UsernamePrefix: in.UsernamePrefix,
+		Permission:     in.Permission,
+		Status:         in.Status,
+		OrderBy:        in.OrderBy,
+	}
+	if in.CreatedAfter != nil {
+		params.CreatedAfter = in.CreatedAfter.AsTime()
+	}
+
+	page, err := s.user.Users(ctx, params)
 	if err != nil {
+		if errors.Is(err, user.ErrInvalidPageSize) || errors.Is(err, user.ErrInvalidPageToken) || errors.Is(err, user.ErrInvalidOrderBy) || errors.Is(err, user.ErrInvalidStatus) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.Internal, "this error message is unimplemented")
 	}
 
 	replyUsers := []*proto.UsersReplyUser{}
-	for _, user := range users {
+	for _, user := range page.Users {
 		replyUsers = append(replyUsers, &proto.UsersReplyUser{
 			Id:           user.ID,
 			Username:     user.Username,
 			PrimaryEmail: "test@web.site",
 		})
 	}
-	return &proto.UsersReply{Users: replyUsers}, nil
+	return &proto.UsersReply{Users: replyUsers, NextPageToken: page.NextPageToken}, nil
+}
+
+func (s *server) GetUser(ctx context.Context, in *proto.GetUserRequest) (*proto.UserProfile, error) {
+	profile, err := s.user.Profile(ctx, in.Id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "no user exists with this id")
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	return userProfileReply(profile), nil
+}
+
+func (s *server) GetUserByUsername(ctx context.Context, in *proto.GetUserByUsernameRequest) (*proto.UserProfile, error) {
+	profile, err := s.user.ProfileByUsername(ctx, in.Username)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidUsername):
+			// TODO: Add reason metadata
+			return nil, status.Error(codes.InvalidArgument, "the username provided isn't valid")
+		case errors.Is(err, user.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "no user exists with this username")
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	return userProfileReply(profile), nil
+}
+
+func (s *server) GetProfile(ctx context.Context, in *proto.GetProfileRequest) (*proto.Profile, error) {
+	fields, err := s.user.ProfileFields(ctx, in.Uid)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "no user exists with this id")
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	return profileReply(in.Uid, fields), nil
+}
+
+func (s *server) UpdateProfile(ctx context.Context, in *proto.UpdateProfileRequest) (*proto.Profile, error) {
+	if in.Profile == nil {
+		return nil, status.Error(codes.InvalidArgument, "the profile to update is missing")
+	}
+
+	fields, err := s.user.UpdateProfileFields(ctx, in.Profile.Uid, in.Iuid, user.ProfileFields{
+		DisplayName: in.Profile.DisplayName,
+		Bio:         in.Profile.Bio,
+		Pronouns:    in.Profile.Pronouns,
+		AvatarURL:   in.Profile.AvatarUrl,
+	}, in.UpdateMask.GetPaths())
+	if err != nil {
+		var fieldErr *user.InvalidProfileFieldError
+		switch {
+		case errors.As(err, &fieldErr):
+			return nil, profileFieldError(fieldErr)
+		case errors.Is(err, user.ErrInvalidProfileField):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, user.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "no user exists with this id")
+		case errors.Is(err, user.ErrCannotEditProfile):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	addLogAttrs(ctx, slog.Int64("uid", in.Profile.Uid))
+
+	return profileReply(in.Profile.Uid, fields), nil
+}
+
+// profileFieldError names the field that failed validation in a BadRequest, so clients can show it next to the input.
+func profileFieldError(err *user.InvalidProfileFieldError) error {
+	br := &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "profile." + err.Field, Description: err.Error()},
+		},
+	}
+	st, derr := status.New(codes.InvalidArgument, err.Error()).WithDetails(br)
+	if derr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+func profileReply(uid int64, fields *user.ProfileFields) *proto.Profile {
+	reply := &proto.Profile{
+		Uid:         uid,
+		DisplayName: fields.DisplayName,
+		Bio:         fields.Bio,
+		Pronouns:    fields.Pronouns,
+		AvatarUrl:   fields.AvatarURL,
+	}
+	if !fields.UpdatedAt.IsZero() {
+		reply.UpdatedAt = timestamppb.New(fields.UpdatedAt)
+	}
+	return reply
+}
+
+func (s *server) SearchUsers(ctx context.Context, in *proto.SearchUsersRequest) (*proto.SearchUsersReply, error) {
+	page, err := s.user.SearchUsers(ctx, user.SearchUsersParams{
+		Query:     in.Query,
+		PageSize:  int(in.PageSize),
+		PageToken: in.PageToken,
+	})
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidSearchQuery) || errors.Is(err, user.ErrInvalidPageSize) || errors.Is(err, user.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	replyUsers := []*proto.SearchUsersReplyUser{}
+	for _, user := range page.Users {
+		replyUsers = append(replyUsers, &proto.SearchUsersReplyUser{
+			Id:       user.ID,
+			Username: user.Username,
+		})
+	}
+	return &proto.SearchUsersReply{Users: replyUsers, NextPageToken: page.NextPageToken}, nil
+}
+
+func userProfileReply(profile *user.Profile) *proto.UserProfile {
+	reply := &proto.UserProfile{
+		Id:          profile.User.ID,
+		Username:    profile.User.Username,
+		CreatedAt:   timestamp(profile.User.CreatedAt),
+		UpdatedAt:   timestamp(profile.User.UpdatedAt),
+		Theme:       profile.Settings[user.SettingTheme.Key],
+		Permissions: profile.Permissions,
+		DisplayName: profile.Fields.DisplayName,
+	}
+	if profile.PrimaryEmail != nil {
+		reply.PrimaryEmail = profile.PrimaryEmail.Address
+		reply.PrimaryEmailVerified = profile.PrimaryEmail.Verified != 0
+	}
+	return reply
+}
+
+// timestamp converts a unixepoch column to a Timestamp, leaving it unset when the column is NULL.
+func timestamp(t sql.NullInt64) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(time.Unix(t.Int64, 0))
 }
 
 func (s *server) UserPermissionDefinitions(ctx context.Context, in *proto.UserPermissionDefinitionsRequest) (*proto.UserPermissionDefinitionsReply, error) {
@@ -98,18 +402,35 @@ func (s *server) UserPermissionDefinitions(ctx context.Context, in *proto.UserPe
 }
 
 func (s *server) UserPermissions(ctx context.Context, in *proto.UserPermissionsRequest) (*proto.UserPermissionsReply, error) {
-	permissions, err := s.user.UserPermissions(ctx, in.Uid)
+	permissions, err := s.user.EffectivePermissions(ctx, in.Uid)
 	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "no user exists with this id")
+		}
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.Internal, "this error message is unimplemented")
 	}
 
-	names := []string{}
-	for _, permission := range permissions {
-		names = append(names, permission.Name)
+	names := append([]string{}, permissions.List()...)
+	effective := []*proto.EffectivePermission{}
+	for _, name := range permissions.Granted() {
+		permission := &proto.EffectivePermission{Name: name}
+		for _, source := range permissions.Sources(name) {
+			reply := &proto.PermissionSource{
+				Kind:  source.Kind,
+				Id:    source.ID,
+				Name:  source.Name,
+				Scope: source.Scope.String(),
+			}
+			if !source.ExpiresAt.IsZero() {
+				reply.ExpiresAt = timestamppb.New(source.ExpiresAt)
+			}
+			permission.Sources = append(permission.Sources, reply)
+		}
+		effective = append(effective, permission)
 	}
 
-	return &proto.UserPermissionsReply{Uid: in.Uid, Names: names}, nil
+	return &proto.UserPermissionsReply{Uid: in.Uid, Names: names, Permissions: effective}, nil
 }
 
 func (s *server) GrantUserPermission(ctx context.Context, in *proto.GrantUserPermissionRequest) (*proto.GrantUserPermissionReply, error) {
@@ -117,8 +438,19 @@ func (s *server) GrantUserPermission(ctx context.Context, in *proto.GrantUserPer
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.PermissionDenied, "this error message is unimplemented")
 	}
-	id, err := s.user.GrantUserPermission(ctx, in.Uid, in.Iuid, in.Name)
+	var expiresAt time.Time
+	if in.ExpiresAt != nil {
+		expiresAt = in.ExpiresAt.AsTime()
+	}
+	scope, err := user.ParseScope(in.Scope)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	id, err := s.user.GrantScopedUserPermission(ctx, in.Uid, in.Iuid, in.Name, scope, expiresAt)
 	if err != nil {
+		if errors.Is(err, user.ErrInvalidPermissionExpiry) || errors.Is(err, user.ErrInvalidScope) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.Internal, "this error message is unimplemented")
 	}
@@ -131,7 +463,11 @@ func (s *server) RevokeUserPermission(ctx context.Context, in *proto.RevokeUserP
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.PermissionDenied, "this error message is unimplemented")
 	}
-	id, err := s.user.RevokeUserPermission(ctx, in.Uid, in.Iuid, in.Name)
+	scope, err := user.ParseScope(in.Scope)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	id, err := s.user.RevokeScopedUserPermission(ctx, in.Uid, in.Iuid, in.Name, scope)
 	if err != nil {
 		// TODO: Implement Error Details
 		return nil, status.Error(codes.Internal, "this error message is unimplemented")
@@ -139,3 +475,517 @@ func (s *server) RevokeUserPermission(ctx context.Context, in *proto.RevokeUserP
 
 	return &proto.RevokeUserPermissionReply{Id: id}, nil
 }
+
+func (s *server) CheckUserPermission(ctx context.Context, in *proto.CheckUserPermissionRequest) (*proto.CheckUserPermissionReply, error) {
+	allowed, err := s.user.CheckUserPermission(ctx, in.Uid, in.Name, user.Resource{Type: in.ResourceType, ID: in.ResourceId})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "no user exists with this id")
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	return &proto.CheckUserPermissionReply{Allowed: allowed}, nil
+}
+
+func (s *server) Roles(ctx context.Context, in *proto.RolesRequest) (*proto.RolesReply, error) {
+	roles, err := s.user.Roles(ctx)
+	if err != nil {
+		return nil, roleError(err)
+	}
+
+	return &proto.RolesReply{Roles: rolesReply(roles)}, nil
+}
+
+func (s *server) CreateRole(ctx context.Context, in *proto.CreateRoleRequest) (*proto.Role, error) {
+	role, err := s.user.CreateRole(ctx, in.Iuid, in.Name, in.About, in.Permissions)
+	if err != nil {
+		return nil, roleError(err)
+	}
+
+	return roleReply(role), nil
+}
+
+func (s *server) UpdateRole(ctx context.Context, in *proto.UpdateRoleRequest) (*proto.Role, error) {
+	role, err := s.user.UpdateRole(ctx, in.Iuid, in.Id, in.Name, in.About, in.Permissions)
+	if err != nil {
+		return nil, roleError(err)
+	}
+
+	return roleReply(role), nil
+}
+
+func (s *server) DeleteRole(ctx context.Context, in *proto.DeleteRoleRequest) (*proto.DeleteRoleReply, error) {
+	if err := s.user.DeleteRole(ctx, in.Iuid, in.Id); err != nil {
+		return nil, roleError(err)
+	}
+
+	return &proto.DeleteRoleReply{}, nil
+}
+
+func (s *server) UserRoles(ctx context.Context, in *proto.UserRolesRequest) (*proto.UserRolesReply, error) {
+	roles, err := s.user.UserRoles(ctx, in.Uid)
+	if err != nil {
+		return nil, roleError(err)
+	}
+
+	return &proto.UserRolesReply{Roles: rolesReply(roles)}, nil
+}
+
+func (s *server) AssignRole(ctx context.Context, in *proto.AssignRoleRequest) (*proto.AssignRoleReply, error) {
+	if err := s.user.AssignRole(ctx, in.Uid, in.Iuid, in.RoleId); err != nil {
+		return nil, roleError(err)
+	}
+
+	return &proto.AssignRoleReply{}, nil
+}
+
+func (s *server) UnassignRole(ctx context.Context, in *proto.UnassignRoleRequest) (*proto.UnassignRoleReply, error) {
+	if err := s.user.UnassignRole(ctx, in.Uid, in.Iuid, in.RoleId); err != nil {
+		return nil, roleError(err)
+	}
+
+	return &proto.UnassignRoleReply{}, nil
+}
+
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, user.ErrRoleExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, user.ErrRoleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, user.ErrUserNotFound):
+		return status.Error(codes.NotFound, "no user exists with this id")
+	case errors.Is(err, user.ErrCannotManageRoles), errors.Is(err, user.ErrCannotAssignRole), errors.Is(err, user.ErrCannotUnassignRole):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+func rolesReply(roles []user.Role) []*proto.Role {
+	reply := make([]*proto.Role, 0, len(roles))
+	for _, role := range roles {
+		reply = append(reply, roleReply(&role))
+	}
+	return reply
+}
+
+func roleReply(role *user.Role) *proto.Role {
+	return &proto.Role{
+		Id:          role.ID,
+		Name:        role.Name,
+		About:       role.About,
+		Permissions: role.Permissions,
+		Iuid:        role.IUID,
+		CreatedAt:   timestamppb.New(role.CreatedAt),
+		UpdatedAt:   timestamppb.New(role.UpdatedAt),
+	}
+}
+
+func (s *server) Groups(ctx context.Context, in *proto.GroupsRequest) (*proto.GroupsReply, error) {
+	groups, err := s.user.Groups(ctx)
+	if err != nil {
+		return nil, groupError(err)
+	}
+
+	return &proto.GroupsReply{Groups: groupsReply(groups)}, nil
+}
+
+func (s *server) CreateGroup(ctx context.Context, in *proto.CreateGroupRequest) (*proto.Group, error) {
+	group, err := s.user.CreateGroup(ctx, in.Iuid, in.Name, in.About, in.Permissions)
+	if err != nil {
+		return nil, groupError(err)
+	}
+
+	return groupReply(group), nil
+}
+
+func (s *server) UpdateGroup(ctx context.Context, in *proto.UpdateGroupRequest) (*proto.Group, error) {
+	group, err := s.user.UpdateGroup(ctx, in.Iuid, in.Id, in.Name, in.About, in.Permissions)
+	if err != nil {
+		return nil, groupError(err)
+	}
+
+	return groupReply(group), nil
+}
+
+func (s *server) DeleteGroup(ctx context.Context, in *proto.DeleteGroupRequest) (*proto.DeleteGroupReply, error) {
+	if err := s.user.DeleteGroup(ctx, in.Iuid, in.Id); err != nil {
+		return nil, groupError(err)
+	}
+
+	return &proto.DeleteGroupReply{}, nil
+}
+
+func (s *server) GroupMembers(ctx context.Context, in *proto.GroupMembersRequest) (*proto.GroupMembersReply, error) {
+	members, err := s.user.GroupMembers(ctx, in.Id)
+	if err != nil {
+		return nil, groupError(err)
+	}
+
+	reply := make([]*proto.GroupMember, 0, len(members))
+	for _, member := range members {
+		reply = append(reply, &proto.GroupMember{
+			Uid:       member.UID,
+			Iuid:      member.IUID,
+			CreatedAt: timestamppb.New(member.CreatedAt),
+		})
+	}
+	return &proto.GroupMembersReply{Members: reply}, nil
+}
+
+func (s *server) UserGroups(ctx context.Context, in *proto.UserGroupsRequest) (*proto.UserGroupsReply, error) {
+	groups, err := s.user.UserGroups(ctx, in.Uid)
+	if err != nil {
+		return nil, groupError(err)
+	}
+
+	return &proto.UserGroupsReply{Groups: groupsReply(groups)}, nil
+}
+
+func (s *server) AddGroupMember(ctx context.Context, in *proto.AddGroupMemberRequest) (*proto.AddGroupMemberReply, error) {
+	if err := s.user.AddGroupMember(ctx, in.Uid, in.Iuid, in.GroupId); err != nil {
+		return nil, groupError(err)
+	}
+
+	return &proto.AddGroupMemberReply{}, nil
+}
+
+func (s *server) RemoveGroupMember(ctx context.Context, in *proto.RemoveGroupMemberRequest) (*proto.RemoveGroupMemberReply, error) {
+	if err := s.user.RemoveGroupMember(ctx, in.Uid, in.Iuid, in.GroupId); err != nil {
+		return nil, groupError(err)
+	}
+
+	return &proto.RemoveGroupMemberReply{}, nil
+}
+
+func groupError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrInvalidGroup):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, user.ErrGroupExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, user.ErrGroupNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, user.ErrUserNotFound):
+		return status.Error(codes.NotFound, "no user exists with this id")
+	case errors.Is(err, user.ErrCannotManageGroups), errors.Is(err, user.ErrCannotAddGroupMember), errors.Is(err, user.ErrCannotRemoveGroupMember):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+func groupsReply(groups []user.Group) []*proto.Group {
+	reply := make([]*proto.Group, 0, len(groups))
+	for _, group := range groups {
+		reply = append(reply, groupReply(&group))
+	}
+	return reply
+}
+
+func groupReply(group *user.Group) *proto.Group {
+	return &proto.Group{
+		Id:          group.ID,
+		Name:        group.Name,
+		About:       group.About,
+		Permissions: group.Permissions,
+		Iuid:        group.IUID,
+		CreatedAt:   timestamppb.New(group.CreatedAt),
+		UpdatedAt:   timestamppb.New(group.UpdatedAt),
+	}
+}
+
+func (s *server) SuspendUser(ctx context.Context, in *proto.SuspendUserRequest) (*proto.UserStatus, error) {
+	if in.Until == nil {
+		return nil, status.Error(codes.InvalidArgument, "a suspension needs an end time")
+	}
+	accountStatus, err := s.user.SuspendUser(ctx, in.Uid, in.Iuid, in.Reason, in.Until.AsTime())
+	if err != nil {
+		return nil, accountStatusError(err)
+	}
+
+	return userStatusReply(accountStatus), nil
+}
+
+func (s *server) BanUser(ctx context.Context, in *proto.BanUserRequest) (*proto.UserStatus, error) {
+	var until time.Time
+	if in.Until != nil {
+		until = in.Until.AsTime()
+	}
+	accountStatus, err := s.user.BanUser(ctx, in.Uid, in.Iuid, in.Reason, until)
+	if err != nil {
+		return nil, accountStatusError(err)
+	}
+
+	return userStatusReply(accountStatus), nil
+}
+
+func (s *server) ReinstateUser(ctx context.Context, in *proto.ReinstateUserRequest) (*proto.UserStatus, error) {
+	accountStatus, err := s.user.ReinstateUser(ctx, in.Uid, in.Iuid, in.Reason)
+	if err != nil {
+		return nil, accountStatusError(err)
+	}
+
+	return userStatusReply(accountStatus), nil
+}
+
+func accountStatusError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		return status.Error(codes.NotFound, "no user exists with this id")
+	case errors.Is(err, user.ErrCannotModerate), errors.Is(err, user.ErrCannotModerateRoot):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, user.ErrInvalidStatusEnd):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+func userStatusReply(accountStatus *user.AccountStatus) *proto.UserStatus {
+	reply := &proto.UserStatus{
+		Status: accountStatus.Status,
+		Reason: accountStatus.Reason,
+		Iuid:   accountStatus.IUID,
+	}
+	if !accountStatus.Until.IsZero() {
+		reply.Until = timestamppb.New(accountStatus.Until)
+	}
+	return reply
+}
+
+// inactiveAccountError tells someone who signed in with the right passphrase why they can't use their account,
+// with an ErrorInfo reason like ACCOUNT_SUSPENDED and, if it ends, when. The moderator's reason is left for staff.
+func inactiveAccountError(err *user.InactiveAccountError) error {
+	info := &errdetails.ErrorInfo{
+		Reason:   "ACCOUNT_" + strings.ToUpper(err.Status.Status),
+		Domain:   "user",
+		Metadata: map[string]string{"status": err.Status.Status},
+	}
+	if !err.Status.Until.IsZero() {
+		info.Metadata["until"] = err.Status.Until.UTC().Format(time.RFC3339)
+	}
+	st, derr := status.New(codes.PermissionDenied, err.Error()).WithDetails(info)
+	if derr != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return st.Err()
+}
+
+func (s *server) DeleteAccount(ctx context.Context, in *proto.DeleteAccountRequest) (*proto.AccountDeletion, error) {
+	deletion, err := s.user.DeleteAccount(ctx, in.Uid, in.Iuid)
+	if err != nil {
+		return nil, accountDeletionError(err)
+	}
+
+	return &proto.AccountDeletion{
+		Uid:         deletion.UID,
+		Iuid:        deletion.IUID,
+		DeleteAfter: timestamppb.New(deletion.DeleteAfter),
+	}, nil
+}
+
+func (s *server) CancelAccountDeletion(ctx context.Context, in *proto.CancelAccountDeletionRequest) (*proto.CancelAccountDeletionReply, error) {
+	if err := s.user.CancelAccountDeletion(ctx, in.Uid, in.Iuid); err != nil {
+		return nil, accountDeletionError(err)
+	}
+
+	return &proto.CancelAccountDeletionReply{}, nil
+}
+
+func (s *server) ChangeUsername(ctx context.Context, in *proto.ChangeUsernameRequest) (*proto.UsernameChange, error) {
+	change, err := s.user.ChangeUsername(ctx, in.Uid, in.Iuid, in.Username)
+	if err != nil {
+		var cooldownErr *user.UsernameCooldownError
+		switch {
+		case errors.As(err, &cooldownErr):
+			return nil, usernameCooldownError(cooldownErr)
+		case errors.Is(err, user.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "no user exists with this id")
+		case errors.Is(err, user.ErrInvalidUsername), errors.Is(err, user.ErrUsernameUnchanged), errors.Is(err, user.ErrUsernameBlocked):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, user.ErrUsernameTaken), errors.Is(err, user.ErrUsernameConfusable), errors.Is(err, user.ErrUsernameReserved):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		case errors.Is(err, user.ErrCannotChangeUsername), errors.Is(err, user.ErrCannotChangeRootUsername):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	addLogAttrs(ctx, slog.Int64("uid", change.UID))
+
+	reply := &proto.UsernameChange{
+		Uid:           change.UID,
+		Iuid:          change.IUID,
+		OldUsername:   change.OldUsername,
+		NewUsername:   change.NewUsername,
+		ReservedUntil: timestamppb.New(change.ReservedUntil),
+	}
+	if !change.NextChangeAfter.IsZero() {
+		reply.NextChangeAfter = timestamppb.New(change.NextChangeAfter)
+	}
+
+	return reply, nil
+}
+
+func (s *server) UsernameRules(ctx context.Context, in *proto.UsernameRulesRequest) (*proto.UsernameRulesReply, error) {
+	rules, err := s.user.UsernameRules(ctx, in.Iuid)
+	if err != nil {
+		return nil, usernameRuleError(err)
+	}
+
+	reply := &proto.UsernameRulesReply{Rules: make([]*proto.UsernameRule, 0, len(rules))}
+	for _, rule := range rules {
+		reply.Rules = append(reply.Rules, usernameRuleReply(&rule))
+	}
+	return reply, nil
+}
+
+func (s *server) AddUsernameRule(ctx context.Context, in *proto.AddUsernameRuleRequest) (*proto.UsernameRule, error) {
+	rule, err := s.user.AddUsernameRule(ctx, in.Iuid, in.Kind, in.Pattern, in.Reason)
+	if err != nil {
+		return nil, usernameRuleError(err)
+	}
+
+	return usernameRuleReply(rule), nil
+}
+
+func (s *server) RemoveUsernameRule(ctx context.Context, in *proto.RemoveUsernameRuleRequest) (*proto.RemoveUsernameRuleReply, error) {
+	if err := s.user.RemoveUsernameRule(ctx, in.Iuid, in.Id); err != nil {
+		return nil, usernameRuleError(err)
+	}
+
+	return &proto.RemoveUsernameRuleReply{}, nil
+}
+
+func usernameRuleError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrInvalidUsernameRule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, user.ErrUsernameRuleExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, user.ErrUsernameRuleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, user.ErrCannotManageUsernameRules):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+func usernameRuleReply(rule *user.UsernameRule) *proto.UsernameRule {
+	return &proto.UsernameRule{
+		Id:        rule.ID,
+		Kind:      rule.Kind,
+		Pattern:   rule.Pattern,
+		Reason:    rule.Reason,
+		Iuid:      rule.IUID,
+		CreatedAt: timestamppb.New(rule.CreatedAt),
+	}
+}
+
+// usernameCooldownError tells users who changed their username too recently when they can change it again.
+func usernameCooldownError(err *user.UsernameCooldownError) error {
+	info := &errdetails.ErrorInfo{
+		Reason:   "USERNAME_CHANGE_COOLDOWN",
+		Domain:   "user",
+		Metadata: map[string]string{"next_change_after": err.NextChangeAfter.UTC().Format(time.RFC3339)},
+	}
+	st, derr := status.New(codes.FailedPrecondition, err.Error()).WithDetails(info)
+	if derr != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return st.Err()
+}
+
+func accountDeletionError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		return status.Error(codes.NotFound, "no user exists with this id")
+	case errors.Is(err, user.ErrAccountDeletionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, user.ErrCannotDeleteAccount), errors.Is(err, user.ErrCannotDeleteRoot):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+func (s *server) Themes(ctx context.Context, in *proto.ThemesRequest) (*proto.ThemesReply, error) {
+	themes, err := s.user.Themes(ctx)
+	if err != nil {
+		// TODO: Implement Error Details
+		return nil, status.Error(codes.Internal, "this error message is unimplemented")
+	}
+
+	reply := &proto.ThemesReply{Themes: make([]*proto.Theme, 0, len(themes))}
+	for _, theme := range themes {
+		reply.Themes = append(reply.Themes, themeReply(&theme))
+	}
+	return reply, nil
+}
+
+func (s *server) CreateTheme(ctx context.Context, in *proto.CreateThemeRequest) (*proto.Theme, error) {
+	theme, err := s.user.CreateTheme(ctx, in.Iuid, in.Name, in.Palette)
+	if err != nil {
+		return nil, themeError(err)
+	}
+
+	return themeReply(theme), nil
+}
+
+func (s *server) UpdateTheme(ctx context.Context, in *proto.UpdateThemeRequest) (*proto.Theme, error) {
+	theme, err := s.user.UpdateTheme(ctx, in.Iuid, in.Name, in.Palette)
+	if err != nil {
+		return nil, themeError(err)
+	}
+
+	return themeReply(theme), nil
+}
+
+func (s *server) DeleteTheme(ctx context.Context, in *proto.DeleteThemeRequest) (*proto.DeleteThemeReply, error) {
+	if err := s.user.DeleteTheme(ctx, in.Iuid, in.Name); err != nil {
+		return nil, themeError(err)
+	}
+
+	return &proto.DeleteThemeReply{}, nil
+}
+
+func themeError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrInvalidThemeName), errors.Is(err, user.ErrInvalidPalette):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, user.ErrThemeExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, user.ErrThemeNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, user.ErrBuiltInTheme):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, user.ErrCannotManageThemes):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	// TODO: Implement Error Details
+	return status.Error(codes.Internal, "this error message is unimplemented")
+}
+
+func themeReply(theme *user.Theme) *proto.Theme {
+	reply := &proto.Theme{
+		Name:    theme.Name,
+		Palette: theme.Palette,
+		BuiltIn: theme.BuiltIn,
+		Iuid:    theme.IUID,
+	}
+	if !theme.CreatedAt.IsZero() {
+		reply.CreatedAt = timestamppb.New(theme.CreatedAt)
+	}
+	if !theme.UpdatedAt.IsZero() {
+		reply.UpdatedAt = timestamppb.New(theme.UpdatedAt)
+	}
+	return reply
+}
@@ -0,0 +1,747 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/afteralec/grpc-user/proto"
+	"github.com/afteralec/grpc-user/services/user"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRegister(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	_, err := client.Register(ctx, &pb.RegisterRequest{Username: "not a username", Password: "short"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, "the username provided isn't valid", status.Convert(err).Message())
+
+	_, err = client.Register(ctx, &pb.RegisterRequest{Username: "testregister", Password: "short"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, "the passphrase provided isn't valid", status.Convert(err).Message())
+
+	_, err = client.Register(ctx, &pb.RegisterRequest{Username: "testregister", Password: TestPassword})
+	require.NoError(t, err)
+}
+
+func TestGetUser(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testprofile", Password: TestPassword})
+	require.NoError(t, err)
+
+	profile, err := client.GetUser(ctx, &pb.GetUserRequest{Id: reply.Id})
+	require.NoError(t, err)
+	require.Equal(t, reply.Id, profile.Id)
+	require.Equal(t, "testprofile", profile.Username)
+	require.Equal(t, user.ThemeDefault, profile.Theme)
+	require.NotNil(t, profile.CreatedAt)
+	require.NotNil(t, profile.UpdatedAt)
+	require.Empty(t, profile.PrimaryEmail)
+	require.Empty(t, profile.Permissions)
+
+	byUsername, err := client.GetUserByUsername(ctx, &pb.GetUserByUsernameRequest{Username: "testprofile"})
+	require.NoError(t, err)
+	require.Equal(t, reply.Id, byUsername.Id)
+
+	_, err = client.GetUser(ctx, &pb.GetUserRequest{Id: reply.Id + 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetUserByUsername(ctx, &pb.GetUserByUsernameRequest{Username: "nobodyhere"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetUserByUsername(ctx, &pb.GetUserByUsernameRequest{Username: "not a username"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSearchUsers(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	for _, username := range []string{"testsearcha", "testsearchb", "testsearchc"} {
+		_, err := client.Register(ctx, &pb.RegisterRequest{Username: username, Password: TestPassword})
+		require.NoError(t, err)
+	}
+
+	reply, err := client.SearchUsers(ctx, &pb.SearchUsersRequest{Query: "testsearch", PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, reply.Users, 2)
+	require.NotEmpty(t, reply.NextPageToken)
+
+	reply, err = client.SearchUsers(ctx, &pb.SearchUsersRequest{Query: "testsearch", PageSize: 2, PageToken: reply.NextPageToken})
+	require.NoError(t, err)
+	require.Len(t, reply.Users, 1)
+	require.Empty(t, reply.NextPageToken)
+
+	_, err = client.SearchUsers(ctx, &pb.SearchUsersRequest{Query: " "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAccountStatus(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "teststatus", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.BanUser(ctx, &pb.BanUserRequest{Uid: reply.Id, Iuid: root.Id, Reason: "spam"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	for _, permission := range []user.Permission{user.PermissionSuspendUser, user.PermissionBanUser, user.PermissionReinstateUser} {
+		_, err := client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: root.Id, Iuid: root.Id, Name: permission.Name})
+		require.NoError(t, err)
+	}
+
+	_, err = client.SuspendUser(ctx, &pb.SuspendUserRequest{Uid: reply.Id, Iuid: root.Id, Reason: "spam"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	suspended, err := client.SuspendUser(ctx, &pb.SuspendUserRequest{Uid: reply.Id, Iuid: root.Id, Reason: "spam", Until: timestamppb.New(until)})
+	require.NoError(t, err)
+	require.Equal(t, user.StatusSuspended, suspended.Status)
+	require.True(t, until.Equal(suspended.Until.AsTime()))
+
+	_, err = client.Login(ctx, &pb.LoginRequest{Username: "teststatus", Password: TestPassword})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	var info *errdetails.ErrorInfo
+	for _, detail := range status.Convert(err).Details() {
+		if i, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	require.NotNil(t, info)
+	require.Equal(t, "ACCOUNT_SUSPENDED", info.Reason)
+	require.Equal(t, until.UTC().Format(time.RFC3339), info.Metadata["until"])
+
+	banned, err := client.BanUser(ctx, &pb.BanUserRequest{Uid: reply.Id, Iuid: root.Id, Reason: "more spam"})
+	require.NoError(t, err)
+	require.Equal(t, user.StatusBanned, banned.Status)
+	require.Nil(t, banned.Until)
+
+	users, err := client.Users(ctx, &pb.UsersRequest{Status: user.StatusBanned})
+	require.NoError(t, err)
+	require.Len(t, users.Users, 1)
+	require.Equal(t, reply.Id, users.Users[0].Id)
+
+	reinstated, err := client.ReinstateUser(ctx, &pb.ReinstateUserRequest{Uid: reply.Id, Iuid: root.Id, Reason: "appealed"})
+	require.NoError(t, err)
+	require.Equal(t, user.StatusActive, reinstated.Status)
+
+	_, err = client.Login(ctx, &pb.LoginRequest{Username: "teststatus", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.BanUser(ctx, &pb.BanUserRequest{Uid: reply.Id + 100, Iuid: root.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDeleteAccount(t *testing.T) {
+	conn := newTestDB(t)
+	us := newTestService(t, conn)
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: us})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testdeletion", Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.DeleteAccount(ctx, &pb.DeleteAccountRequest{Uid: reply.Id, Iuid: other.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.DeleteAccount(ctx, &pb.DeleteAccountRequest{Uid: reply.Id, Iuid: reply.Id})
+	require.NoError(t, err)
+	_, err = client.CancelAccountDeletion(ctx, &pb.CancelAccountDeletionRequest{Uid: reply.Id, Iuid: reply.Id})
+	require.NoError(t, err)
+	_, err = client.CancelAccountDeletion(ctx, &pb.CancelAccountDeletionRequest{Uid: reply.Id, Iuid: reply.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	deletion, err := client.DeleteAccount(ctx, &pb.DeleteAccountRequest{Uid: reply.Id, Iuid: reply.Id})
+	require.NoError(t, err)
+	require.NotNil(t, deletion.DeleteAfter)
+	_, err = conn.Exec("UPDATE user_deletions SET delete_after = ? WHERE uid = ?;", time.Now().Add(-time.Minute).Unix(), reply.Id)
+	require.NoError(t, err)
+
+	sweepCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		eraseDueAccounts(sweepCtx, slog.Default(), us, 10*time.Millisecond)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		_, err := client.GetUser(ctx, &pb.GetUserRequest{Id: reply.Id})
+		return status.Code(err) == codes.NotFound
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	_, err = client.GetUser(ctx, &pb.GetUserRequest{Id: other.Id})
+	require.NoError(t, err)
+}
+
+func TestGrantUserPermissionExpiry(t *testing.T) {
+	conn := newTestDB(t)
+	us := newTestService(t, conn)
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: us})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	reviewer, err := client.Register(ctx, &pb.RegisterRequest{Username: "testreviewer", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{
+		Uid:       reviewer.Id,
+		Iuid:      root.Id,
+		Name:      "review-character-applications",
+		ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour)),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	expiresAt := time.Now().Add(2 * time.Second).Truncate(time.Second)
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{
+		Uid:       reviewer.Id,
+		Iuid:      root.Id,
+		Name:      "review-character-applications",
+		ExpiresAt: timestamppb.New(expiresAt),
+	})
+	require.NoError(t, err)
+
+	permissions, err := client.UserPermissions(ctx, &pb.UserPermissionsRequest{Uid: reviewer.Id})
+	require.NoError(t, err)
+	require.Equal(t, []string{"review-character-applications"}, permissions.Names)
+	require.Equal(t, expiresAt.Unix(), permissions.Permissions[0].Sources[0].ExpiresAt.AsTime().Unix())
+
+	sweepCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		expireUserPermissions(sweepCtx, slog.Default(), us, 10*time.Millisecond)
+		close(done)
+	}()
+	// Reads leave out expired grants straight away; the sweeper removes the row and logs the revocation.
+	require.Eventually(t, func() bool {
+		var revocations int
+		err := conn.QueryRow("SELECT COUNT(*) FROM user_permission_revocations WHERE uid = ? AND iuid = 0;", reviewer.Id).Scan(&revocations)
+		return err == nil && revocations == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	var remaining int
+	require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM user_permissions WHERE uid = ?;", reviewer.Id).Scan(&remaining))
+	require.Zero(t, remaining)
+}
+
+func TestScopedPermissions(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	builder, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbuilder", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: builder.Id, Iuid: root.Id, Name: "create-changelog", Scope: "room"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: builder.Id, Iuid: root.Id, Name: "create-changelog", Scope: "room:4*"})
+	require.NoError(t, err)
+
+	check := func(resourceType, resourceID string) bool {
+		reply, err := client.CheckUserPermission(ctx, &pb.CheckUserPermissionRequest{
+			Uid:          builder.Id,
+			Name:         "create-changelog",
+			ResourceType: resourceType,
+			ResourceId:   resourceID,
+		})
+		require.NoError(t, err)
+		return reply.Allowed
+	}
+	require.True(t, check("room", "42"))
+	require.False(t, check("room", "7"))
+	require.False(t, check("", ""))
+	_, err = client.CheckUserPermission(ctx, &pb.CheckUserPermissionRequest{Uid: builder.Id + 100, Name: "create-changelog"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	permissions, err := client.UserPermissions(ctx, &pb.UserPermissionsRequest{Uid: builder.Id})
+	require.NoError(t, err)
+	require.Empty(t, permissions.Names)
+	require.Len(t, permissions.Permissions, 1)
+	require.Equal(t, "room:4*", permissions.Permissions[0].Sources[0].Scope)
+
+	_, err = client.RevokeUserPermission(ctx, &pb.RevokeUserPermissionRequest{Uid: builder.Id, Iuid: root.Id, Name: "create-changelog", Scope: "room:4*"})
+	require.NoError(t, err)
+	require.False(t, check("room", "42"))
+}
+
+func TestChangeUsername(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testrename", Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.ChangeUsername(ctx, &pb.ChangeUsernameRequest{Uid: reply.Id, Iuid: other.Id, Username: "testrenamed"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ChangeUsername(ctx, &pb.ChangeUsernameRequest{Uid: reply.Id, Iuid: reply.Id, Username: "x"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ChangeUsername(ctx, &pb.ChangeUsernameRequest{Uid: reply.Id, Iuid: reply.Id, Username: "testbystander"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.Register(ctx, &pb.RegisterRequest{Username: "testrenarne", Password: TestPassword})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	change, err := client.ChangeUsername(ctx, &pb.ChangeUsernameRequest{Uid: reply.Id, Iuid: reply.Id, Username: "testrenamed"})
+	require.NoError(t, err)
+	require.Equal(t, "testrename", change.OldUsername)
+	require.Equal(t, "testrenamed", change.NewUsername)
+
+	profile, err := client.GetUser(ctx, &pb.GetUserRequest{Id: reply.Id})
+	require.NoError(t, err)
+	require.Equal(t, "testrenamed", profile.Username)
+}
+
+func TestUsernameRules(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.Register(ctx, &pb.RegisterRequest{Username: "admin", Password: TestPassword})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.AddUsernameRule(ctx, &pb.AddUsernameRuleRequest{Iuid: other.Id, Kind: "blocked", Pattern: "nasty"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: root.Id, Iuid: root.Id, Name: "manage-username-rules"})
+	require.NoError(t, err)
+	_, err = client.AddUsernameRule(ctx, &pb.AddUsernameRuleRequest{Iuid: root.Id, Kind: "forbidden", Pattern: "nasty"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	rule, err := client.AddUsernameRule(ctx, &pb.AddUsernameRuleRequest{Iuid: root.Id, Kind: "blocked", Pattern: "nasty"})
+	require.NoError(t, err)
+
+	_, err = client.Register(ctx, &pb.RegisterRequest{Username: "testnasty", Password: TestPassword})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ChangeUsername(ctx, &pb.ChangeUsernameRequest{Uid: other.Id, Iuid: other.Id, Username: "testnasty"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	rules, err := client.UsernameRules(ctx, &pb.UsernameRulesRequest{Iuid: root.Id})
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, 1)
+
+	_, err = client.RemoveUsernameRule(ctx, &pb.RemoveUsernameRuleRequest{Iuid: root.Id, Id: rule.Id})
+	require.NoError(t, err)
+	_, err = client.RemoveUsernameRule(ctx, &pb.RemoveUsernameRuleRequest{Iuid: root.Id, Id: rule.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestProfile(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	conn := newTestConn(t, s)
+	client := pb.NewUserClient(conn)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testprofile", Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	_, err = client.UpdateProfile(ctx, &pb.UpdateProfileRequest{
+		Profile: &pb.Profile{Uid: reply.Id, DisplayName: "Tested"},
+		Iuid:    other.Id,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.UpdateProfile(ctx, &pb.UpdateProfileRequest{
+		Profile: &pb.Profile{Uid: reply.Id, AvatarUrl: "not a url"},
+		Iuid:    reply.Id,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	var violation *errdetails.BadRequest
+	for _, detail := range status.Convert(err).Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			violation = br
+		}
+	}
+	require.NotNil(t, violation)
+	require.Equal(t, "profile.avatar_url", violation.FieldViolations[0].Field)
+
+	profile, err := client.UpdateProfile(ctx, &pb.UpdateProfileRequest{
+		Profile:    &pb.Profile{Uid: reply.Id, DisplayName: "Tested", Bio: "Ignored"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name"}},
+		Iuid:       reply.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Tested", profile.DisplayName)
+	require.Empty(t, profile.Bio)
+
+	t.Run("Gateway", func(t *testing.T) {
+		gateway, err := newGateway(context.Background(), conn)
+		require.NoError(t, err)
+
+		path := "/v1/users/" + strconv.FormatInt(reply.Id, 10) + "/profile"
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, path+"?iuid="+strconv.FormatInt(reply.Id, 10), strings.NewReader(`{"pronouns": "they/them"}`)))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		require.Equal(t, "Tested", got["display_name"])
+		require.Equal(t, "they/them", got["pronouns"])
+	})
+
+	userProfile, err := client.GetUser(ctx, &pb.GetUserRequest{Id: reply.Id})
+	require.NoError(t, err)
+	require.Equal(t, "Tested", userProfile.DisplayName)
+}
+
+func TestSettings(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	conn := newTestConn(t, s)
+	client := pb.NewUserClient(conn)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testsettings", Password: TestPassword})
+	require.NoError(t, err)
+
+	settings, err := client.GetSettings(ctx, &pb.GetSettingsRequest{Uid: reply.Id})
+	require.NoError(t, err)
+	require.Equal(t, len(user.AllSettings), len(settings.Settings))
+	require.Equal(t, user.SettingTheme.Key, settings.Settings[0].Key)
+	require.Equal(t, user.ThemeDefault, settings.Settings[0].GetStringValue())
+	require.True(t, settings.Settings[0].IsDefault)
+
+	_, err = client.GetSettings(ctx, &pb.GetSettingsRequest{Uid: reply.Id + 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetSettings(ctx, &pb.GetSettingsRequest{Uid: reply.Id, Keys: []string{"nonexistent"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.UpdateSettings(ctx, &pb.UpdateSettingsRequest{
+		Uid:      reply.Id,
+		Settings: []*pb.Setting{{Key: user.SettingTheme.Key, Value: &pb.Setting_BoolValue{BoolValue: true}}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	updated, err := client.UpdateSettings(ctx, &pb.UpdateSettingsRequest{
+		Uid:      reply.Id,
+		Settings: []*pb.Setting{{Key: user.SettingTheme.Key, Value: &pb.Setting_StringValue{StringValue: user.ThemeDark}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.ThemeDark, updated.Settings[0].GetStringValue())
+	require.False(t, updated.Settings[0].IsDefault)
+
+	legacy, err := client.UserSettings(ctx, &pb.UserSettingsRequest{Uid: reply.Id})
+	require.NoError(t, err)
+	require.Equal(t, user.ThemeDark, legacy.Theme)
+
+	t.Run("Gateway", func(t *testing.T) {
+		gateway, err := newGateway(context.Background(), conn)
+		require.NoError(t, err)
+
+		path := "/v1/users/" + strconv.FormatInt(reply.Id, 10) + "/settings"
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path+":batchUpdate", strings.NewReader(`{"settings": [{"key": "theme"}]}`)))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+":batchGet?keys=theme", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var got struct {
+			Settings []map[string]any `json:"settings"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		require.Equal(t, user.ThemeDefault, got.Settings[0]["string_value"])
+		require.Equal(t, true, got.Settings[0]["is_default"])
+	})
+}
+
+func TestThemes(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	conn := newTestConn(t, s)
+	client := pb.NewUserClient(conn)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	palette := map[string]string{"background": "#282a36", "text": "#f8f8f2"}
+	_, err = client.CreateTheme(ctx, &pb.CreateThemeRequest{Iuid: other.Id, Name: "dracula", Palette: palette})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: root.Id, Iuid: root.Id, Name: "manage-themes"})
+	require.NoError(t, err)
+	_, err = client.CreateTheme(ctx, &pb.CreateThemeRequest{Iuid: root.Id, Name: "dracula", Palette: map[string]string{"background": "purple"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.DeleteTheme(ctx, &pb.DeleteThemeRequest{Iuid: root.Id, Name: user.ThemeLight})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	theme, err := client.CreateTheme(ctx, &pb.CreateThemeRequest{Iuid: root.Id, Name: "dracula", Palette: palette})
+	require.NoError(t, err)
+	require.Equal(t, palette, theme.Palette)
+	_, err = client.CreateTheme(ctx, &pb.CreateThemeRequest{Iuid: root.Id, Name: "dracula", Palette: palette})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.SetUserSettingsTheme(ctx, &pb.SetUserSettingsThemeRequest{Uid: other.Id, Theme: "monokai"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	settings, err := client.SetUserSettingsTheme(ctx, &pb.SetUserSettingsThemeRequest{Uid: other.Id, Theme: "dracula"})
+	require.NoError(t, err)
+	require.Equal(t, "dracula", settings.Theme)
+
+	t.Run("Gateway", func(t *testing.T) {
+		gateway, err := newGateway(context.Background(), conn)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/themes", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var got struct {
+			Themes []struct {
+				Name    string            `json:"name"`
+				Palette map[string]string `json:"palette"`
+				BuiltIn bool              `json:"built_in"`
+			} `json:"themes"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		require.Len(t, got.Themes, 4)
+		require.Equal(t, user.ThemeSystem, got.Themes[2].Name)
+		require.True(t, got.Themes[2].BuiltIn)
+		require.Equal(t, "dracula", got.Themes[3].Name)
+		require.Equal(t, palette, got.Themes[3].Palette)
+	})
+
+	_, err = client.DeleteTheme(ctx, &pb.DeleteThemeRequest{Iuid: root.Id, Name: "dracula"})
+	require.NoError(t, err)
+	_, err = client.DeleteTheme(ctx, &pb.DeleteThemeRequest{Iuid: root.Id, Name: "dracula"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	reply, err := client.UserSettings(ctx, &pb.UserSettingsRequest{Uid: other.Id})
+	require.NoError(t, err)
+	require.Equal(t, user.ThemeDefault, reply.Theme)
+}
+
+func TestWatchUserSettings(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t)), settingsHeartbeat: 50 * time.Millisecond})
+	conn := newTestConn(t, s)
+	client := pb.NewUserClient(conn)
+	ctx := context.Background()
+
+	reply, err := client.Register(ctx, &pb.RegisterRequest{Username: "testwatch", Password: TestPassword})
+	require.NoError(t, err)
+
+	stream, err := client.WatchUserSettings(ctx, &pb.WatchUserSettingsRequest{Uid: reply.Id + 100})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err = client.WatchUserSettings(watchCtx, &pb.WatchUserSettingsRequest{Uid: reply.Id})
+	require.NoError(t, err)
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, user.ThemeDefault, update.Settings[0].GetStringValue())
+	require.Nil(t, update.HeartbeatAt)
+
+	update, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, update.Settings)
+	require.NotNil(t, update.HeartbeatAt)
+
+	_, err = client.SetUserSettingsTheme(ctx, &pb.SetUserSettingsThemeRequest{Uid: reply.Id, Theme: user.ThemeLight})
+	require.NoError(t, err)
+	for {
+		update, err = stream.Recv()
+		require.NoError(t, err)
+		if update.HeartbeatAt == nil {
+			break
+		}
+	}
+	require.Equal(t, user.ThemeLight, update.Settings[0].GetStringValue())
+
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	t.Run("Gateway", func(t *testing.T) {
+		gateway, err := newGateway(context.Background(), conn)
+		require.NoError(t, err)
+		ts := httptest.NewServer(gateway)
+		t.Cleanup(ts.Close)
+
+		reqCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, ts.URL+"/v1/users/"+strconv.FormatInt(reply.Id, 10)+"/settings:watch", nil)
+		require.NoError(t, err)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var got struct {
+			Result struct {
+				Settings []map[string]any `json:"settings"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		require.Equal(t, user.ThemeLight, got.Result.Settings[0]["string_value"])
+	})
+}
+
+func TestRoles(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	permissions := []string{"create-changelog", "release-changelog", "revoke-changelog"}
+	_, err = client.CreateRole(ctx, &pb.CreateRoleRequest{Iuid: root.Id, Name: "Changelog Editor", Permissions: permissions})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: root.Id, Iuid: root.Id, Name: "manage-roles"})
+	require.NoError(t, err)
+	_, err = client.CreateRole(ctx, &pb.CreateRoleRequest{Iuid: root.Id, Name: "Root", Permissions: []string{"grant-all"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	role, err := client.CreateRole(ctx, &pb.CreateRoleRequest{Iuid: root.Id, Name: "Changelog Editor", Permissions: permissions})
+	require.NoError(t, err)
+	require.Equal(t, permissions, role.Permissions)
+
+	_, err = client.AssignRole(ctx, &pb.AssignRoleRequest{Uid: other.Id, Iuid: other.Id, RoleId: role.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.AssignRole(ctx, &pb.AssignRoleRequest{Uid: other.Id, Iuid: root.Id, RoleId: role.Id + 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.AssignRole(ctx, &pb.AssignRoleRequest{Uid: other.Id, Iuid: root.Id, RoleId: role.Id})
+	require.NoError(t, err)
+
+	userRoles, err := client.UserRoles(ctx, &pb.UserRolesRequest{Uid: other.Id})
+	require.NoError(t, err)
+	require.Len(t, userRoles.Roles, 1)
+	require.Equal(t, "Changelog Editor", userRoles.Roles[0].Name)
+
+	roles, err := client.Roles(ctx, &pb.RolesRequest{})
+	require.NoError(t, err)
+	require.Len(t, roles.Roles, 1)
+
+	_, err = client.UnassignRole(ctx, &pb.UnassignRoleRequest{Uid: other.Id, Iuid: root.Id, RoleId: role.Id})
+	require.NoError(t, err)
+	_, err = client.DeleteRole(ctx, &pb.DeleteRoleRequest{Iuid: root.Id, Id: role.Id})
+	require.NoError(t, err)
+	_, err = client.DeleteRole(ctx, &pb.DeleteRoleRequest{Iuid: root.Id, Id: role.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGroups(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterUserServer(s, &server{user: newTestService(t, newTestDB(t))})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	root, err := client.Register(ctx, &pb.RegisterRequest{Username: TestRootUsername, Password: TestPassword})
+	require.NoError(t, err)
+	other, err := client.Register(ctx, &pb.RegisterRequest{Username: "testbystander", Password: TestPassword})
+	require.NoError(t, err)
+
+	permissions := []string{"create-changelog", "release-changelog"}
+	_, err = client.CreateGroup(ctx, &pb.CreateGroupRequest{Iuid: root.Id, Name: "Room Builders", Permissions: permissions})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: root.Id, Iuid: root.Id, Name: "manage-groups"})
+	require.NoError(t, err)
+	_, err = client.CreateGroup(ctx, &pb.CreateGroupRequest{Iuid: root.Id, Name: "Root", Permissions: []string{"revoke-all"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	group, err := client.CreateGroup(ctx, &pb.CreateGroupRequest{Iuid: root.Id, Name: "Room Builders", Permissions: permissions})
+	require.NoError(t, err)
+	require.Equal(t, permissions, group.Permissions)
+	_, err = client.CreateGroup(ctx, &pb.CreateGroupRequest{Iuid: root.Id, Name: "Room Builders", Permissions: permissions})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.AddGroupMember(ctx, &pb.AddGroupMemberRequest{Uid: other.Id, Iuid: other.Id, GroupId: group.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.AddGroupMember(ctx, &pb.AddGroupMemberRequest{Uid: other.Id, Iuid: root.Id, GroupId: group.Id + 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.AddGroupMember(ctx, &pb.AddGroupMemberRequest{Uid: other.Id, Iuid: root.Id, GroupId: group.Id})
+	require.NoError(t, err)
+
+	members, err := client.GroupMembers(ctx, &pb.GroupMembersRequest{Id: group.Id})
+	require.NoError(t, err)
+	require.Len(t, members.Members, 1)
+	require.Equal(t, other.Id, members.Members[0].Uid)
+
+	userGroups, err := client.UserGroups(ctx, &pb.UserGroupsRequest{Uid: other.Id})
+	require.NoError(t, err)
+	require.Len(t, userGroups.Groups, 1)
+	require.Equal(t, "Room Builders", userGroups.Groups[0].Name)
+
+	_, err = client.GrantUserPermission(ctx, &pb.GrantUserPermissionRequest{Uid: other.Id, Iuid: root.Id, Name: "create-changelog"})
+	require.NoError(t, err)
+	effective, err := client.UserPermissions(ctx, &pb.UserPermissionsRequest{Uid: other.Id})
+	require.NoError(t, err)
+	require.Equal(t, permissions, effective.Names)
+	require.Len(t, effective.Permissions, 2)
+	require.Equal(t, "create-changelog", effective.Permissions[0].Name)
+	require.Len(t, effective.Permissions[0].Sources, 2)
+	require.Equal(t, "direct", effective.Permissions[0].Sources[0].Kind)
+	require.Equal(t, "group", effective.Permissions[0].Sources[1].Kind)
+	require.Equal(t, group.Id, effective.Permissions[0].Sources[1].Id)
+	require.Equal(t, "Room Builders", effective.Permissions[0].Sources[1].Name)
+	_, err = client.UserPermissions(ctx, &pb.UserPermissionsRequest{Uid: other.Id + 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	groups, err := client.Groups(ctx, &pb.GroupsRequest{})
+	require.NoError(t, err)
+	require.Len(t, groups.Groups, 1)
+
+	_, err = client.RemoveGroupMember(ctx, &pb.RemoveGroupMemberRequest{Uid: other.Id, Iuid: root.Id, GroupId: group.Id})
+	require.NoError(t, err)
+	_, err = client.DeleteGroup(ctx, &pb.DeleteGroupRequest{Iuid: root.Id, Id: group.Id})
+	require.NoError(t, err)
+	_, err = client.DeleteGroup(ctx, &pb.DeleteGroupRequest{Iuid: root.Id, Id: group.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,405 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+// permissionBundle is a named set of permissions that users hold for as long as they belong to it.
+// Roles and groups are both bundles, and only differ in how users join and leave them.
+type permissionBundle struct {
+	ID          int64
+	Name        string
+	About       string
+	Permissions []string
+	IUID        int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// bundleKind is everything that differs between roles and groups, apart from membership.
+type bundleKind struct {
+	// manage is needed to create, update or delete bundles of this kind.
+	manage Permission
+	// leave is the change logged for each member when a bundle is deleted.
+	leave string
+
+	errInvalid      error
+	errExists       error
+	errNotFound     error
+	errCannotManage error
+	// errCannotJoin and errCannotLeave are returned when the issuer can't grant or revoke
+	// everything in the bundle.
+	errCannotJoin  error
+	errCannotLeave error
+
+	queries func(qtx *query.Queries) bundleQueries
+}
+
+// bundleQueries are the queries for one kind of bundle, bound to a transaction.
+type bundleQueries struct {
+	get               func(ctx context.Context, id int64) (permissionBundle, error)
+	getByName         func(ctx context.Context, name string) (permissionBundle, error)
+	list              func(ctx context.Context) ([]permissionBundle, error)
+	listByUser        func(ctx context.Context, uid int64) ([]permissionBundle, error)
+	create            func(ctx context.Context, name, about string, iuid int64) (int64, error)
+	update            func(ctx context.Context, id int64, name, about string, iuid int64) error
+	delete            func(ctx context.Context, id int64) error
+	listPermissions   func(ctx context.Context, id int64) ([]string, error)
+	createPermission  func(ctx context.Context, id int64, name string) error
+	deletePermissions func(ctx context.Context, id int64) error
+	listMembers       func(ctx context.Context, id int64) ([]int64, error)
+	createChange      func(ctx context.Context, action string, bundle *permissionBundle, uid, iuid int64) error
+}
+
+func newPermissionBundle(id int64, name, about string, iuid int64, createdAt, updatedAt sql.NullInt64) permissionBundle {
+	bundle := permissionBundle{ID: id, Name: name, About: about, IUID: iuid}
+	if createdAt.Valid {
+		bundle.CreatedAt = time.Unix(createdAt.Int64, 0)
+	}
+	if updatedAt.Valid {
+		bundle.UpdatedAt = time.Unix(updatedAt.Int64, 0)
+	}
+	return bundle
+}
+
+// listBundles lists every bundle of a kind, sorted by name.
+func (s *Service) listBundles(ctx context.Context, kind bundleKind) ([]permissionBundle, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	q := kind.queries(s.query.WithTx(tx))
+
+	rows, err := q.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundles, err := bundlesWithPermissions(ctx, q, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return bundles, nil
+}
+
+func (s *Service) createBundle(ctx context.Context, kind bundleKind, iuid int64, name, about string, permissions []string) (*permissionBundle, error) {
+	permissions, err := bundlePermissionNames(kind, name, about, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+	q := kind.queries(qtx)
+
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return nil, err
+	}
+	if !issuerPermissions.Has(kind.manage.Name) {
+		return nil, kind.errCannotManage
+	}
+	for _, permission := range permissions {
+		if !issuerPermissions.CanGrant(permission) {
+			return nil, kind.errCannotJoin
+		}
+	}
+
+	if _, err := q.getByName(ctx, name); err == nil {
+		return nil, kind.errExists
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	id, err := q.create(ctx, name, about, iuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := setBundlePermissions(ctx, q, id, permissions); err != nil {
+		return nil, err
+	}
+	bundle, err := bundleByID(ctx, kind, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// updateBundle replaces a bundle's name, about and permissions. Besides the kind's manage permission, the issuer
+// needs permission to grant the permissions it adds and to revoke those it removes, since its members gain or lose them.
+func (s *Service) updateBundle(ctx context.Context, kind bundleKind, iuid, id int64, name, about string, permissions []string) (*permissionBundle, error) {
+	permissions, err := bundlePermissionNames(kind, name, about, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+	q := kind.queries(qtx)
+
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return nil, err
+	}
+	if !issuerPermissions.Has(kind.manage.Name) {
+		return nil, kind.errCannotManage
+	}
+
+	current, err := bundleByID(ctx, kind, q, id)
+	if err != nil {
+		return nil, err
+	}
+	added, removed := diffPermissionNames(current.Permissions, permissions)
+	for _, permission := range added {
+		if !issuerPermissions.CanGrant(permission) {
+			return nil, kind.errCannotJoin
+		}
+	}
+	for _, permission := range removed {
+		if !issuerPermissions.CanRevoke(permission) {
+			return nil, kind.errCannotLeave
+		}
+	}
+
+	if name != current.Name {
+		if _, err := q.getByName(ctx, name); err == nil {
+			return nil, kind.errExists
+		} else if err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+
+	if err := q.update(ctx, id, name, about, iuid); err != nil {
+		return nil, err
+	}
+	if err := q.deletePermissions(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := setBundlePermissions(ctx, q, id, permissions); err != nil {
+		return nil, err
+	}
+	bundle, err := bundleByID(ctx, kind, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// deleteBundle takes everyone out of the bundle, so the issuer needs permission to revoke everything in it
+// as well as the kind's manage permission.
+func (s *Service) deleteBundle(ctx context.Context, kind bundleKind, iuid, id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+	q := kind.queries(qtx)
+
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return err
+	}
+	if !issuerPermissions.Has(kind.manage.Name) {
+		return kind.errCannotManage
+	}
+
+	bundle, err := bundleByID(ctx, kind, q, id)
+	if err != nil {
+		return err
+	}
+	for _, permission := range bundle.Permissions {
+		if !issuerPermissions.CanRevoke(permission) {
+			return kind.errCannotLeave
+		}
+	}
+
+	members, err := q.listMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, uid := range members {
+		if err := q.createChange(ctx, kind.leave, bundle, uid, iuid); err != nil {
+			return err
+		}
+	}
+	if err := q.delete(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// changeMembershipPermitted checks that uid exists, and that the issuer can do what can checks with every
+// permission in the bundle, returning denied if they can't.
+func changeMembershipPermitted(ctx context.Context, qtx *query.Queries, kind bundleKind, uid, iuid, id int64, can func(*Permissions, string) bool, denied error) (*permissionBundle, error) {
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	bundle, err := bundleByID(ctx, kind, kind.queries(qtx), id)
+	if err != nil {
+		return nil, err
+	}
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range bundle.Permissions {
+		if !can(&issuerPermissions, permission) {
+			return nil, denied
+		}
+	}
+	return bundle, nil
+}
+
+// effectivePermissions loads everything a user is permitted to do: their direct grants, roles and groups.
+func effectivePermissions(ctx context.Context, qtx *query.Queries, uid int64) (Permissions, error) {
+	records, err := userPermissions(ctx, qtx, uid)
+	if err != nil {
+		return Permissions{}, err
+	}
+	roles, err := userRoles(ctx, qtx, uid)
+	if err != nil {
+		return Permissions{}, err
+	}
+	groups, err := userGroups(ctx, qtx, uid)
+	if err != nil {
+		return Permissions{}, err
+	}
+	inherited := make([]InheritedPermissions, 0, len(roles)+len(groups))
+	for _, role := range roles {
+		inherited = append(inherited, role.Inherited())
+	}
+	for _, group := range groups {
+		inherited = append(inherited, group.Inherited())
+	}
+	return NewPermissions(uid, records, inherited...), nil
+}
+
+func userBundles(ctx context.Context, q bundleQueries, uid int64) ([]permissionBundle, error) {
+	rows, err := q.listByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return bundlesWithPermissions(ctx, q, rows)
+}
+
+func bundleByID(ctx context.Context, kind bundleKind, q bundleQueries, id int64) (*permissionBundle, error) {
+	row, err := q.get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, kind.errNotFound
+		}
+		return nil, err
+	}
+	bundles, err := bundlesWithPermissions(ctx, q, []permissionBundle{row})
+	if err != nil {
+		return nil, err
+	}
+	return &bundles[0], nil
+}
+
+func bundlesWithPermissions(ctx context.Context, q bundleQueries, rows []permissionBundle) ([]permissionBundle, error) {
+	bundles := make([]permissionBundle, 0, len(rows))
+	for _, bundle := range rows {
+		permissions, err := q.listPermissions(ctx, bundle.ID)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Permissions = permissions
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+func setBundlePermissions(ctx context.Context, q bundleQueries, id int64, permissions []string) error {
+	for _, permission := range permissions {
+		if err := q.createPermission(ctx, id, permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bundlePermissionNames validates a bundle, returning its permissions sorted and without duplicates.
+func bundlePermissionNames(kind bundleKind, name, about string, permissions []string) ([]string, error) {
+	if name == "" || name != strings.TrimSpace(name) || utf8.RuneCountInString(name) > 64 {
+		return nil, kind.errInvalid
+	}
+	if utf8.RuneCountInString(about) > 256 || len(permissions) == 0 {
+		return nil, kind.errInvalid
+	}
+	for _, r := range name + about {
+		if !unicode.IsPrint(r) {
+			return nil, kind.errInvalid
+		}
+	}
+
+	seen := map[string]bool{}
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !IsValidPermissionName(permission) || IsRootPermission(permission) {
+			return nil, kind.errInvalid
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			names = append(names, permission)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func diffPermissionNames(before, after []string) (added, removed []string) {
+	inBefore := map[string]bool{}
+	for _, name := range before {
+		inBefore[name] = true
+	}
+	inAfter := map[string]bool{}
+	for _, name := range after {
+		inAfter[name] = true
+		if !inBefore[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range before {
+		if !inAfter[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
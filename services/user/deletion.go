@@ -0,0 +1,290 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
+)
+
+// DeletedUID replaces the uid and iuid of erased users in the audit tables, which keep their rows
+// but have no foreign key to cascade from. The anonymising queries hardcode it.
+const DeletedUID int64 = -1
+
+// EraseBatchSize is the most accounts EraseDueAccounts erases in one call.
+const EraseBatchSize = 100
+
+// DefaultDeletionGracePeriod is how long a deleted account waits to be erased when the service
+// is built without a deletion.grace_period.
+const DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+var (
+	ErrCannotDeleteAccount     = errors.New("this issuer cannot delete this user's account")
+	ErrCannotDeleteRoot        = errors.New("users with root permissions cannot be deleted")
+	ErrAccountDeletionNotFound = errors.New("this user's account isn't scheduled for deletion")
+)
+
+type AccountDeletion struct {
+	UID  int64
+	IUID int64
+	// DeleteAfter is when the account becomes due for erasure.
+	DeleteAfter time.Time
+}
+
+// DeleteAccount schedules a user's account to be erased once the deletion.grace_period configured
+// for the service has passed. Until then the account is deactivated, and the deletion can be cancelled.
+// Users can delete their own account; deleting anyone else's needs the delete-user permission.
+func (s *Service) DeleteAccount(ctx context.Context, uid, iuid int64) (*AccountDeletion, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.DeleteAccount")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canDeleteAccount(ctx, qtx, uid, iuid); err != nil {
+		return nil, err
+	}
+
+	permissions, err := effectivePermissions(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range RootPermissions {
+		if permissions.Has(root.Name) {
+			return nil, ErrCannotDeleteRoot
+		}
+	}
+
+	existing, err := qtx.GetUserDeletion(ctx, uid)
+	if err == nil {
+		return accountDeletion(existing), nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	requestID, _ := requestid.FromContext(ctx)
+	deleteAfter := time.Now().Add(s.config.GetDuration("deletion.grace_period"))
+	if err := qtx.CreateUserDeletion(ctx, query.CreateUserDeletionParams{
+		DeleteAfter: deleteAfter.Unix(),
+		RequestID:   requestID,
+		IUID:        iuid,
+		UID:         uid,
+	}); err != nil {
+		return nil, err
+	}
+
+	// A suspension or ban stays in place, so cancelling the deletion can't be used to lift it.
+	status, err := accountStatus(ctx, qtx, uid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if status.Active() {
+		if err := setAccountStatus(ctx, qtx, uid, AccountStatus{
+			Status: StatusDeactivated,
+			Reason: "account deletion requested",
+			IUID:   iuid,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.metrics.deletions.WithLabelValues("scheduled").Inc()
+
+	return &AccountDeletion{UID: uid, IUID: iuid, DeleteAfter: time.Unix(deleteAfter.Unix(), 0)}, nil
+}
+
+// CancelAccountDeletion restores an account that's scheduled for deletion but hasn't been erased yet.
+func (s *Service) CancelAccountDeletion(ctx context.Context, uid, iuid int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.CancelAccountDeletion")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canDeleteAccount(ctx, qtx, uid, iuid); err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetUserDeletion(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrAccountDeletionNotFound
+		}
+		return err
+	}
+	if err := cancelAccountDeletion(ctx, qtx, uid, iuid); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.metrics.deletions.WithLabelValues("cancelled").Inc()
+
+	return nil
+}
+
+// EraseDueAccounts erases up to EraseBatchSize accounts whose deletion grace period has passed,
+// returning how many it erased. Each account is erased in its own transaction.
+func (s *Service) EraseDueAccounts(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.EraseDueAccounts")
+	defer span.End()
+
+	due, err := s.query.ListDueUserDeletions(ctx, query.ListDueUserDeletionsParams{
+		DeleteAfter: time.Now().Unix(),
+		Limit:       EraseBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, deletion := range due {
+		ok, err := s.eraseAccount(ctx, deletion.UID)
+		if err != nil {
+			return erased, err
+		}
+		if !ok {
+			continue
+		}
+		erased++
+		s.metrics.deletions.WithLabelValues("erased").Inc()
+		s.logger.InfoContext(ctx, "erased account", "uid", deletion.UID)
+	}
+	return erased, nil
+}
+
+// eraseAccount deletes the user, which cascades to everything with a foreign key to them,
+// and anonymises the audit rows that refer to them without one. The deletion is checked again
+// first, since it may have been cancelled after the due accounts were listed; if it has, nothing is
+// erased and it returns false. Users who have since been given a root permission are never erased,
+// so their deletion is cancelled instead, as if by the system with an IUID of 0.
+func (s *Service) eraseAccount(ctx context.Context, uid int64) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	deletion, err := qtx.GetUserDeletion(ctx, uid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if deletion.DeleteAfter > time.Now().Unix() {
+		return false, nil
+	}
+	permissions, err := effectivePermissions(ctx, qtx, uid)
+	if err != nil {
+		return false, err
+	}
+	for _, root := range RootPermissions {
+		if permissions.Has(root.Name) {
+			if err := cancelAccountDeletion(ctx, qtx, uid, 0); err != nil {
+				return false, err
+			}
+			if err := tx.Commit(); err != nil {
+				return false, err
+			}
+			s.metrics.deletions.WithLabelValues("cancelled").Inc()
+			s.logger.InfoContext(ctx, "cancelled account deletion for root permission holder", "uid", uid, "permission", root.Name)
+			return false, nil
+		}
+	}
+
+	for _, anonymize := range []func(context.Context, int64) error{
+		qtx.AnonymizeUserPermissionGrantsByUID,
+		qtx.AnonymizeUserPermissionGrantsByIUID,
+		qtx.AnonymizeUserPermissionRevocationsByUID,
+		qtx.AnonymizeUserPermissionRevocationsByIUID,
+		qtx.AnonymizeUserStatusChangesByUID,
+		qtx.AnonymizeUserStatusChangesByIUID,
+		qtx.AnonymizeUserDeletionsByIUID,
+		qtx.AnonymizeUsernameHistoryByIUID,
+		qtx.AnonymizeUsernameRulesByIUID,
+		qtx.AnonymizeThemesByIUID,
+		qtx.AnonymizeRolesByIUID,
+		qtx.AnonymizeUserRolesByIUID,
+		qtx.AnonymizeUserRoleChangesByUID,
+		qtx.AnonymizeUserRoleChangesByIUID,
+		qtx.AnonymizeGroupsByIUID,
+		qtx.AnonymizeGroupMembersByIUID,
+		qtx.AnonymizeGroupMemberChangesByUID,
+		qtx.AnonymizeGroupMemberChangesByIUID,
+	} {
+		if err := anonymize(ctx, uid); err != nil {
+			return false, err
+		}
+	}
+	if err := qtx.DeleteUser(ctx, uid); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// cancelAccountDeletion removes a user's scheduled deletion, and reactivates their account if it was
+// deactivated for it.
+func cancelAccountDeletion(ctx context.Context, qtx *query.Queries, uid, iuid int64) error {
+	if err := qtx.DeleteUserDeletion(ctx, uid); err != nil {
+		return err
+	}
+
+	status, err := accountStatus(ctx, qtx, uid, time.Now())
+	if err != nil {
+		return err
+	}
+	if status.Status != StatusDeactivated {
+		return nil
+	}
+	return setAccountStatus(ctx, qtx, uid, AccountStatus{
+		Status: StatusActive,
+		Reason: "account deletion cancelled",
+		IUID:   iuid,
+	})
+}
+
+func canDeleteAccount(ctx context.Context, qtx *query.Queries, uid, iuid int64) error {
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	ok, err := selfOrPermitted(ctx, qtx, uid, iuid, PermissionDeleteUser)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCannotDeleteAccount
+	}
+	return nil
+}
+
+func accountDeletion(row query.UserDeletion) *AccountDeletion {
+	return &AccountDeletion{
+		UID:         row.UID,
+		IUID:        row.IUID,
+		DeleteAfter: time.Unix(row.DeleteAfter, 0),
+	}
+}
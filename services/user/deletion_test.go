@@ -0,0 +1,190 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+func TestDeleteAccount(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Exec("DELETE FROM user_status_changes;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	config.Set("deletion.grace_period", time.Hour)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	staff, err := ps.Register(ctx, "teststaff", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.DeleteAccount(ctx, uid, staff)
+		require.ErrorIs(t, err, ErrCannotDeleteAccount)
+		_, err = ps.DeleteAccount(ctx, root, root)
+		require.ErrorIs(t, err, ErrCannotDeleteRoot)
+		_, err = ps.DeleteAccount(ctx, uid+100, uid+100)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("DefaultGracePeriod", func(t *testing.T) {
+		config := viper.New()
+		config.Set("root_username", TestRootUsername)
+		defaulted, err := New(db, WithConfig(config))
+		require.NoError(t, err)
+		require.Equal(t, DefaultDeletionGracePeriod, defaulted.config.GetDuration("deletion.grace_period"))
+
+		config.Set("deletion.grace_period", -time.Hour)
+		_, err = New(db, WithConfig(config))
+		require.Error(t, err)
+	})
+
+	t.Run("GracePeriod", func(t *testing.T) {
+		deletion, err := ps.DeleteAccount(ctx, uid, uid)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), deletion.DeleteAfter, time.Minute)
+
+		again, err := ps.DeleteAccount(ctx, uid, uid)
+		require.NoError(t, err)
+		require.Equal(t, deletion, again)
+
+		_, err = ps.Authenticate(ctx, TestUsername, TestPassword)
+		var inactiveErr *InactiveAccountError
+		require.True(t, errors.As(err, &inactiveErr))
+		require.Equal(t, StatusDeactivated, inactiveErr.Status.Status)
+
+		erased, err := ps.EraseDueAccounts(ctx)
+		require.NoError(t, err)
+		require.Zero(t, erased)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(ctx, staff, root, PermissionDeleteUser.Name)
+		require.NoError(t, err)
+		require.NoError(t, ps.CancelAccountDeletion(ctx, uid, staff))
+
+		_, err = ps.Authenticate(ctx, TestUsername, TestPassword)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, ps.CancelAccountDeletion(ctx, uid, uid), ErrAccountDeletionNotFound)
+	})
+
+	t.Run("CancelKeepsBan", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(ctx, staff, root, PermissionBanUser.Name)
+		require.NoError(t, err)
+		_, err = ps.BanUser(ctx, uid, staff, "spam", time.Time{})
+		require.NoError(t, err)
+
+		_, err = ps.DeleteAccount(ctx, uid, uid)
+		require.NoError(t, err)
+		require.NoError(t, ps.CancelAccountDeletion(ctx, uid, uid))
+
+		status, err := ps.AccountStatus(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, StatusBanned, status.Status)
+		_, err = db.Exec("DELETE FROM user_statuses WHERE uid = ?;", uid)
+		require.NoError(t, err)
+	})
+
+	t.Run("CancelledAfterListing", func(t *testing.T) {
+		_, err := ps.DeleteAccount(ctx, uid, uid)
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE user_deletions SET delete_after = ? WHERE uid = ?;", time.Now().Add(-time.Minute).Unix(), uid)
+		require.NoError(t, err)
+
+		due, err := ps.query.ListDueUserDeletions(ctx, query.ListDueUserDeletionsParams{DeleteAfter: time.Now().Unix(), Limit: EraseBatchSize})
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		require.NoError(t, ps.CancelAccountDeletion(ctx, uid, uid))
+
+		erased, err := ps.eraseAccount(ctx, due[0].UID)
+		require.NoError(t, err)
+		require.False(t, erased)
+		_, err = ps.Profile(ctx, uid)
+		require.NoError(t, err)
+	})
+
+	t.Run("RootIsNeverErased", func(t *testing.T) {
+		cancelled := testutil.ToFloat64(ps.metrics.deletions.WithLabelValues("cancelled"))
+		err := ps.query.CreateUserDeletion(ctx, query.CreateUserDeletionParams{
+			DeleteAfter: time.Now().Add(-time.Minute).Unix(),
+			IUID:        root,
+			UID:         root,
+		})
+		require.NoError(t, err)
+
+		erased, err := ps.EraseDueAccounts(ctx)
+		require.NoError(t, err)
+		require.Zero(t, erased)
+		_, err = ps.Profile(ctx, root)
+		require.NoError(t, err)
+		_, err = ps.query.GetUserDeletion(ctx, root)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		require.Equal(t, cancelled+1, testutil.ToFloat64(ps.metrics.deletions.WithLabelValues("cancelled")))
+	})
+
+	t.Run("Erase", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(ctx, uid, root, PermissionViewAllRooms.Name)
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO user_permission_grants (name, iuid, uid) VALUES (?, ?, ?);", PermissionViewAllRooms.Name, uid, staff)
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO emails (address, uid, verified) VALUES (?, ?, true);", "erased@web.site", uid)
+		require.NoError(t, err)
+		_, err = ps.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Value: ThemeDark}})
+		require.NoError(t, err)
+
+		_, err = ps.DeleteAccount(ctx, uid, staff)
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE user_deletions SET delete_after = ? WHERE uid = ?;", time.Now().Add(-time.Minute).Unix(), uid)
+		require.NoError(t, err)
+
+		erased, err := ps.EraseDueAccounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, erased)
+
+		_, err = ps.Profile(ctx, uid)
+		require.ErrorIs(t, err, ErrUserNotFound)
+
+		for _, q := range []string{
+			"SELECT COUNT(*) FROM emails WHERE uid = ?;",
+			"SELECT COUNT(*) FROM user_setting_values WHERE uid = ?;",
+			"SELECT COUNT(*) FROM user_permissions WHERE uid = ?;",
+			"SELECT COUNT(*) FROM user_statuses WHERE uid = ?;",
+			"SELECT COUNT(*) FROM user_deletions WHERE uid = ?;",
+			"SELECT COUNT(*) FROM users_search WHERE rowid = ?;",
+			"SELECT COUNT(*) FROM user_permission_grants WHERE uid = ?1 OR iuid = ?1;",
+			"SELECT COUNT(*) FROM user_status_changes WHERE uid = ?1 OR iuid = ?1;",
+		} {
+			var count int
+			require.NoError(t, db.QueryRow(q, uid).Scan(&count))
+			require.Zero(t, count, q)
+		}
+
+		var anonymized int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM user_permission_grants WHERE uid = ?1 OR iuid = ?1;", DeletedUID).Scan(&anonymized))
+		require.Equal(t, 2, anonymized)
+	})
+}
@@ -0,0 +1,438 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+var ErrCannotExportUserData = errors.New("this issuer cannot export this user's data")
+
+// UserData is everything stored about a user, for answering subject access requests.
+// It leaves out the passphrase hash. Sessions and login history aren't stored by this service.
+type UserData struct {
+	ExportedAt            time.Time                `json:"exported_at"`
+	User                  UserDataUser             `json:"user"`
+	Profile               UserDataProfile          `json:"profile"`
+	Settings              Settings                 `json:"settings"`
+	UsernameHistory       []UserDataUsernameChange `json:"username_history"`
+	Emails                []UserDataEmail          `json:"emails"`
+	Permissions           []UserDataPermission     `json:"permissions"`
+	PermissionGrants      []UserDataPermissionLog  `json:"permission_grants"`
+	PermissionRevocations []UserDataPermissionLog  `json:"permission_revocations"`
+	Roles                 []UserDataRole           `json:"roles"`
+	RoleChanges           []UserDataRoleChange     `json:"role_changes"`
+	Groups                []UserDataGroup          `json:"groups"`
+	GroupChanges          []UserDataGroupChange    `json:"group_changes"`
+	Status                UserDataStatus           `json:"status"`
+	StatusChanges         []UserDataStatusChange   `json:"status_changes"`
+	Deletion              *UserDataAccountDeletion `json:"deletion,omitempty"`
+}
+
+type UserDataUser struct {
+	ID        int64      `json:"id"`
+	Username  string     `json:"username"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type UserDataProfile struct {
+	DisplayName string     `json:"display_name"`
+	Bio         string     `json:"bio"`
+	Pronouns    string     `json:"pronouns"`
+	AvatarURL   string     `json:"avatar_url"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+type UserDataUsernameChange struct {
+	ID            int64      `json:"id"`
+	OldUsername   string     `json:"old_username"`
+	NewUsername   string     `json:"new_username"`
+	ReservedUntil time.Time  `json:"reserved_until"`
+	IUID          int64      `json:"iuid"`
+	RequestID     string     `json:"request_id"`
+	CreatedAt     *time.Time `json:"created_at"`
+}
+
+type UserDataEmail struct {
+	ID        int64      `json:"id"`
+	Address   string     `json:"address"`
+	Verified  bool       `json:"verified"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type UserDataPermission struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	IUID      int64      `json:"iuid"`
+	CreatedAt *time.Time `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UserDataPermissionLog is a grant or revocation either made to the user or by them.
+// UID is left out of those the user made to someone else.
+type UserDataPermissionLog struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	UID       int64      `json:"uid,omitempty"`
+	IUID      int64      `json:"iuid"`
+	RequestID string     `json:"request_id"`
+	CreatedAt *time.Time `json:"created_at"`
+	// ExpiresAt is the expiry a grant was made with. It's left out for grants made until revoked, and revocations.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type UserDataRole struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// UserDataRoleChange is a change to the user's roles, or one they made to someone else's.
+// UID is left out of those made to someone else.
+type UserDataRoleChange struct {
+	ID        int64      `json:"id"`
+	Action    string     `json:"action"`
+	RoleID    int64      `json:"role_id"`
+	RoleName  string     `json:"role_name"`
+	UID       int64      `json:"uid,omitempty"`
+	IUID      int64      `json:"iuid"`
+	RequestID string     `json:"request_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type UserDataGroup struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// UserDataGroupChange is a change to the user's groups, or one they made to someone else's.
+// UID is left out of those made to someone else.
+type UserDataGroupChange struct {
+	ID        int64      `json:"id"`
+	Action    string     `json:"action"`
+	GroupID   int64      `json:"group_id"`
+	GroupName string     `json:"group_name"`
+	UID       int64      `json:"uid,omitempty"`
+	IUID      int64      `json:"iuid"`
+	RequestID string     `json:"request_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type UserDataStatus struct {
+	Status string     `json:"status"`
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+	IUID   int64      `json:"iuid"`
+}
+
+// UserDataStatusChange is a change to the user's account status, or one they made to someone else's.
+// UID and Reason are left out of those made to someone else, since the reason is about them.
+type UserDataStatusChange struct {
+	ID        int64      `json:"id"`
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason,omitempty"`
+	Until     *time.Time `json:"until"`
+	UID       int64      `json:"uid,omitempty"`
+	IUID      int64      `json:"iuid"`
+	RequestID string     `json:"request_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type UserDataAccountDeletion struct {
+	IUID        int64      `json:"iuid"`
+	DeleteAfter time.Time  `json:"delete_after"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+// ExportUserData reads everything stored about a user in one transaction. Users can export their own data;
+// exporting anyone else's needs the export-user-data permission.
+func (s *Service) ExportUserData(ctx context.Context, uid, iuid int64) (*UserData, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ExportUserData")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	u, err := qtx.GetUser(ctx, uid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	ok, err := selfOrPermitted(ctx, qtx, uid, iuid, PermissionExportUserData)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCannotExportUserData
+	}
+
+	data := &UserData{
+		ExportedAt: time.Now().UTC(),
+		User: UserDataUser{
+			ID:        u.ID,
+			Username:  u.Username,
+			CreatedAt: unixTime(u.CreatedAt),
+			UpdatedAt: unixTime(u.UpdatedAt),
+		},
+		UsernameHistory:       []UserDataUsernameChange{},
+		Emails:                []UserDataEmail{},
+		Permissions:           []UserDataPermission{},
+		PermissionGrants:      []UserDataPermissionLog{},
+		PermissionRevocations: []UserDataPermissionLog{},
+		StatusChanges:         []UserDataStatusChange{},
+		Roles:                 []UserDataRole{},
+		RoleChanges:           []UserDataRoleChange{},
+		Groups:                []UserDataGroup{},
+		GroupChanges:          []UserDataGroupChange{},
+	}
+
+	fields, err := profileFields(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	data.Profile = UserDataProfile{
+		DisplayName: fields.DisplayName,
+		Bio:         fields.Bio,
+		Pronouns:    fields.Pronouns,
+		AvatarURL:   fields.AvatarURL,
+	}
+	if !fields.UpdatedAt.IsZero() {
+		updatedAt := fields.UpdatedAt.UTC()
+		data.Profile.UpdatedAt = &updatedAt
+	}
+
+	settings, err := userSettings(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	data.Settings = settings
+
+	history, err := qtx.ListUsernameHistory(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range history {
+		data.UsernameHistory = append(data.UsernameHistory, UserDataUsernameChange{
+			ID:            change.ID,
+			OldUsername:   change.OldUsername,
+			NewUsername:   change.NewUsername,
+			ReservedUntil: time.Unix(change.ReservedUntil, 0).UTC(),
+			IUID:          change.IUID,
+			RequestID:     change.RequestID,
+			CreatedAt:     unixTime(change.CreatedAt),
+		})
+	}
+
+	emails, err := qtx.ListEmails(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range emails {
+		data.Emails = append(data.Emails, UserDataEmail{
+			ID:        email.ID,
+			Address:   email.Address,
+			Verified:  email.Verified != 0,
+			CreatedAt: unixTime(email.CreatedAt),
+			UpdatedAt: unixTime(email.UpdatedAt),
+		})
+	}
+
+	permissions, err := userPermissions(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		data.Permissions = append(data.Permissions, UserDataPermission{
+			Name:      permission.Name,
+			Scope:     permission.Scope,
+			IUID:      permission.IUID,
+			CreatedAt: unixTime(permission.CreatedAt),
+			ExpiresAt: unixTime(permission.ExpiresAt),
+		})
+	}
+
+	grants, err := qtx.ListUserPermissionGrants(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		data.PermissionGrants = append(data.PermissionGrants, UserDataPermissionLog{
+			ID:        grant.ID,
+			Name:      grant.Name,
+			Scope:     grant.Scope,
+			UID:       subjectUID(uid, grant.UID),
+			IUID:      grant.IUID,
+			RequestID: grant.RequestID,
+			CreatedAt: unixTime(grant.CreatedAt),
+			ExpiresAt: unixTime(grant.ExpiresAt),
+		})
+	}
+
+	revocations, err := qtx.ListUserPermissionRevocations(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, revocation := range revocations {
+		data.PermissionRevocations = append(data.PermissionRevocations, UserDataPermissionLog{
+			ID:        revocation.ID,
+			Name:      revocation.Name,
+			Scope:     revocation.Scope,
+			UID:       subjectUID(uid, revocation.UID),
+			IUID:      revocation.IUID,
+			RequestID: revocation.RequestID,
+			CreatedAt: unixTime(revocation.CreatedAt),
+		})
+	}
+
+	roles, err := userRoles(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		data.Roles = append(data.Roles, UserDataRole{
+			ID:          role.ID,
+			Name:        role.Name,
+			Permissions: role.Permissions,
+		})
+	}
+
+	roleChanges, err := qtx.ListUserRoleChanges(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range roleChanges {
+		data.RoleChanges = append(data.RoleChanges, UserDataRoleChange{
+			ID:        change.ID,
+			Action:    change.Action,
+			RoleID:    change.RoleID,
+			RoleName:  change.RoleName,
+			UID:       subjectUID(uid, change.UID),
+			IUID:      change.IUID,
+			RequestID: change.RequestID,
+			CreatedAt: unixTime(change.CreatedAt),
+		})
+	}
+
+	groups, err := userGroups(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		data.Groups = append(data.Groups, UserDataGroup{
+			ID:          group.ID,
+			Name:        group.Name,
+			Permissions: group.Permissions,
+		})
+	}
+
+	groupChanges, err := qtx.ListGroupMemberChanges(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range groupChanges {
+		data.GroupChanges = append(data.GroupChanges, UserDataGroupChange{
+			ID:        change.ID,
+			Action:    change.Action,
+			GroupID:   change.GroupID,
+			GroupName: change.GroupName,
+			UID:       subjectUID(uid, change.UID),
+			IUID:      change.IUID,
+			RequestID: change.RequestID,
+			CreatedAt: unixTime(change.CreatedAt),
+		})
+	}
+
+	status, err := accountStatus(ctx, qtx, uid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	data.Status = UserDataStatus{
+		Status: status.Status,
+		Reason: status.Reason,
+		IUID:   status.IUID,
+	}
+	if !status.Until.IsZero() {
+		until := status.Until.UTC()
+		data.Status.Until = &until
+	}
+
+	changes, err := qtx.ListUserStatusChanges(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		reason := change.Reason
+		if change.UID != uid {
+			reason = ""
+		}
+		data.StatusChanges = append(data.StatusChanges, UserDataStatusChange{
+			ID:        change.ID,
+			Status:    change.Status,
+			Reason:    reason,
+			Until:     unixTime(change.Until),
+			UID:       subjectUID(uid, change.UID),
+			IUID:      change.IUID,
+			RequestID: change.RequestID,
+			CreatedAt: unixTime(change.CreatedAt),
+		})
+	}
+
+	deletion, err := qtx.GetUserDeletion(ctx, uid)
+	if err == nil {
+		data.Deletion = &UserDataAccountDeletion{
+			IUID:        deletion.IUID,
+			DeleteAfter: time.Unix(deletion.DeleteAfter, 0).UTC(),
+			CreatedAt:   unixTime(deletion.CreatedAt),
+		}
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func unixTime(t sql.NullInt64) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	u := time.Unix(t.Int64, 0).UTC()
+	return &u
+}
+
+// subjectUID returns who a logged change was made to, or zero if it wasn't made to uid.
+// Changes the user only issued are exported without saying who they were about.
+func subjectUID(uid, changeUID int64) int64 {
+	if changeUID != uid {
+		return 0
+	}
+	return changeUID
+}
+
+// selfOrPermitted reports whether iuid is acting on their own account, or holds the permission to act on anyone's.
+func selfOrPermitted(ctx context.Context, qtx *query.Queries, uid, iuid int64, permission Permission) (bool, error) {
+	if uid == iuid {
+		return true, nil
+	}
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return false, err
+	}
+	return issuerPermissions.Has(permission.Name), nil
+}
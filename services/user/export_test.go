@@ -0,0 +1,92 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestExportUserData(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Exec("DELETE FROM user_status_changes;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	config.Set("deletion.grace_period", time.Hour)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	compliance, err := ps.Register(ctx, "testcompliance", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	_, err = ps.ExportUserData(ctx, uid, compliance)
+	require.ErrorIs(t, err, ErrCannotExportUserData)
+	_, err = ps.ExportUserData(ctx, uid+100, uid+100)
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	_, err = ps.GrantUserPermission(ctx, compliance, root, PermissionExportUserData.Name)
+	require.NoError(t, err)
+	_, err = ps.GrantUserPermission(ctx, uid, root, PermissionViewAllRooms.Name)
+	require.NoError(t, err)
+	_, err = ps.RevokeUserPermission(ctx, uid, root, PermissionViewAllRooms.Name)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO emails (address, uid, verified) VALUES (?, ?, true);", "export@web.site", uid)
+	require.NoError(t, err)
+	_, err = ps.DeleteAccount(ctx, uid, uid)
+	require.NoError(t, err)
+
+	data, err := ps.ExportUserData(ctx, uid, compliance)
+	require.NoError(t, err)
+	require.Equal(t, TestUsername, data.User.Username)
+	require.NotNil(t, data.User.CreatedAt)
+	require.Equal(t, ThemeDefault, data.Settings[SettingTheme.Key])
+	require.Len(t, data.Emails, 1)
+	require.True(t, data.Emails[0].Verified)
+	require.Empty(t, data.Permissions)
+	require.Len(t, data.PermissionGrants, 1)
+	require.Len(t, data.PermissionRevocations, 1)
+	require.Equal(t, StatusDeactivated, data.Status.Status)
+	require.Len(t, data.StatusChanges, 1)
+	require.NotNil(t, data.Deletion)
+
+	_, err = ps.GrantUserPermission(ctx, compliance, root, PermissionSuspendUser.Name)
+	require.NoError(t, err)
+	_, err = ps.SuspendUser(ctx, uid, compliance, "spamming the lobby", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	own, err := ps.ExportUserData(ctx, compliance, compliance)
+	require.NoError(t, err)
+	require.Len(t, own.Permissions, 2)
+	require.Len(t, own.PermissionGrants, 2)
+	for _, grant := range own.PermissionGrants {
+		require.Equal(t, compliance, grant.UID)
+	}
+	require.Len(t, own.StatusChanges, 1)
+	require.Equal(t, StatusSuspended, own.StatusChanges[0].Status)
+	require.Equal(t, compliance, own.StatusChanges[0].IUID)
+	require.Zero(t, own.StatusChanges[0].UID)
+	require.Empty(t, own.StatusChanges[0].Reason)
+	require.Nil(t, own.Deletion)
+	require.NotNil(t, own.Roles)
+	require.NotNil(t, own.RoleChanges)
+	require.NotNil(t, own.Groups)
+	require.NotNil(t, own.GroupChanges)
+}
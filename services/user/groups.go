@@ -0,0 +1,347 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
+)
+
+const (
+	GroupChangeAdd    = "add"
+	GroupChangeRemove = "remove"
+)
+
+var (
+	ErrInvalidGroup            = errors.New("a group needs a name of up to 64 characters, an about of up to 256, and at least one permission that isn't a root permission")
+	ErrGroupExists             = errors.New("a group with this name already exists")
+	ErrGroupNotFound           = errors.New("no group exists with this id")
+	ErrCannotManageGroups      = errors.New("this issuer cannot manage groups")
+	ErrCannotAddGroupMember    = errors.New("this issuer cannot grant every permission in this group")
+	ErrCannotRemoveGroupMember = errors.New("this issuer cannot revoke every permission in this group")
+)
+
+// Group is a set of users, like "Room Builders", that are granted permissions together. Members have
+// the group's permissions for as long as they're in it, on top of their own and their roles'.
+type Group struct {
+	ID          int64
+	Name        string
+	About       string
+	Permissions []string
+	IUID        int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+var groupBundles = bundleKind{
+	manage:          PermissionManageGroups,
+	leave:           GroupChangeRemove,
+	errInvalid:      ErrInvalidGroup,
+	errExists:       ErrGroupExists,
+	errNotFound:     ErrGroupNotFound,
+	errCannotManage: ErrCannotManageGroups,
+	errCannotJoin:   ErrCannotAddGroupMember,
+	errCannotLeave:  ErrCannotRemoveGroupMember,
+	queries:         groupQueries,
+}
+
+// Inherited returns the permissions a user gets from being in the group.
+func (g Group) Inherited() InheritedPermissions {
+	return InheritedPermissions{
+		Source: PermissionSource{Kind: PermissionSourceGroup, ID: g.ID, Name: g.Name},
+		Names:  g.Permissions,
+	}
+}
+
+// GroupMember is a user in a group, added by IUID.
+type GroupMember struct {
+	UID       int64
+	IUID      int64
+	CreatedAt time.Time
+}
+
+// Groups lists every group, sorted by name.
+func (s *Service) Groups(ctx context.Context) ([]Group, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Groups")
+	defer span.End()
+
+	bundles, err := s.listBundles(ctx, groupBundles)
+	if err != nil {
+		return nil, err
+	}
+	return groupsFromBundles(bundles), nil
+}
+
+// CreateGroup needs the manage-groups permission, and permission to grant everything granted to the group.
+func (s *Service) CreateGroup(ctx context.Context, iuid int64, name, about string, permissions []string) (*Group, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.CreateGroup")
+	defer span.End()
+
+	bundle, err := s.createBundle(ctx, groupBundles, iuid, name, about, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return (*Group)(bundle), nil
+}
+
+// UpdateGroup replaces a group's name, about and permissions. Besides manage-groups, the issuer needs permission
+// to grant the permissions it adds and to revoke those it removes, since the group's members gain or lose them.
+func (s *Service) UpdateGroup(ctx context.Context, iuid, id int64, name, about string, permissions []string) (*Group, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UpdateGroup")
+	defer span.End()
+
+	bundle, err := s.updateBundle(ctx, groupBundles, iuid, id, name, about, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return (*Group)(bundle), nil
+}
+
+// DeleteGroup removes everyone from the group, so the issuer needs permission to revoke everything
+// granted to it as well as manage-groups.
+func (s *Service) DeleteGroup(ctx context.Context, iuid, id int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.DeleteGroup")
+	defer span.End()
+
+	return s.deleteBundle(ctx, groupBundles, iuid, id)
+}
+
+// GroupMembers lists the users in a group, sorted by uid.
+func (s *Service) GroupMembers(ctx context.Context, id int64) ([]GroupMember, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GroupMembers")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := bundleByID(ctx, groupBundles, groupQueries(qtx), id); err != nil {
+		return nil, err
+	}
+	rows, err := qtx.ListGroupMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]GroupMember, 0, len(rows))
+	for _, row := range rows {
+		member := GroupMember{UID: row.UID, IUID: row.IUID}
+		if row.CreatedAt.Valid {
+			member.CreatedAt = time.Unix(row.CreatedAt.Int64, 0)
+		}
+		members = append(members, member)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddGroupMember puts a user in a group. The issuer needs permission to grant every permission granted to it.
+// Adding a user who's already in the group does nothing.
+func (s *Service) AddGroupMember(ctx context.Context, uid, iuid, groupID int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.AddGroupMember")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	group, err := changeMembershipPermitted(ctx, qtx, groupBundles, uid, iuid, groupID, (*Permissions).CanGrant, ErrCannotAddGroupMember)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetGroupMember(ctx, query.GetGroupMemberParams{UID: uid, GroupID: groupID}); err == nil {
+		return tx.Commit()
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+	if err := qtx.CreateGroupMember(ctx, query.CreateGroupMemberParams{
+		GroupID: groupID,
+		UID:     uid,
+		IUID:    iuid,
+	}); err != nil {
+		return err
+	}
+	if err := createGroupMemberChange(ctx, qtx, GroupChangeAdd, group, uid, iuid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveGroupMember takes a user out of a group. The issuer needs permission to revoke every permission
+// granted to it. Removing a user who isn't in the group does nothing.
+func (s *Service) RemoveGroupMember(ctx context.Context, uid, iuid, groupID int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.RemoveGroupMember")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	group, err := changeMembershipPermitted(ctx, qtx, groupBundles, uid, iuid, groupID, (*Permissions).CanRevoke, ErrCannotRemoveGroupMember)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetGroupMember(ctx, query.GetGroupMemberParams{UID: uid, GroupID: groupID}); err != nil {
+		if err == sql.ErrNoRows {
+			return tx.Commit()
+		}
+		return err
+	}
+	if err := qtx.DeleteGroupMember(ctx, query.DeleteGroupMemberParams{UID: uid, GroupID: groupID}); err != nil {
+		return err
+	}
+	if err := createGroupMemberChange(ctx, qtx, GroupChangeRemove, group, uid, iuid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UserGroups lists the groups a user is in, sorted by name.
+func (s *Service) UserGroups(ctx context.Context, uid int64) ([]Group, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UserGroups")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	groups, err := userGroups(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func userGroups(ctx context.Context, qtx *query.Queries, uid int64) ([]Group, error) {
+	bundles, err := userBundles(ctx, groupQueries(qtx), uid)
+	if err != nil {
+		return nil, err
+	}
+	return groupsFromBundles(bundles), nil
+}
+
+func groupsFromBundles(bundles []permissionBundle) []Group {
+	groups := make([]Group, 0, len(bundles))
+	for _, bundle := range bundles {
+		groups = append(groups, Group(bundle))
+	}
+	return groups
+}
+
+func createGroupMemberChange(ctx context.Context, qtx *query.Queries, action string, group *permissionBundle, uid, iuid int64) error {
+	requestID, _ := requestid.FromContext(ctx)
+	return qtx.CreateGroupMemberChange(ctx, query.CreateGroupMemberChangeParams{
+		Action:    action,
+		GroupID:   group.ID,
+		GroupName: group.Name,
+		RequestID: requestID,
+		IUID:      iuid,
+		UID:       uid,
+	})
+}
+
+func groupBundle(row query.UserGroup) permissionBundle {
+	return newPermissionBundle(row.ID, row.Name, row.About, row.IUID, row.CreatedAt, row.UpdatedAt)
+}
+
+func groupBundleList(rows []query.UserGroup) []permissionBundle {
+	bundles := make([]permissionBundle, 0, len(rows))
+	for _, row := range rows {
+		bundles = append(bundles, groupBundle(row))
+	}
+	return bundles
+}
+
+func groupQueries(qtx *query.Queries) bundleQueries {
+	return bundleQueries{
+		get: func(ctx context.Context, id int64) (permissionBundle, error) {
+			row, err := qtx.GetGroup(ctx, id)
+			return groupBundle(row), err
+		},
+		getByName: func(ctx context.Context, name string) (permissionBundle, error) {
+			row, err := qtx.GetGroupByName(ctx, name)
+			return groupBundle(row), err
+		},
+		list: func(ctx context.Context) ([]permissionBundle, error) {
+			rows, err := qtx.ListGroups(ctx)
+			return groupBundleList(rows), err
+		},
+		listByUser: func(ctx context.Context, uid int64) ([]permissionBundle, error) {
+			rows, err := qtx.ListUserGroups(ctx, uid)
+			return groupBundleList(rows), err
+		},
+		create: func(ctx context.Context, name, about string, iuid int64) (int64, error) {
+			result, err := qtx.CreateGroup(ctx, query.CreateGroupParams{Name: name, About: about, IUID: iuid})
+			if err != nil {
+				return 0, err
+			}
+			return result.LastInsertId()
+		},
+		update: func(ctx context.Context, id int64, name, about string, iuid int64) error {
+			return qtx.UpdateGroup(ctx, query.UpdateGroupParams{Name: name, About: about, IUID: iuid, ID: id})
+		},
+		delete: qtx.DeleteGroup,
+		listPermissions: func(ctx context.Context, id int64) ([]string, error) {
+			rows, err := qtx.ListGroupPermissions(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(rows))
+			for _, row := range rows {
+				names = append(names, row.Name)
+			}
+			return names, nil
+		},
+		createPermission: func(ctx context.Context, id int64, name string) error {
+			return qtx.CreateGroupPermission(ctx, query.CreateGroupPermissionParams{Name: name, GroupID: id})
+		},
+		deletePermissions: qtx.DeleteGroupPermissions,
+		listMembers: func(ctx context.Context, id int64) ([]int64, error) {
+			rows, err := qtx.ListGroupMembers(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			uids := make([]int64, 0, len(rows))
+			for _, row := range rows {
+				uids = append(uids, row.UID)
+			}
+			return uids, nil
+		},
+		createChange: func(ctx context.Context, action string, group *permissionBundle, uid, iuid int64) error {
+			return createGroupMemberChange(ctx, qtx, action, group, uid, iuid)
+		},
+	}
+}
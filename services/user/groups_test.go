@@ -0,0 +1,165 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestGroups(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM roles;")
+		db.Exec("DELETE FROM user_groups;")
+		db.Exec("DELETE FROM user_role_changes;")
+		db.Exec("DELETE FROM user_group_member_changes;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	staff, err := ps.Register(ctx, "teststaff", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	changelog := []string{PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name}
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.CreateGroup(ctx, staff, "Room Builders", "", changelog)
+		require.ErrorIs(t, err, ErrCannotManageGroups)
+
+		// manage-groups alone isn't enough to grant permissions to a group.
+		_, err = ps.GrantUserPermission(ctx, staff, root, PermissionManageGroups.Name)
+		require.NoError(t, err)
+		_, err = ps.CreateGroup(ctx, staff, "Room Builders", "", changelog)
+		require.ErrorIs(t, err, ErrCannotAddGroupMember)
+	})
+
+	_, err = ps.GrantUserPermission(ctx, root, root, PermissionManageGroups.Name)
+	require.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := ps.CreateGroup(ctx, root, " Padded", "", changelog)
+		require.ErrorIs(t, err, ErrInvalidGroup)
+		_, err = ps.CreateGroup(ctx, root, "Empty", "", nil)
+		require.ErrorIs(t, err, ErrInvalidGroup)
+		_, err = ps.CreateGroup(ctx, root, "Root", "", []string{PermissionRevokeAll.Name})
+		require.ErrorIs(t, err, ErrInvalidGroup)
+	})
+
+	group, err := ps.CreateGroup(ctx, root, "Room Builders", "Builds and releases rooms.", changelog)
+	require.NoError(t, err)
+	require.Equal(t, changelog, group.Permissions)
+	_, err = ps.CreateGroup(ctx, root, "Room Builders", "", changelog)
+	require.ErrorIs(t, err, ErrGroupExists)
+
+	t.Run("AddMember", func(t *testing.T) {
+		require.ErrorIs(t, ps.AddGroupMember(ctx, uid, staff, group.ID), ErrCannotAddGroupMember)
+		require.ErrorIs(t, ps.AddGroupMember(ctx, uid, root, group.ID+100), ErrGroupNotFound)
+		require.ErrorIs(t, ps.AddGroupMember(ctx, uid+100, root, group.ID), ErrUserNotFound)
+
+		require.NoError(t, ps.AddGroupMember(ctx, uid, root, group.ID))
+		require.NoError(t, ps.AddGroupMember(ctx, uid, root, group.ID))
+
+		members, err := ps.GroupMembers(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Equal(t, uid, members[0].UID)
+		require.Equal(t, root, members[0].IUID)
+
+		groups, err := ps.UserGroups(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, group.ID, groups[0].ID)
+	})
+
+	t.Run("EffectivePermissions", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(ctx, uid, root, PermissionCreateChangelog.Name)
+		require.NoError(t, err)
+		_, err = ps.GrantUserPermission(ctx, root, root, PermissionManageRoles.Name)
+		require.NoError(t, err)
+		role, err := ps.CreateRole(ctx, root, "Changelog Editor", "", []string{PermissionCreateChangelog.Name})
+		require.NoError(t, err)
+		require.NoError(t, ps.AssignRole(ctx, uid, root, role.ID))
+
+		permissions, err := ps.EffectivePermissions(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, []string{PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name}, permissions.List())
+
+		sources := permissions.Sources(PermissionCreateChangelog.Name)
+		require.Len(t, sources, 3)
+		require.Equal(t, PermissionSourceDirect, sources[0].Kind)
+		require.Equal(t, PermissionSource{Kind: PermissionSourceRole, ID: role.ID, Name: role.Name}, sources[1])
+		require.Equal(t, PermissionSource{Kind: PermissionSourceGroup, ID: group.ID, Name: group.Name}, sources[2])
+		require.Equal(t, []PermissionSource{
+			{Kind: PermissionSourceGroup, ID: group.ID, Name: group.Name},
+		}, permissions.Sources(PermissionReleaseChangelog.Name))
+
+		profile, err := ps.Profile(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, permissions.List(), profile.Permissions)
+
+		_, err = ps.EffectivePermissions(ctx, uid+100)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated, err := ps.UpdateGroup(ctx, root, group.ID, "Room Reviewers", "", []string{PermissionReleaseChangelog.Name})
+		require.NoError(t, err)
+		require.Equal(t, "Room Reviewers", updated.Name)
+
+		permissions, err := ps.EffectivePermissions(ctx, uid)
+		require.NoError(t, err)
+		require.True(t, permissions.Has(PermissionReleaseChangelog.Name))
+		require.Equal(t, []PermissionSource{
+			{Kind: PermissionSourceGroup, ID: group.ID, Name: "Room Reviewers"},
+		}, permissions.Sources(PermissionReleaseChangelog.Name))
+	})
+
+	t.Run("RemoveMember", func(t *testing.T) {
+		require.ErrorIs(t, ps.RemoveGroupMember(ctx, uid, staff, group.ID), ErrCannotRemoveGroupMember)
+		require.NoError(t, ps.RemoveGroupMember(ctx, uid, root, group.ID))
+		require.NoError(t, ps.RemoveGroupMember(ctx, uid, root, group.ID))
+
+		permissions, err := ps.EffectivePermissions(ctx, uid)
+		require.NoError(t, err)
+		require.False(t, permissions.Has(PermissionReleaseChangelog.Name))
+
+		changes, err := ps.query.ListGroupMemberChanges(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, GroupChangeAdd, changes[0].Action)
+		require.Equal(t, GroupChangeRemove, changes[1].Action)
+		require.Equal(t, "Room Reviewers", changes[1].GroupName)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, ps.AddGroupMember(ctx, uid, root, group.ID))
+		require.ErrorIs(t, ps.DeleteGroup(ctx, staff, group.ID), ErrCannotRemoveGroupMember)
+		require.NoError(t, ps.DeleteGroup(ctx, root, group.ID))
+		require.ErrorIs(t, ps.DeleteGroup(ctx, root, group.ID), ErrGroupNotFound)
+
+		groups, err := ps.UserGroups(ctx, uid)
+		require.NoError(t, err)
+		require.Empty(t, groups)
+		changes, err := ps.query.ListGroupMemberChanges(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, GroupChangeRemove, changes[len(changes)-1].Action)
+	})
+}
@@ -3,12 +3,18 @@ package user
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
 )
 
+// userPermissions lists a user's direct grants, leaving out any that have expired but haven't been swept yet.
 func userPermissions(ctx context.Context, qtx *query.Queries, uid int64) ([]query.UserPermission, error) {
-	permissions, err := qtx.ListUserPermissions(ctx, uid)
+	permissions, err := qtx.ListUserPermissions(ctx, query.ListUserPermissionsParams{
+		UID: uid,
+		Now: time.Now().Unix(),
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return []query.UserPermission{}, nil
@@ -20,7 +26,7 @@ func userPermissions(ctx context.Context, qtx *query.Queries, uid int64) ([]quer
 
 func grantRootUserPermissions(ctx context.Context, qtx *query.Queries, uid int64) error {
 	for _, permission := range RootPermissions {
-		_, err := grantUserPermission(ctx, qtx, uid, 0, permission.Name)
+		_, err := grantUserPermission(ctx, qtx, uid, 0, permission.Name, Scope{}, time.Time{})
 		if err != nil {
 			return err
 		}
@@ -40,6 +46,7 @@ func userPermissionsByName(ctx context.Context, qtx *query.Queries, name string)
 }
 
 func revokeAllRootUserPermissions(ctx context.Context, qtx *query.Queries) error {
+	requestID, _ := requestid.FromContext(ctx)
 	for _, permission := range RootPermissions {
 		permissions, err := userPermissionsByName(ctx, qtx, permission.Name)
 		if err != nil {
@@ -47,9 +54,11 @@ func revokeAllRootUserPermissions(ctx context.Context, qtx *query.Queries) error
 		}
 		for _, permission := range permissions {
 			if err := qtx.CreateUserPermissionRevocation(ctx, query.CreateUserPermissionRevocationParams{
-				UID:  permission.UID,
-				IUID: 0,
-				Name: permission.Name,
+				UID:       permission.UID,
+				IUID:      0,
+				Name:      permission.Name,
+				Scope:     permission.Scope,
+				RequestID: requestID,
 			}); err != nil {
 				return err
 			}
@@ -61,10 +70,11 @@ func revokeAllRootUserPermissions(ctx context.Context, qtx *query.Queries) error
 	return nil
 }
 
-func revokeUserPermission(ctx context.Context, qtx *query.Queries, uid, iuid int64, name string) (int64, error) {
+func revokeUserPermission(ctx context.Context, qtx *query.Queries, uid, iuid int64, name string, scope Scope) (int64, error) {
 	permission, err := qtx.GetUserPermissionByName(ctx, query.GetUserPermissionByNameParams{
-		UID:  uid,
-		Name: name,
+		UID:   uid,
+		Name:  name,
+		Scope: scope.String(),
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -75,40 +85,79 @@ func revokeUserPermission(ctx context.Context, qtx *query.Queries, uid, iuid int
 	if err := qtx.DeleteUserPermission(ctx, permission.ID); err != nil {
 		return 0, err
 	}
+	requestID, _ := requestid.FromContext(ctx)
 	if err := qtx.CreateUserPermissionRevocation(ctx, query.CreateUserPermissionRevocationParams{
-		UID:  uid,
-		IUID: iuid,
-		Name: name,
+		UID:       uid,
+		IUID:      iuid,
+		Name:      name,
+		Scope:     scope.String(),
+		RequestID: requestID,
 	}); err != nil {
 		return 0, err
 	}
 	return permission.ID, nil
 }
 
-func grantUserPermission(ctx context.Context, qtx *query.Queries, uid, iuid int64, name string) (int64, error) {
+// grantUserPermission grants a permission within scope until expiresAt, or until it's revoked if expiresAt
+// is zero. The zero Scope grants it everywhere. If the user already holds it in that scope until another time,
+// the existing grant is moved to expiresAt and logged as a new grant. If it's until the same time, nothing
+// changes and the returned id is 0.
+func grantUserPermission(ctx context.Context, qtx *query.Queries, uid, iuid int64, name string, scope Scope, expiresAt time.Time) (int64, error) {
+	var expires sql.NullInt64
+	if !expiresAt.IsZero() {
+		expires = sql.NullInt64{Int64: expiresAt.Unix(), Valid: true}
+	}
+
 	perms, err := userPermissions(ctx, qtx, uid)
 	if err != nil {
 		return 0, err
 	}
-	permissions := NewPermissions(uid, perms)
-	if permissions.Has(name) {
-		// TODO: Log this as "attempted to grant user permission they already have"
-		return 0, nil
+	for _, perm := range perms {
+		if perm.Name != name || perm.Scope != scope.String() {
+			continue
+		}
+		if perm.ExpiresAt == expires {
+			// TODO: Log this as "attempted to grant user permission they already have"
+			return 0, nil
+		}
+		if err := qtx.UpdateUserPermissionExpiry(ctx, query.UpdateUserPermissionExpiryParams{
+			ExpiresAt: expires,
+			IUID:      iuid,
+			ID:        perm.ID,
+		}); err != nil {
+			return 0, err
+		}
+		if err := createUserPermissionGrant(ctx, qtx, uid, iuid, name, scope, expires); err != nil {
+			return 0, err
+		}
+		return perm.ID, nil
+	}
+
+	// An expired grant the sweeper hasn't got to yet would collide with the new one.
+	expired, err := qtx.GetUserPermissionByName(ctx, query.GetUserPermissionByNameParams{
+		UID:   uid,
+		Name:  name,
+		Scope: scope.String(),
+	})
+	if err == nil {
+		if err := expireUserPermission(ctx, qtx, expired); err != nil {
+			return 0, err
+		}
+	} else if err != sql.ErrNoRows {
+		return 0, err
 	}
 
 	result, err := qtx.CreateUserPermission(ctx, query.CreateUserPermissionParams{
-		UID:  uid,
-		IUID: iuid,
-		Name: name,
+		UID:       uid,
+		IUID:      iuid,
+		Name:      name,
+		ExpiresAt: expires,
+		Scope:     scope.String(),
 	})
 	if err != nil {
 		return 0, err
 	}
-	if err = qtx.CreateUserPermissionGrant(ctx, query.CreateUserPermissionGrantParams{
-		UID:  uid,
-		IUID: iuid,
-		Name: name,
-	}); err != nil {
+	if err := createUserPermissionGrant(ctx, qtx, uid, iuid, name, scope, expires); err != nil {
 		return 0, err
 	}
 	id, err := result.LastInsertId()
@@ -118,3 +167,15 @@ func grantUserPermission(ctx context.Context, qtx *query.Queries, uid, iuid int6
 
 	return id, nil
 }
+
+func createUserPermissionGrant(ctx context.Context, qtx *query.Queries, uid, iuid int64, name string, scope Scope, expiresAt sql.NullInt64) error {
+	requestID, _ := requestid.FromContext(ctx)
+	return qtx.CreateUserPermissionGrant(ctx, query.CreateUserPermissionGrantParams{
+		UID:       uid,
+		IUID:      iuid,
+		Name:      name,
+		Scope:     scope.String(),
+		ExpiresAt: expiresAt,
+		RequestID: requestID,
+	})
+}
@@ -25,7 +25,7 @@ func TestRevokeAllRootPermissions(t *testing.T) {
 	service, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	uid, err := service.Register(TestRootUsername, "T3sted_tested")
+	uid, err := service.Register(context.Background(), TestRootUsername, "T3sted_tested")
 	require.NoError(t, err)
 	require.NotEqual(t, 0, uid)
 
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	registrations prometheus.Counter
+	logins        *prometheus.CounterVec
+	// lockouts counts suspensions and bans, which lock an account out until they end or are lifted.
+	lockouts       prometheus.Counter
+	grants         *prometheus.CounterVec
+	revocations    *prometheus.CounterVec
+	argon2Duration *prometheus.HistogramVec
+	deletions      *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "user",
+			Name:      "registrations_total",
+			Help:      "Number of users registered.",
+		}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user",
+			Name:      "logins_total",
+			Help:      "Number of login attempts, by result.",
+		}, []string{"result"}),
+		lockouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "user",
+			Name:      "lockouts_total",
+			Help:      "Number of accounts locked out by a suspension or ban.",
+		}),
+		grants: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user",
+			Name:      "permission_grants_total",
+			Help:      "Number of permissions granted, by permission name.",
+		}, []string{"permission"}),
+		revocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user",
+			Name:      "permission_revocations_total",
+			Help:      "Number of permissions revoked, by permission name.",
+		}, []string{"permission"}),
+		argon2Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "user",
+			Name:      "argon2_duration_seconds",
+			Help:      "Time spent hashing and verifying passphrases with argon2, by operation.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		deletions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user",
+			Name:      "account_deletions_total",
+			Help:      "Number of account deletions, by stage: scheduled, cancelled or erased.",
+		}, []string{"stage"}),
+	}
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.registrations,
+		m.logins,
+		m.lockouts,
+		m.grants,
+		m.revocations,
+		m.argon2Duration,
+		m.deletions,
+	}
+}
+
+func (m *metrics) observeArgon2(operation string, start time.Time) {
+	m.argon2Duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
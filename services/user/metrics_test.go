@@ -0,0 +1,78 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestMetrics(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Exec("DELETE FROM user_status_changes;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	reg := prometheus.NewRegistry()
+	ps, err := New(db, WithConfig(config), WithMetrics(reg))
+	require.NoError(t, err)
+
+	iuid, err := ps.Register(context.Background(), TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
+	require.NoError(t, err)
+	require.Equal(t, float64(2), testutil.ToFloat64(ps.metrics.registrations))
+
+	_, err = ps.Authenticate(context.Background(), TestUsername, TestPassword)
+	require.NoError(t, err)
+	_, err = ps.Authenticate(context.Background(), TestUsername, "Wr0ng_passphrase")
+	require.Error(t, err)
+	require.Equal(t, float64(1), testutil.ToFloat64(ps.metrics.logins.WithLabelValues("success")))
+	require.Equal(t, float64(1), testutil.ToFloat64(ps.metrics.logins.WithLabelValues("failure")))
+	require.Equal(t, 2, testutil.CollectAndCount(ps.metrics.argon2Duration))
+
+	name := PermissionViewAllRooms.Name
+	_, err = ps.GrantUserPermission(context.Background(), uid, iuid, name)
+	require.NoError(t, err)
+	_, err = ps.GrantUserPermission(context.Background(), uid, iuid, name)
+	require.NoError(t, err)
+	_, err = ps.RevokeUserPermission(context.Background(), uid, iuid, name)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), testutil.ToFloat64(ps.metrics.grants.WithLabelValues(name)))
+	require.Equal(t, float64(1), testutil.ToFloat64(ps.metrics.revocations.WithLabelValues(name)))
+
+	for _, permission := range []Permission{PermissionSuspendUser, PermissionBanUser, PermissionReinstateUser} {
+		_, err = ps.GrantUserPermission(context.Background(), iuid, iuid, permission.Name)
+		require.NoError(t, err)
+	}
+	_, err = ps.SuspendUser(context.Background(), uid, iuid, "", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = ps.BanUser(context.Background(), uid, iuid, "", time.Time{})
+	require.NoError(t, err)
+	_, err = ps.ReinstateUser(context.Background(), uid, iuid, "")
+	require.NoError(t, err)
+	require.Equal(t, float64(2), testutil.ToFloat64(ps.metrics.lockouts))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	names := []string{}
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	require.Contains(t, names, "user_registrations_total")
+	require.Contains(t, names, "user_argon2_duration_seconds")
+}
@@ -1,6 +1,11 @@
 package user
 
-import "github.com/spf13/viper"
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+)
 
 func WithConfig(config *viper.Viper) func(s *Service) error {
 	return func(s *Service) error {
@@ -8,3 +13,21 @@ func WithConfig(config *viper.Viper) func(s *Service) error {
 		return nil
 	}
 }
+
+func WithLogger(logger *slog.Logger) func(s *Service) error {
+	return func(s *Service) error {
+		s.logger = logger
+		return nil
+	}
+}
+
+func WithMetrics(reg prometheus.Registerer) func(s *Service) error {
+	return func(s *Service) error {
+		for _, c := range s.metrics.collectors() {
+			if err := reg.Register(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -1,6 +1,10 @@
 package user
 
-import "github.com/afteralec/grpc-user/db/query"
+import (
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
 
 type Permission struct {
 	Name     string
@@ -79,6 +83,83 @@ var PermissionRevokeChangelog Permission = Permission{
 	Category: "Changelog",
 }
 
+var PermissionSuspendUser Permission = Permission{
+	Name:     "suspend-user",
+	Title:    "Suspend Users",
+	About:    "Suspend a user's account until a set time.",
+	Category: "Moderation",
+}
+
+var PermissionBanUser Permission = Permission{
+	Name:     "ban-user",
+	Title:    "Ban Users",
+	About:    "Ban a user's account, indefinitely or until a set time.",
+	Category: "Moderation",
+}
+
+var PermissionReinstateUser Permission = Permission{
+	Name:     "reinstate-user",
+	Title:    "Reinstate Users",
+	About:    "Lift a suspension or ban before it ends.",
+	Category: "Moderation",
+}
+
+var PermissionDeleteUser Permission = Permission{
+	Name:     "delete-user",
+	Title:    "Delete Users",
+	About:    "Schedule another user's account for deletion, or cancel it.",
+	Category: "Moderation",
+}
+
+var PermissionChangeUsername Permission = Permission{
+	Name:     "change-username",
+	Title:    "Change Usernames",
+	About:    "Change another user's username, without waiting for the cooldown between changes.",
+	Category: "Moderation",
+}
+
+var PermissionEditUserProfile Permission = Permission{
+	Name:     "edit-user-profile",
+	Title:    "Edit User Profiles",
+	About:    "Edit another user's profile, such as to remove an offensive bio or avatar.",
+	Category: "Moderation",
+}
+
+var PermissionManageUsernameRules Permission = Permission{
+	Name:     "manage-username-rules",
+	Title:    "Manage Username Rules",
+	About:    "View, add and remove reserved usernames and blocked words.",
+	Category: "Moderation",
+}
+
+var PermissionManageThemes Permission = Permission{
+	Name:     "manage-themes",
+	Title:    "Manage Themes",
+	About:    "Add, change and remove the custom themes users can pick from.",
+	Category: "Theme",
+}
+
+var PermissionManageRoles Permission = Permission{
+	Name:     "manage-roles",
+	Title:    "Manage Roles",
+	About:    "Create, change and delete roles. Adding a permission to a role also needs permission to grant it.",
+	Category: "Permissions",
+}
+
+var PermissionManageGroups Permission = Permission{
+	Name:     "manage-groups",
+	Title:    "Manage Groups",
+	About:    "Create, change and delete groups. Adding a permission to a group also needs permission to grant it.",
+	Category: "Permissions",
+}
+
+var PermissionExportUserData Permission = Permission{
+	Name:     "export-user-data",
+	Title:    "Export User Data",
+	About:    "Export everything stored about another user, to answer subject access requests.",
+	Category: "Compliance",
+}
+
 var AllPermissions []Permission = []Permission{
 	PermissionGrantAll,
 	PermissionRevokeAll,
@@ -90,6 +171,17 @@ var AllPermissions []Permission = []Permission{
 	PermissionCreateChangelog,
 	PermissionReleaseChangelog,
 	PermissionRevokeChangelog,
+	PermissionSuspendUser,
+	PermissionBanUser,
+	PermissionReinstateUser,
+	PermissionDeleteUser,
+	PermissionChangeUsername,
+	PermissionEditUserProfile,
+	PermissionManageUsernameRules,
+	PermissionManageThemes,
+	PermissionManageRoles,
+	PermissionManageGroups,
+	PermissionExportUserData,
 }
 
 var RootPermissions []Permission = []Permission{
@@ -129,34 +221,125 @@ var (
 	NonRootPermissionsByName = permissionsByName(NonRootPermissions)
 )
 
+const (
+	PermissionSourceDirect = "direct"
+	PermissionSourceRole   = "role"
+	PermissionSourceGroup  = "group"
+)
+
+// PermissionSource is where a user gets a permission from: a direct grant, a role or a group.
+// ID is the grant, role or group's id, and Name is the role or group's name. ExpiresAt and Scope
+// are only set for direct grants that expire, or that are limited to some resources.
+type PermissionSource struct {
+	Kind      string
+	ID        int64
+	Name      string
+	ExpiresAt time.Time
+	Scope     Scope
+}
+
+// InheritedPermissions are the permissions a user has through a role or group.
+type InheritedPermissions struct {
+	Source PermissionSource
+	Names  []string
+}
+
 type Permissions struct {
-	inner map[string]bool
-	list  []string
-	uid   int64
+	inner   map[string]bool
+	list    []string
+	granted []string
+	scopes  map[string][]Scope
+	sources map[string][]PermissionSource
+	uid     int64
 }
 
-func NewPermissions(uid int64, perms []query.UserPermission) Permissions {
-	filtered := []query.UserPermission{}
-	for _, perm := range perms {
-		if IsValidPermissionName(perm.Name) {
-			filtered = append(filtered, perm)
+// NewPermissions builds a user's effective permissions: their direct grants, plus those they inherit from
+// their roles and groups. Root permissions are only ever granted directly, and never within a scope.
+func NewPermissions(uid int64, perms []query.UserPermission, inherited ...InheritedPermissions) Permissions {
+	list := []string{}
+	granted := []string{}
+	permissionsMap := map[string]bool{}
+	scopes := map[string][]Scope{}
+	sources := map[string][]PermissionSource{}
+	add := func(name string, source PermissionSource) {
+		if !IsValidPermissionName(name) {
+			return
+		}
+		if _, ok := sources[name]; !ok {
+			granted = append(granted, name)
+		}
+		sources[name] = append(sources[name], source)
+		if !source.Scope.IsZero() {
+			scopes[name] = append(scopes[name], source.Scope)
+			return
+		}
+		if !permissionsMap[name] {
+			list = append(list, name)
+			permissionsMap[name] = true
 		}
 	}
-	list := []string{}
-	for _, perm := range filtered {
-		list = append(list, perm.Name)
+	for _, perm := range perms {
+		source := PermissionSource{Kind: PermissionSourceDirect, ID: perm.ID}
+		if perm.ExpiresAt.Valid {
+			source.ExpiresAt = time.Unix(perm.ExpiresAt.Int64, 0)
+		}
+		scope, err := ParseScope(perm.Scope)
+		if err != nil || (!scope.IsZero() && IsRootPermission(perm.Name)) {
+			continue
+		}
+		source.Scope = scope
+		add(perm.Name, source)
 	}
-	permissionsMap := map[string]bool{}
-	for _, perm := range filtered {
-		permissionsMap[perm.Name] = true
+	for _, permissions := range inherited {
+		for _, name := range permissions.Names {
+			if !IsRootPermission(name) {
+				add(name, permissions.Source)
+			}
+		}
 	}
 	return Permissions{
-		uid:   uid,
-		list:  list,
-		inner: permissionsMap,
+		uid:     uid,
+		list:    list,
+		granted: granted,
+		inner:   permissionsMap,
+		scopes:  scopes,
+		sources: sources,
 	}
 }
 
+// List returns the name of every permission held everywhere, in the order they were added.
+func (p *Permissions) List() []string {
+	return p.list
+}
+
+// Granted returns the name of every permission held anywhere, including those only held within a scope,
+// in the order they were added.
+func (p *Permissions) Granted() []string {
+	return p.granted
+}
+
+// Sources returns everywhere the user gets a permission from, direct grants first.
+func (p *Permissions) Sources(name string) []PermissionSource {
+	return p.sources[name]
+}
+
+// Check reports whether uid may use a permission on a resource: either they hold it everywhere, or they
+// hold it within a scope that matches the resource. Permissions built for another user never pass.
+func (p *Permissions) Check(uid int64, name string, resource Resource) bool {
+	if uid != p.uid {
+		return false
+	}
+	if p.Has(name) {
+		return true
+	}
+	for _, scope := range p.scopes[name] {
+		if scope.Matches(resource) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Permissions) Has(name string) bool {
 	_, ok := p.inner[name]
 	return ok
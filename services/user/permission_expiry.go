@@ -0,0 +1,66 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
+)
+
+// ExpireBatchSize is the most grants ExpireUserPermissions revokes in one call.
+const ExpireBatchSize = 100
+
+var ErrInvalidPermissionExpiry = errors.New("a permission can only be granted until a time in the future")
+
+// ExpireUserPermissions revokes up to ExpireBatchSize grants whose expiry has passed, returning how many
+// it revoked. Each is logged as a revocation by the system, with an IUID of 0.
+func (s *Service) ExpireUserPermissions(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ExpireUserPermissions")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	expired, err := qtx.ListExpiredUserPermissions(ctx, query.ListExpiredUserPermissionsParams{
+		Now:   time.Now().Unix(),
+		Limit: ExpireBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, permission := range expired {
+		if err := expireUserPermission(ctx, qtx, permission); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	for _, permission := range expired {
+		s.metrics.revocations.WithLabelValues(permission.Name).Inc()
+		s.logger.InfoContext(ctx, "expired permission", "uid", permission.UID, "name", permission.Name)
+	}
+
+	return len(expired), nil
+}
+
+func expireUserPermission(ctx context.Context, qtx *query.Queries, permission query.UserPermission) error {
+	if err := qtx.DeleteUserPermission(ctx, permission.ID); err != nil {
+		return err
+	}
+	requestID, _ := requestid.FromContext(ctx)
+	return qtx.CreateUserPermissionRevocation(ctx, query.CreateUserPermissionRevocationParams{
+		UID:       permission.UID,
+		IUID:      0,
+		Name:      permission.Name,
+		Scope:     permission.Scope,
+		RequestID: requestID,
+	})
+}
@@ -0,0 +1,143 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+func TestExpireUserPermissions(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+	name := PermissionReviewCharacterApplications.Name
+
+	_, err = ps.GrantUserPermissionUntil(ctx, uid, root, name, time.Now().Add(-time.Minute))
+	require.ErrorIs(t, err, ErrInvalidPermissionExpiry)
+
+	expiresAt := time.Now().Add(7 * 24 * time.Hour)
+	id, err := ps.GrantUserPermissionUntil(ctx, uid, root, name, expiresAt)
+	require.NoError(t, err)
+	require.NotZero(t, id)
+
+	permissions, err := ps.EffectivePermissions(ctx, uid)
+	require.NoError(t, err)
+	require.True(t, permissions.Has(name))
+	require.Equal(t, expiresAt.Unix(), permissions.Sources(name)[0].ExpiresAt.Unix())
+
+	// Nothing is due yet.
+	expired, err := ps.ExpireUserPermissions(ctx)
+	require.NoError(t, err)
+	require.Zero(t, expired)
+
+	_, err = db.Exec("UPDATE user_permissions SET expires_at = ? WHERE id = ?;", time.Now().Add(-time.Second).Unix(), id)
+	require.NoError(t, err)
+
+	t.Run("ExpiredGrantsAreNotHeld", func(t *testing.T) {
+		records, err := ps.UserPermissions(ctx, uid)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("RegrantReplacesExpiredGrant", func(t *testing.T) {
+		regranted, err := ps.GrantUserPermission(ctx, uid, root, name)
+		require.NoError(t, err)
+		require.NotZero(t, regranted)
+
+		records, err := ps.UserPermissions(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.False(t, records[0].ExpiresAt.Valid)
+
+		revocations, err := ps.query.ListUserPermissionRevocations(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, revocations, 1)
+		require.Equal(t, int64(0), revocations[0].IUID)
+
+		_, err = ps.RevokeUserPermission(ctx, uid, root, name)
+		require.NoError(t, err)
+	})
+
+	t.Run("Sweep", func(t *testing.T) {
+		id, err := ps.GrantUserPermissionUntil(ctx, uid, root, name, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE user_permissions SET expires_at = ? WHERE id = ?;", time.Now().Add(-time.Second).Unix(), id)
+		require.NoError(t, err)
+
+		expired, err := ps.ExpireUserPermissions(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, expired)
+
+		_, err = ps.query.GetUserPermissionByName(ctx, query.GetUserPermissionByNameParams{UID: uid, Name: name})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		revocations, err := ps.query.ListUserPermissionRevocations(ctx, uid)
+		require.NoError(t, err)
+		last := revocations[len(revocations)-1]
+		require.Equal(t, name, last.Name)
+		require.Equal(t, uid, last.UID)
+		require.Equal(t, int64(0), last.IUID)
+
+		// Root permissions never expire.
+		rootPermissions, err := ps.EffectivePermissions(ctx, root)
+		require.NoError(t, err)
+		require.True(t, rootPermissions.Has(PermissionGrantAll.Name))
+	})
+
+	t.Run("RegrantChangesExpiry", func(t *testing.T) {
+		id, err := ps.GrantUserPermissionUntil(ctx, uid, root, name, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		grants, err := ps.query.ListUserPermissionGrants(ctx, uid)
+		require.NoError(t, err)
+
+		later := time.Now().Add(2 * time.Hour)
+		extended, err := ps.GrantUserPermissionUntil(ctx, uid, root, name, later)
+		require.NoError(t, err)
+		require.Equal(t, id, extended)
+		records, err := ps.UserPermissions(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, later.Unix(), records[0].ExpiresAt.Int64)
+
+		permanent, err := ps.GrantUserPermission(ctx, uid, root, name)
+		require.NoError(t, err)
+		require.Equal(t, id, permanent)
+		records, err = ps.UserPermissions(ctx, uid)
+		require.NoError(t, err)
+		require.False(t, records[0].ExpiresAt.Valid)
+
+		// Each change is logged as a grant, but granting it until the same time again changes nothing.
+		unchanged, err := ps.GrantUserPermission(ctx, uid, root, name)
+		require.NoError(t, err)
+		require.Zero(t, unchanged)
+		regrants, err := ps.query.ListUserPermissionGrants(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, regrants, len(grants)+2)
+		require.Equal(t, later.Unix(), regrants[len(grants)].ExpiresAt.Int64)
+		require.False(t, regrants[len(grants)+1].ExpiresAt.Valid)
+	})
+}
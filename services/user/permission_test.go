@@ -78,3 +78,73 @@ func TestCanRevokeFalseForRootPermissions(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPermissionsIncludesRoles(t *testing.T) {
+	uid := int64(1)
+	records := []query.UserPermission{
+		{UID: uid, Name: PermissionCreateChangelog.Name},
+	}
+	roles := []Role{
+		{Name: "Changelog Editor", Permissions: []string{PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name}},
+		{Name: "Sneaky", Permissions: []string{PermissionGrantAll.Name, "not-a-permission"}},
+	}
+	permissions := NewPermissions(uid, records, roles[0].Inherited(), roles[1].Inherited())
+
+	require.True(t, permissions.Has(PermissionCreateChangelog.Name))
+	require.True(t, permissions.Has(PermissionReleaseChangelog.Name))
+	require.False(t, permissions.Has(PermissionRevokeChangelog.Name))
+	require.False(t, permissions.Has(PermissionGrantAll.Name))
+	require.False(t, permissions.Has("not-a-permission"))
+	require.Len(t, permissions.list, 2)
+}
+
+func TestNewPermissionsTracksSources(t *testing.T) {
+	uid := int64(1)
+	records := []query.UserPermission{
+		{ID: 7, UID: uid, Name: PermissionCreateChangelog.Name},
+	}
+	role := Role{ID: 2, Name: "Changelog Editor", Permissions: []string{PermissionCreateChangelog.Name}}
+	group := Group{ID: 3, Name: "Room Builders", Permissions: []string{PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name}}
+	permissions := NewPermissions(uid, records, role.Inherited(), group.Inherited())
+
+	require.Equal(t, []string{PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name}, permissions.List())
+	require.Equal(t, []PermissionSource{
+		{Kind: PermissionSourceDirect, ID: 7},
+		{Kind: PermissionSourceRole, ID: 2, Name: "Changelog Editor"},
+		{Kind: PermissionSourceGroup, ID: 3, Name: "Room Builders"},
+	}, permissions.Sources(PermissionCreateChangelog.Name))
+	require.Equal(t, []PermissionSource{
+		{Kind: PermissionSourceGroup, ID: 3, Name: "Room Builders"},
+	}, permissions.Sources(PermissionReleaseChangelog.Name))
+	require.Empty(t, permissions.Sources(PermissionRevokeChangelog.Name))
+}
+
+func TestPermissionsCheck(t *testing.T) {
+	uid := int64(1)
+	records := []query.UserPermission{
+		{ID: 1, UID: uid, Name: PermissionCreateChangelog.Name},
+		{ID: 2, UID: uid, Name: PermissionReviewCharacterApplications.Name, Scope: "area:north-*"},
+		{ID: 3, UID: uid, Name: PermissionGrantAll.Name, Scope: "room:42"},
+		{ID: 4, UID: uid, Name: PermissionReleaseChangelog.Name, Scope: "not a scope"},
+	}
+	permissions := NewPermissions(uid, records)
+
+	room := Resource{Type: "room", ID: "42"}
+	require.True(t, permissions.Check(uid, PermissionCreateChangelog.Name, room))
+	require.False(t, permissions.Check(uid+1, PermissionCreateChangelog.Name, room))
+
+	// Scoped permissions only pass for matching resources, and never count as held everywhere.
+	name := PermissionReviewCharacterApplications.Name
+	require.True(t, permissions.Check(uid, name, Resource{Type: "area", ID: "north-gate"}))
+	require.False(t, permissions.Check(uid, name, Resource{Type: "area", ID: "south-gate"}))
+	require.False(t, permissions.Check(uid, name, Resource{}))
+	require.False(t, permissions.Has(name))
+	require.Equal(t, []string{PermissionCreateChangelog.Name}, permissions.List())
+	require.Equal(t, []string{PermissionCreateChangelog.Name, name}, permissions.Granted())
+	require.Equal(t, Scope{Type: "area", Pattern: "north-*"}, permissions.Sources(name)[0].Scope)
+
+	// Root permissions can't be scoped, and grants with malformed scopes are ignored.
+	require.False(t, permissions.Check(uid, PermissionGrantAll.Name, room))
+	require.False(t, permissions.Has(PermissionReleaseChangelog.Name))
+	require.Empty(t, permissions.Sources(PermissionReleaseChangelog.Name))
+}
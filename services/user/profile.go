@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+var ErrUserNotFound = errors.New("no user exists with this id or username")
+
+// Profile is everything there is to show about a single user.
+type Profile struct {
+	User     query.User
+	Settings Settings
+	// PrimaryEmail is the user's oldest verified email, or their oldest email if none are verified.
+	PrimaryEmail *query.Email
+	Permissions  []string
+	Fields       ProfileFields
+}
+
+func (s *Service) Profile(ctx context.Context, uid int64) (*Profile, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Profile")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	u, err := qtx.GetUser(ctx, uid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	profile, err := userProfile(ctx, qtx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *Service) ProfileByUsername(ctx context.Context, username string) (*Profile, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ProfileByUsername")
+	defer span.End()
+
+	username, err := s.normalizeUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	u, err := qtx.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	profile, err := userProfile(ctx, qtx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func userProfile(ctx context.Context, qtx *query.Queries, u query.User) (*Profile, error) {
+	settings, err := userSettings(ctx, qtx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryEmail *query.Email
+	email, err := qtx.GetPrimaryEmail(ctx, u.ID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+	} else {
+		primaryEmail = &email
+	}
+
+	// Only permissions held everywhere are listed, including those from roles and groups.
+	permissions, err := effectivePermissions(ctx, qtx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := profileFields(ctx, qtx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{
+		User:         u,
+		Settings:     settings,
+		PrimaryEmail: primaryEmail,
+		Permissions:  permissions.List(),
+		Fields:       *fields,
+	}, nil
+}
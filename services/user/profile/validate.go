@@ -0,0 +1,60 @@
+package profile
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	MaxDisplayNameLength = 64
+	MaxBioLength         = 1024
+	MaxPronounsLength    = 32
+	MaxAvatarURLLength   = 2048
+)
+
+var validate *validator.Validate
+
+// ValidateSingleLine rejects control characters, including line breaks.
+func ValidateSingleLine(fl validator.FieldLevel) bool {
+	for _, r := range fl.Field().String() {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateMultiLine rejects control characters other than line breaks.
+func ValidateMultiLine(fl validator.FieldLevel) bool {
+	for _, r := range fl.Field().String() {
+		if unicode.IsControl(r) && r != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	validate = validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterValidation("singleline", ValidateSingleLine)
+	validate.RegisterValidation("multiline", ValidateMultiLine)
+}
+
+func IsValidDisplayName(name string) error {
+	return validate.Var(name, fmt.Sprintf("max=%d,singleline", MaxDisplayNameLength))
+}
+
+func IsValidBio(bio string) error {
+	return validate.Var(bio, fmt.Sprintf("max=%d,multiline", MaxBioLength))
+}
+
+func IsValidPronouns(pronouns string) error {
+	return validate.Var(pronouns, fmt.Sprintf("max=%d,singleline", MaxPronounsLength))
+}
+
+// IsValidAvatarURL allows an empty URL, for no avatar, or an https URL.
+func IsValidAvatarURL(url string) error {
+	return validate.Var(url, fmt.Sprintf("omitempty,max=%d,url,startswith=https://", MaxAvatarURLLength))
+}
@@ -0,0 +1,42 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValid(t *testing.T) {
+	type testCase struct {
+		name     string
+		validate func(string) error
+		input    string
+		want     bool
+	}
+	testCases := []testCase{
+		{"display name", IsValidDisplayName, "Tested Testerson", true},
+		{"empty display name", IsValidDisplayName, "", true},
+		{"unicode display name", IsValidDisplayName, "Zoë 🌱", true},
+		{"long display name", IsValidDisplayName, strings.Repeat("é", MaxDisplayNameLength), true},
+		{"too long display name", IsValidDisplayName, strings.Repeat("a", MaxDisplayNameLength+1), false},
+		{"multiline display name", IsValidDisplayName, "Tested\nTesterson", false},
+		{"bio", IsValidBio, "Likes tests.\nWrites more.", true},
+		{"too long bio", IsValidBio, strings.Repeat("a", MaxBioLength+1), false},
+		{"control character bio", IsValidBio, "bell\a", false},
+		{"pronouns", IsValidPronouns, "they/them", true},
+		{"too long pronouns", IsValidPronouns, strings.Repeat("a", MaxPronounsLength+1), false},
+		{"avatar url", IsValidAvatarURL, "https://web.site/avatar.png", true},
+		{"empty avatar url", IsValidAvatarURL, "", true},
+		{"insecure avatar url", IsValidAvatarURL, "http://web.site/avatar.png", false},
+		{"not an avatar url", IsValidAvatarURL, "avatar.png", false},
+		{"too long avatar url", IsValidAvatarURL, "https://web.site/" + strings.Repeat("a", MaxAvatarURLLength), false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, tc.validate(tc.input) == nil)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/services/user/profile"
+)
+
+const (
+	ProfileFieldDisplayName = "display_name"
+	ProfileFieldBio         = "bio"
+	ProfileFieldPronouns    = "pronouns"
+	ProfileFieldAvatarURL   = "avatar_url"
+)
+
+var ProfileFieldNames = []string{ProfileFieldDisplayName, ProfileFieldBio, ProfileFieldPronouns, ProfileFieldAvatarURL}
+
+var (
+	ErrInvalidProfileField = errors.New("the update mask names a field that isn't in the profile")
+	ErrCannotEditProfile   = errors.New("this issuer cannot edit this user's profile")
+)
+
+// InvalidProfileFieldError is returned by UpdateProfileFields when a field's new value isn't valid.
+type InvalidProfileFieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *InvalidProfileFieldError) Error() string {
+	return "the " + e.Field + " provided isn't valid"
+}
+
+func (e *InvalidProfileFieldError) Unwrap() error {
+	return e.Err
+}
+
+// ProfileFields are the parts of a profile users fill in themselves. They're all empty until they do.
+type ProfileFields struct {
+	DisplayName string
+	Bio         string
+	Pronouns    string
+	AvatarURL   string
+	// UpdatedAt is zero until the profile is first updated.
+	UpdatedAt time.Time
+}
+
+var profileFieldValidators = map[string]func(string) error{
+	ProfileFieldDisplayName: profile.IsValidDisplayName,
+	ProfileFieldBio:         profile.IsValidBio,
+	ProfileFieldPronouns:    profile.IsValidPronouns,
+	ProfileFieldAvatarURL:   profile.IsValidAvatarURL,
+}
+
+func (f *ProfileFields) field(name string) *string {
+	switch name {
+	case ProfileFieldDisplayName:
+		return &f.DisplayName
+	case ProfileFieldBio:
+		return &f.Bio
+	case ProfileFieldPronouns:
+		return &f.Pronouns
+	case ProfileFieldAvatarURL:
+		return &f.AvatarURL
+	}
+	return nil
+}
+
+func (s *Service) ProfileFields(ctx context.Context, uid int64) (*ProfileFields, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ProfileFields")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	fields, err := profileFields(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// UpdateProfileFields sets the fields named in paths to their values in fields, leaving the rest as they are.
+// With no paths, it sets the fields that aren't empty; the path "*" sets every field.
+// Users can edit their own profile; editing anyone else's needs the edit-user-profile permission.
+func (s *Service) UpdateProfileFields(ctx context.Context, uid, iuid int64, fields ProfileFields, paths []string) (*ProfileFields, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UpdateProfileFields")
+	defer span.End()
+
+	paths, err := profileFieldPaths(&fields, paths)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if err := profileFieldValidators[path](*fields.field(path)); err != nil {
+			return nil, &InvalidProfileFieldError{Field: path, Err: err}
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	ok, err := selfOrPermitted(ctx, qtx, uid, iuid, PermissionEditUserProfile)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCannotEditProfile
+	}
+
+	current, err := profileFields(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		*current.field(path) = *fields.field(path)
+	}
+	if err := qtx.SetUserProfile(ctx, query.SetUserProfileParams{
+		DisplayName: current.DisplayName,
+		Bio:         current.Bio,
+		Pronouns:    current.Pronouns,
+		AvatarURL:   current.AvatarURL,
+		UID:         uid,
+	}); err != nil {
+		return nil, err
+	}
+	updated, err := profileFields(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// profileFieldPaths checks the paths from an update mask, filling them in when there aren't any.
+func profileFieldPaths(fields *ProfileFields, paths []string) ([]string, error) {
+	if len(paths) == 1 && paths[0] == "*" {
+		return ProfileFieldNames, nil
+	}
+	if len(paths) == 0 {
+		for _, name := range ProfileFieldNames {
+			if *fields.field(name) != "" {
+				paths = append(paths, name)
+			}
+		}
+		return paths, nil
+	}
+	for _, path := range paths {
+		if fields.field(path) == nil {
+			return nil, ErrInvalidProfileField
+		}
+	}
+	return paths, nil
+}
+
+func profileFields(ctx context.Context, qtx *query.Queries, uid int64) (*ProfileFields, error) {
+	row, err := qtx.GetUserProfile(ctx, uid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &ProfileFields{}, nil
+		}
+		return nil, err
+	}
+	fields := &ProfileFields{
+		DisplayName: row.DisplayName,
+		Bio:         row.Bio,
+		Pronouns:    row.Pronouns,
+		AvatarURL:   row.AvatarURL,
+	}
+	if row.UpdatedAt.Valid {
+		fields.UpdatedAt = time.Unix(row.UpdatedAt.Int64, 0)
+	}
+	return fields, nil
+}
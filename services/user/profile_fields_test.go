@@ -0,0 +1,102 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+func TestUpdateProfileFields(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	staff, err := ps.Register(ctx, "teststaff", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	t.Run("Empty", func(t *testing.T) {
+		fields, err := ps.ProfileFields(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, &ProfileFields{}, fields)
+		_, err = ps.ProfileFields(ctx, uid+100)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.UpdateProfileFields(ctx, uid, staff, ProfileFields{Bio: "Hi"}, nil)
+		require.ErrorIs(t, err, ErrCannotEditProfile)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := ps.UpdateProfileFields(ctx, uid, uid, ProfileFields{AvatarURL: "http://web.site/a.png"}, nil)
+		var fieldErr *InvalidProfileFieldError
+		require.True(t, errors.As(err, &fieldErr))
+		require.Equal(t, ProfileFieldAvatarURL, fieldErr.Field)
+		_, err = ps.UpdateProfileFields(ctx, uid, uid, ProfileFields{}, []string{"username"})
+		require.ErrorIs(t, err, ErrInvalidProfileField)
+	})
+
+	t.Run("Mask", func(t *testing.T) {
+		fields, err := ps.UpdateProfileFields(ctx, uid, uid, ProfileFields{DisplayName: "Testify", Bio: "Asserts things."}, nil)
+		require.NoError(t, err)
+		require.Equal(t, "Testify", fields.DisplayName)
+		require.Equal(t, "Asserts things.", fields.Bio)
+		require.False(t, fields.UpdatedAt.IsZero())
+
+		fields, err = ps.UpdateProfileFields(ctx, uid, uid, ProfileFields{DisplayName: "Ignored", Pronouns: "it/its"}, []string{ProfileFieldPronouns})
+		require.NoError(t, err)
+		require.Equal(t, "Testify", fields.DisplayName)
+		require.Equal(t, "it/its", fields.Pronouns)
+
+		fields, err = ps.UpdateProfileFields(ctx, uid, uid, ProfileFields{}, []string{ProfileFieldBio})
+		require.NoError(t, err)
+		require.Empty(t, fields.Bio)
+		require.Equal(t, "it/its", fields.Pronouns)
+
+		_, err = ps.GrantUserPermission(ctx, staff, root, PermissionEditUserProfile.Name)
+		require.NoError(t, err)
+		fields, err = ps.UpdateProfileFields(ctx, uid, staff, ProfileFields{DisplayName: "Moderated"}, []string{"*"})
+		require.NoError(t, err)
+		require.Equal(t, &ProfileFields{DisplayName: "Moderated", UpdatedAt: fields.UpdatedAt}, fields)
+
+		profile, err := ps.Profile(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, "Moderated", profile.Fields.DisplayName)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		page, err := ps.SearchUsers(ctx, SearchUsersParams{Query: "moderated"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{uid}, userIDs(page.Users))
+	})
+}
+
+func userIDs(users []query.User) []int64 {
+	ids := []int64{}
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
@@ -0,0 +1,305 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
+)
+
+const (
+	RoleChangeAssign   = "assign"
+	RoleChangeUnassign = "unassign"
+)
+
+var (
+	ErrInvalidRole        = errors.New("a role needs a name of up to 64 characters, an about of up to 256, and at least one permission that isn't a root permission")
+	ErrRoleExists         = errors.New("a role with this name already exists")
+	ErrRoleNotFound       = errors.New("no role exists with this id")
+	ErrCannotManageRoles  = errors.New("this issuer cannot manage roles")
+	ErrCannotAssignRole   = errors.New("this issuer cannot grant every permission in this role")
+	ErrCannotUnassignRole = errors.New("this issuer cannot revoke every permission in this role")
+)
+
+// Role is a named set of permissions, like "Changelog Editor". Users assigned a role have its permissions
+// for as long as they have the role, on top of any granted to them directly.
+type Role struct {
+	ID          int64
+	Name        string
+	About       string
+	Permissions []string
+	IUID        int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+var roleBundles = bundleKind{
+	manage:          PermissionManageRoles,
+	leave:           RoleChangeUnassign,
+	errInvalid:      ErrInvalidRole,
+	errExists:       ErrRoleExists,
+	errNotFound:     ErrRoleNotFound,
+	errCannotManage: ErrCannotManageRoles,
+	errCannotJoin:   ErrCannotAssignRole,
+	errCannotLeave:  ErrCannotUnassignRole,
+	queries:         roleQueries,
+}
+
+// Inherited returns the permissions a user gets from having the role.
+func (r Role) Inherited() InheritedPermissions {
+	return InheritedPermissions{
+		Source: PermissionSource{Kind: PermissionSourceRole, ID: r.ID, Name: r.Name},
+		Names:  r.Permissions,
+	}
+}
+
+// Roles lists every role, sorted by name.
+func (s *Service) Roles(ctx context.Context) ([]Role, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Roles")
+	defer span.End()
+
+	bundles, err := s.listBundles(ctx, roleBundles)
+	if err != nil {
+		return nil, err
+	}
+	return rolesFromBundles(bundles), nil
+}
+
+// CreateRole needs the manage-roles permission, and permission to grant everything in the role.
+func (s *Service) CreateRole(ctx context.Context, iuid int64, name, about string, permissions []string) (*Role, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.CreateRole")
+	defer span.End()
+
+	bundle, err := s.createBundle(ctx, roleBundles, iuid, name, about, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return (*Role)(bundle), nil
+}
+
+// UpdateRole replaces a role's name, about and permissions. Besides manage-roles, the issuer needs permission
+// to grant the permissions it adds and to revoke those it removes, since the role's users gain or lose them.
+func (s *Service) UpdateRole(ctx context.Context, iuid, id int64, name, about string, permissions []string) (*Role, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UpdateRole")
+	defer span.End()
+
+	bundle, err := s.updateBundle(ctx, roleBundles, iuid, id, name, about, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return (*Role)(bundle), nil
+}
+
+// DeleteRole unassigns the role from everyone who has it, so the issuer needs permission to revoke
+// everything in it as well as manage-roles.
+func (s *Service) DeleteRole(ctx context.Context, iuid, id int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.DeleteRole")
+	defer span.End()
+
+	return s.deleteBundle(ctx, roleBundles, iuid, id)
+}
+
+// AssignRole gives a user a role. The issuer needs permission to grant every permission in it.
+// Assigning a role the user already has does nothing.
+func (s *Service) AssignRole(ctx context.Context, uid, iuid, roleID int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.AssignRole")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	role, err := changeMembershipPermitted(ctx, qtx, roleBundles, uid, iuid, roleID, (*Permissions).CanGrant, ErrCannotAssignRole)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetUserRole(ctx, query.GetUserRoleParams{UID: uid, RoleID: roleID}); err == nil {
+		return tx.Commit()
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+	if err := qtx.CreateUserRole(ctx, query.CreateUserRoleParams{
+		RoleID: roleID,
+		UID:    uid,
+		IUID:   iuid,
+	}); err != nil {
+		return err
+	}
+	if err := createUserRoleChange(ctx, qtx, RoleChangeAssign, role, uid, iuid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UnassignRole takes a role away from a user. The issuer needs permission to revoke every permission in it.
+// Unassigning a role the user doesn't have does nothing.
+func (s *Service) UnassignRole(ctx context.Context, uid, iuid, roleID int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.UnassignRole")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	role, err := changeMembershipPermitted(ctx, qtx, roleBundles, uid, iuid, roleID, (*Permissions).CanRevoke, ErrCannotUnassignRole)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetUserRole(ctx, query.GetUserRoleParams{UID: uid, RoleID: roleID}); err != nil {
+		if err == sql.ErrNoRows {
+			return tx.Commit()
+		}
+		return err
+	}
+	if err := qtx.DeleteUserRole(ctx, query.DeleteUserRoleParams{UID: uid, RoleID: roleID}); err != nil {
+		return err
+	}
+	if err := createUserRoleChange(ctx, qtx, RoleChangeUnassign, role, uid, iuid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UserRoles lists the roles assigned to a user, sorted by name.
+func (s *Service) UserRoles(ctx context.Context, uid int64) ([]Role, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UserRoles")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	roles, err := userRoles(ctx, qtx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func userRoles(ctx context.Context, qtx *query.Queries, uid int64) ([]Role, error) {
+	bundles, err := userBundles(ctx, roleQueries(qtx), uid)
+	if err != nil {
+		return nil, err
+	}
+	return rolesFromBundles(bundles), nil
+}
+
+func rolesFromBundles(bundles []permissionBundle) []Role {
+	roles := make([]Role, 0, len(bundles))
+	for _, bundle := range bundles {
+		roles = append(roles, Role(bundle))
+	}
+	return roles
+}
+
+func createUserRoleChange(ctx context.Context, qtx *query.Queries, action string, role *permissionBundle, uid, iuid int64) error {
+	requestID, _ := requestid.FromContext(ctx)
+	return qtx.CreateUserRoleChange(ctx, query.CreateUserRoleChangeParams{
+		Action:    action,
+		RoleID:    role.ID,
+		RoleName:  role.Name,
+		RequestID: requestID,
+		IUID:      iuid,
+		UID:       uid,
+	})
+}
+
+func roleBundle(row query.Role) permissionBundle {
+	return newPermissionBundle(row.ID, row.Name, row.About, row.IUID, row.CreatedAt, row.UpdatedAt)
+}
+
+func roleBundleList(rows []query.Role) []permissionBundle {
+	bundles := make([]permissionBundle, 0, len(rows))
+	for _, row := range rows {
+		bundles = append(bundles, roleBundle(row))
+	}
+	return bundles
+}
+
+func roleQueries(qtx *query.Queries) bundleQueries {
+	return bundleQueries{
+		get: func(ctx context.Context, id int64) (permissionBundle, error) {
+			row, err := qtx.GetRole(ctx, id)
+			return roleBundle(row), err
+		},
+		getByName: func(ctx context.Context, name string) (permissionBundle, error) {
+			row, err := qtx.GetRoleByName(ctx, name)
+			return roleBundle(row), err
+		},
+		list: func(ctx context.Context) ([]permissionBundle, error) {
+			rows, err := qtx.ListRoles(ctx)
+			return roleBundleList(rows), err
+		},
+		listByUser: func(ctx context.Context, uid int64) ([]permissionBundle, error) {
+			rows, err := qtx.ListUserRoles(ctx, uid)
+			return roleBundleList(rows), err
+		},
+		create: func(ctx context.Context, name, about string, iuid int64) (int64, error) {
+			result, err := qtx.CreateRole(ctx, query.CreateRoleParams{Name: name, About: about, IUID: iuid})
+			if err != nil {
+				return 0, err
+			}
+			return result.LastInsertId()
+		},
+		update: func(ctx context.Context, id int64, name, about string, iuid int64) error {
+			return qtx.UpdateRole(ctx, query.UpdateRoleParams{Name: name, About: about, IUID: iuid, ID: id})
+		},
+		delete: qtx.DeleteRole,
+		listPermissions: func(ctx context.Context, id int64) ([]string, error) {
+			rows, err := qtx.ListRolePermissions(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(rows))
+			for _, row := range rows {
+				names = append(names, row.Name)
+			}
+			return names, nil
+		},
+		createPermission: func(ctx context.Context, id int64, name string) error {
+			return qtx.CreateRolePermission(ctx, query.CreateRolePermissionParams{Name: name, RoleID: id})
+		},
+		deletePermissions: qtx.DeleteRolePermissions,
+		listMembers: func(ctx context.Context, id int64) ([]int64, error) {
+			rows, err := qtx.ListUserRolesByRoleID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			uids := make([]int64, 0, len(rows))
+			for _, row := range rows {
+				uids = append(uids, row.UID)
+			}
+			return uids, nil
+		},
+		createChange: func(ctx context.Context, action string, role *permissionBundle, uid, iuid int64) error {
+			return createUserRoleChange(ctx, qtx, action, role, uid, iuid)
+		},
+	}
+}
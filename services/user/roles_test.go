@@ -0,0 +1,136 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestRoles(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM roles;")
+		db.Exec("DELETE FROM user_role_changes;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	staff, err := ps.Register(ctx, "teststaff", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	changelog := []string{PermissionRevokeChangelog.Name, PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name}
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.CreateRole(ctx, staff, "Changelog Editor", "", changelog)
+		require.ErrorIs(t, err, ErrCannotManageRoles)
+
+		// manage-roles alone isn't enough to put permissions in a role.
+		_, err = ps.GrantUserPermission(ctx, staff, root, PermissionManageRoles.Name)
+		require.NoError(t, err)
+		_, err = ps.CreateRole(ctx, staff, "Changelog Editor", "", changelog)
+		require.ErrorIs(t, err, ErrCannotAssignRole)
+	})
+
+	_, err = ps.GrantUserPermission(ctx, root, root, PermissionManageRoles.Name)
+	require.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := ps.CreateRole(ctx, root, "", "", changelog)
+		require.ErrorIs(t, err, ErrInvalidRole)
+		_, err = ps.CreateRole(ctx, root, "Empty", "", nil)
+		require.ErrorIs(t, err, ErrInvalidRole)
+		_, err = ps.CreateRole(ctx, root, "Root", "", []string{PermissionGrantAll.Name})
+		require.ErrorIs(t, err, ErrInvalidRole)
+		_, err = ps.CreateRole(ctx, root, "Made Up", "", []string{"not-a-permission"})
+		require.ErrorIs(t, err, ErrInvalidRole)
+	})
+
+	role, err := ps.CreateRole(ctx, root, "Changelog Editor", "Writes and publishes changelogs.", changelog)
+	require.NoError(t, err)
+	require.Equal(t, []string{PermissionCreateChangelog.Name, PermissionReleaseChangelog.Name, PermissionRevokeChangelog.Name}, role.Permissions)
+	_, err = ps.CreateRole(ctx, root, "Changelog Editor", "", changelog)
+	require.ErrorIs(t, err, ErrRoleExists)
+
+	t.Run("Assign", func(t *testing.T) {
+		require.ErrorIs(t, ps.AssignRole(ctx, uid, staff, role.ID), ErrCannotAssignRole)
+		require.ErrorIs(t, ps.AssignRole(ctx, uid, root, role.ID+100), ErrRoleNotFound)
+		require.ErrorIs(t, ps.AssignRole(ctx, uid+100, root, role.ID), ErrUserNotFound)
+
+		require.NoError(t, ps.AssignRole(ctx, uid, root, role.ID))
+		require.NoError(t, ps.AssignRole(ctx, uid, root, role.ID))
+
+		roles, err := ps.UserRoles(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, role.ID, roles[0].ID)
+
+		permissions, err := effectivePermissions(ctx, ps.query, uid)
+		require.NoError(t, err)
+		require.True(t, permissions.HasAllPermissionsInSet(changelog))
+
+		// Direct grants are kept apart from those that come from roles.
+		records, err := ps.UserPermissions(ctx, uid)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated, err := ps.UpdateRole(ctx, root, role.ID, "Changelog Writer", "", []string{PermissionCreateChangelog.Name})
+		require.NoError(t, err)
+		require.Equal(t, "Changelog Writer", updated.Name)
+
+		permissions, err := effectivePermissions(ctx, ps.query, uid)
+		require.NoError(t, err)
+		require.True(t, permissions.Has(PermissionCreateChangelog.Name))
+		require.False(t, permissions.Has(PermissionReleaseChangelog.Name))
+	})
+
+	t.Run("Unassign", func(t *testing.T) {
+		require.ErrorIs(t, ps.UnassignRole(ctx, uid, staff, role.ID), ErrCannotUnassignRole)
+		require.NoError(t, ps.UnassignRole(ctx, uid, root, role.ID))
+		require.NoError(t, ps.UnassignRole(ctx, uid, root, role.ID))
+
+		permissions, err := effectivePermissions(ctx, ps.query, uid)
+		require.NoError(t, err)
+		require.False(t, permissions.Has(PermissionCreateChangelog.Name))
+
+		changes, err := ps.query.ListUserRoleChanges(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, RoleChangeAssign, changes[0].Action)
+		require.Equal(t, RoleChangeUnassign, changes[1].Action)
+		require.Equal(t, "Changelog Writer", changes[1].RoleName)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, ps.AssignRole(ctx, uid, root, role.ID))
+		require.ErrorIs(t, ps.DeleteRole(ctx, staff, role.ID), ErrCannotUnassignRole)
+		require.NoError(t, ps.DeleteRole(ctx, root, role.ID))
+		require.ErrorIs(t, ps.DeleteRole(ctx, root, role.ID), ErrRoleNotFound)
+
+		roles, err := ps.UserRoles(ctx, uid)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+		changes, err := ps.query.ListUserRoleChanges(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, RoleChangeUnassign, changes[len(changes)-1].Action)
+	})
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidScope = errors.New("a scope is a resource type, a colon, and an id or a pattern using * like room:42 or area:north-*")
+
+var (
+	resourceTypePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+	scopePatternPattern = regexp.MustCompile(`^[A-Za-z0-9_.*-]{1,64}$`)
+)
+
+// Resource is something a permission can be limited to, like room 42.
+type Resource struct {
+	Type string
+	ID   string
+}
+
+// Scope limits a permission to resources of one type whose id matches Pattern, where * matches any run
+// of characters. The zero Scope isn't limited to anything, so the permission holds everywhere.
+type Scope struct {
+	Type    string
+	Pattern string
+}
+
+// ParseScope parses a scope like room:42 or area:north-*. An empty string is the zero Scope.
+func ParseScope(s string) (Scope, error) {
+	if s == "" {
+		return Scope{}, nil
+	}
+	kind, pattern, ok := strings.Cut(s, ":")
+	if !ok || !resourceTypePattern.MatchString(kind) || !scopePatternPattern.MatchString(pattern) {
+		return Scope{}, ErrInvalidScope
+	}
+	return Scope{Type: kind, Pattern: pattern}, nil
+}
+
+func (s Scope) IsZero() bool {
+	return s == Scope{}
+}
+
+// String formats the scope the way ParseScope reads it.
+func (s Scope) String() string {
+	if s.IsZero() {
+		return ""
+	}
+	return s.Type + ":" + s.Pattern
+}
+
+func (s Scope) valid() bool {
+	parsed, err := ParseScope(s.String())
+	return err == nil && parsed == s
+}
+
+// Matches reports whether r is within the scope. Everything is within the zero Scope.
+func (s Scope) Matches(r Resource) bool {
+	if s.IsZero() {
+		return true
+	}
+	return s.Type == r.Type && matchWildcard(s.Pattern, r.ID)
+}
+
+// matchWildcard matches id against a pattern where * matches any run of characters, including none.
+func matchWildcard(pattern, id string) bool {
+	// The last * seen, and the position in id it's currently matched up to.
+	star, starID := -1, 0
+	p, i := 0, 0
+	for i < len(id) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, starID = p, i
+			p++
+		case p < len(pattern) && pattern[p] == id[i]:
+			p++
+			i++
+		case star >= 0:
+			// Let the last * swallow one more character and try again from there.
+			starID++
+			p, i = star+1, starID
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
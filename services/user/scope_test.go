@@ -0,0 +1,157 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestParseScope(t *testing.T) {
+	scope, err := ParseScope("")
+	require.NoError(t, err)
+	require.True(t, scope.IsZero())
+
+	scope, err = ParseScope("room:42")
+	require.NoError(t, err)
+	require.Equal(t, Scope{Type: "room", Pattern: "42"}, scope)
+	require.Equal(t, "room:42", scope.String())
+
+	scope, err = ParseScope("area:north-*")
+	require.NoError(t, err)
+	require.Equal(t, Scope{Type: "area", Pattern: "north-*"}, scope)
+
+	for _, invalid := range []string{"room", "room:", ":42", "Room:42", "room:4 2", "room:42:1", "room:a/b"} {
+		_, err := ParseScope(invalid)
+		require.ErrorIs(t, err, ErrInvalidScope, invalid)
+	}
+}
+
+func TestScopeMatches(t *testing.T) {
+	cases := []struct {
+		pattern string
+		id      string
+		matches bool
+	}{
+		{"42", "42", true},
+		{"42", "420", false},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"north-*", "north-gate", true},
+		{"north-*", "north-", true},
+		{"north-*", "south-gate", false},
+		{"*-gate", "north-gate", true},
+		{"n*h-*e", "north-gate", true},
+		{"n*h-*e", "north-gates", false},
+		{"a*a", "a", false},
+		{"a**b", "ab", true},
+	}
+	for _, c := range cases {
+		scope := Scope{Type: "area", Pattern: c.pattern}
+		require.Equal(t, c.matches, scope.Matches(Resource{Type: "area", ID: c.id}), "%s ~ %s", c.pattern, c.id)
+	}
+
+	require.False(t, Scope{Type: "room", Pattern: "*"}.Matches(Resource{Type: "area", ID: "42"}))
+	require.True(t, Scope{}.Matches(Resource{Type: "area", ID: "42"}))
+}
+
+func TestGrantScopedUserPermission(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	name := PermissionReviewCharacterApplications.Name
+	north := Scope{Type: "area", Pattern: "north-*"}
+	room := Scope{Type: "room", Pattern: "42"}
+
+	_, err = ps.GrantScopedUserPermission(ctx, uid, root, PermissionGrantAll.Name, room, time.Time{})
+	require.ErrorIs(t, err, ErrInvalidScope)
+	_, err = ps.GrantScopedUserPermission(ctx, uid, root, name, Scope{Type: "room"}, time.Time{})
+	require.ErrorIs(t, err, ErrInvalidScope)
+
+	id, err := ps.GrantScopedUserPermission(ctx, uid, root, name, north, time.Time{})
+	require.NoError(t, err)
+	require.NotZero(t, id)
+	// Granting the same permission in the same scope again does nothing, but other scopes are separate grants.
+	id, err = ps.GrantScopedUserPermission(ctx, uid, root, name, north, time.Time{})
+	require.NoError(t, err)
+	require.Zero(t, id)
+	_, err = ps.GrantScopedUserPermission(ctx, uid, root, name, room, time.Time{})
+	require.NoError(t, err)
+
+	allowed, err := ps.CheckUserPermission(ctx, uid, name, Resource{Type: "area", ID: "north-gate"})
+	require.NoError(t, err)
+	require.True(t, allowed)
+	allowed, err = ps.CheckUserPermission(ctx, uid, name, Resource{Type: "area", ID: "south-gate"})
+	require.NoError(t, err)
+	require.False(t, allowed)
+	allowed, err = ps.CheckUserPermission(ctx, uid, name, Resource{Type: "room", ID: "42"})
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	permissions, err := ps.EffectivePermissions(ctx, uid)
+	require.NoError(t, err)
+	require.False(t, permissions.Has(name))
+	require.Empty(t, permissions.List())
+	require.Equal(t, []string{name}, permissions.Granted())
+	require.Len(t, permissions.Sources(name), 2)
+
+	// Scoped grants don't show on the profile as if they were held everywhere.
+	profile, err := ps.Profile(ctx, uid)
+	require.NoError(t, err)
+	require.Empty(t, profile.Permissions)
+
+	t.Run("RevokeOneScope", func(t *testing.T) {
+		_, err := ps.RevokeScopedUserPermission(ctx, uid, root, name, room)
+		require.NoError(t, err)
+
+		allowed, err := ps.CheckUserPermission(ctx, uid, name, Resource{Type: "room", ID: "42"})
+		require.NoError(t, err)
+		require.False(t, allowed)
+		allowed, err = ps.CheckUserPermission(ctx, uid, name, Resource{Type: "area", ID: "north-gate"})
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		revocations, err := ps.query.ListUserPermissionRevocations(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, revocations, 1)
+		require.Equal(t, room.String(), revocations[0].Scope)
+	})
+
+	t.Run("GrantEverywhere", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(ctx, uid, root, name)
+		require.NoError(t, err)
+
+		allowed, err := ps.CheckUserPermission(ctx, uid, name, Resource{Type: "room", ID: "7"})
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		// Revoking it everywhere leaves the scoped grant.
+		_, err = ps.RevokeUserPermission(ctx, uid, root, name)
+		require.NoError(t, err)
+		allowed, err = ps.CheckUserPermission(ctx, uid, name, Resource{Type: "area", ID: "north-gate"})
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+}
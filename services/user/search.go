@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+const (
+	DefaultSearchUsersPageSize = 20
+	MaxSearchUsersPageSize     = 100
+	MaxSearchUsersQueryLength  = 256
+)
+
+var ErrInvalidSearchQuery = errors.New("search query must have at least one term and be at most 256 characters")
+
+type SearchUsersParams struct {
+	// Query is split on whitespace, and every term must prefix-match a username, display name or email address.
+	Query string
+	// PageSize defaults to DefaultSearchUsersPageSize and is capped at MaxSearchUsersPageSize.
+	PageSize  int
+	PageToken string
+}
+
+// SearchUsers ranks users by how well they match the query, weighting usernames over display names over email addresses.
+func (s *Service) SearchUsers(ctx context.Context, params SearchUsersParams) (*UsersPage, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.SearchUsers")
+	defer span.End()
+
+	if params.PageSize < 0 {
+		return nil, ErrInvalidPageSize
+	}
+	pageSize := params.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultSearchUsersPageSize
+	}
+	if pageSize > MaxSearchUsersPageSize {
+		pageSize = MaxSearchUsersPageSize
+	}
+
+	match, err := matchExpression(params.Query)
+	if err != nil {
+		return nil, err
+	}
+	filter := searchFilter(match)
+
+	var offset int64
+	if params.PageToken != "" {
+		after, err := decodePageToken(params.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if after.OrderBy != "rank" || after.Filter != filter || after.Offset <= 0 {
+			return nil, ErrInvalidPageToken
+		}
+		offset = after.Offset
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	// Fetch one extra user to find out whether there's another page.
+	users, err := qtx.SearchUsers(ctx, query.SearchUsersParams{
+		Match:  match,
+		Limit:  int64(pageSize) + 1,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	page := &UsersPage{Users: users}
+	if page.Users == nil {
+		page.Users = []query.User{}
+	}
+	if len(page.Users) > pageSize {
+		page.Users = page.Users[:pageSize]
+		page.NextPageToken = encodePageToken(pageToken{
+			OrderBy: "rank",
+			Filter:  filter,
+			Offset:  offset + int64(pageSize),
+		})
+	}
+	return page, nil
+}
+
+// matchExpression turns a user's query into an FTS5 expression. Each term is quoted so FTS5 syntax
+// in it is matched literally, and marked as a prefix so partial input matches as it's typed.
+func matchExpression(q string) (string, error) {
+	if utf8.RuneCountInString(q) > MaxSearchUsersQueryLength {
+		return "", ErrInvalidSearchQuery
+	}
+	terms := strings.Fields(q)
+	if len(terms) == 0 {
+		return "", ErrInvalidSearchQuery
+	}
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " "), nil
+}
+
+func searchFilter(match string) string {
+	sum := sha256.Sum256([]byte(match))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func searchUsernames(t *testing.T, ps Service, params SearchUsersParams) []string {
+	page, err := ps.SearchUsers(context.Background(), params)
+	require.NoError(t, err)
+	usernames := []string{}
+	for _, u := range page.Users {
+		usernames = append(usernames, u.Username)
+	}
+	return usernames
+}
+
+func TestSearchUsers(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM emails;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+
+	uids := map[string]int64{}
+	for _, username := range []string{"searchalpha", "searchbeta", "alphonse", "gamma"} {
+		uid, err := ps.Register(context.Background(), username, TestPassword)
+		require.NoError(t, err)
+		uids[username] = uid
+	}
+	_, err = db.Exec("INSERT INTO emails (address, uid, verified) VALUES (?, ?, false);", "alphaville@web.site", uids["gamma"])
+	require.NoError(t, err)
+
+	t.Run("Prefix", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"searchalpha", "searchbeta"}, searchUsernames(t, ps, SearchUsersParams{Query: "sea"}))
+		require.Equal(t, []string{"searchbeta"}, searchUsernames(t, ps, SearchUsersParams{Query: "searchb"}))
+	})
+
+	t.Run("RanksUsernamesOverEmails", func(t *testing.T) {
+		require.Equal(t, []string{"alphonse", "gamma"}, searchUsernames(t, ps, SearchUsersParams{Query: "alph"}))
+	})
+
+	t.Run("EveryTermMatches", func(t *testing.T) {
+		require.Equal(t, []string{"gamma"}, searchUsernames(t, ps, SearchUsersParams{Query: "gam web.site"}))
+		require.Empty(t, searchUsernames(t, ps, SearchUsersParams{Query: "gam nope"}))
+	})
+
+	t.Run("KeptInSync", func(t *testing.T) {
+		_, err := db.Exec("UPDATE users SET username = ? WHERE id = ?;", "deltaforce", uids["searchbeta"])
+		require.NoError(t, err)
+		require.Equal(t, []string{"deltaforce"}, searchUsernames(t, ps, SearchUsersParams{Query: "delta"}))
+		require.Equal(t, []string{"searchalpha"}, searchUsernames(t, ps, SearchUsersParams{Query: "sea"}))
+
+		_, err = db.Exec("DELETE FROM emails WHERE uid = ?;", uids["gamma"])
+		require.NoError(t, err)
+		require.Equal(t, []string{"alphonse"}, searchUsernames(t, ps, SearchUsersParams{Query: "alph"}))
+
+		_, err = db.Exec("DELETE FROM users WHERE id = ?;", uids["alphonse"])
+		require.NoError(t, err)
+		require.Empty(t, searchUsernames(t, ps, SearchUsersParams{Query: "alph"}))
+	})
+
+	t.Run("QuotesSyntax", func(t *testing.T) {
+		for _, q := range []string{`"`, `sea*`, `sea OR gamma`, `NEAR(sea`, `username:sea`, `-`} {
+			_, err := ps.SearchUsers(context.Background(), SearchUsersParams{Query: q})
+			require.NoError(t, err, q)
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		for _, username := range []string{"pagesearcha", "pagesearchb", "pagesearchc"} {
+			_, err := ps.Register(context.Background(), username, TestPassword)
+			require.NoError(t, err)
+		}
+
+		usernames := []string{}
+		params := SearchUsersParams{Query: "pagesearch", PageSize: 2}
+		for {
+			page, err := ps.SearchUsers(context.Background(), params)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page.Users), params.PageSize)
+			for _, u := range page.Users {
+				usernames = append(usernames, u.Username)
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			params.PageToken = page.NextPageToken
+		}
+		require.ElementsMatch(t, []string{"pagesearcha", "pagesearchb", "pagesearchc"}, usernames)
+
+		page, err := ps.SearchUsers(context.Background(), SearchUsersParams{Query: "pagesearch", PageSize: 2})
+		require.NoError(t, err)
+		_, err = ps.SearchUsers(context.Background(), SearchUsersParams{Query: "pagesearchb", PageToken: page.NextPageToken})
+		require.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		for _, q := range []string{"", "   ", string(make([]byte, MaxSearchUsersQueryLength+1))} {
+			_, err := ps.SearchUsers(context.Background(), SearchUsersParams{Query: q})
+			require.ErrorIs(t, err, ErrInvalidSearchQuery)
+		}
+	})
+}
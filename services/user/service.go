@@ -4,7 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/afteralec/grpc-user/db/query"
@@ -15,9 +15,14 @@ import (
 )
 
 type Service struct {
-	db     *sql.DB
-	query  *query.Queries
-	config *viper.Viper
+	db      *sql.DB
+	query   *query.Queries
+	config  *viper.Viper
+	logger  *slog.Logger
+	metrics *metrics
+	// usernameRules are the reserved names and blocked words from files, before any added at runtime.
+	usernameRules username.Rules
+	settingsHub   *settingsHub
 }
 
 func New(db *sql.DB, opts ...func(s *Service) error) (Service, error) {
@@ -25,7 +30,7 @@ func New(db *sql.DB, opts ...func(s *Service) error) (Service, error) {
 		return Service{}, errors.New("cannot instantiate without a database connection")
 	}
 	// TODO: Get sensible defaults for this config
-	service := Service{db: db, query: query.New(db), config: viper.New()}
+	service := Service{db: db, query: query.New(db), config: viper.New(), logger: slog.Default(), metrics: newMetrics(), settingsHub: newSettingsHub()}
 	for _, opt := range opts {
 		if err := opt(&service); err != nil {
 			return Service{}, err
@@ -34,10 +39,22 @@ func New(db *sql.DB, opts ...func(s *Service) error) (Service, error) {
 	if err := username.IsValid(service.config.GetString("root_username")); err != nil {
 		return Service{}, err
 	}
+	service.config.SetDefault("deletion.grace_period", DefaultDeletionGracePeriod)
+	if service.config.GetDuration("deletion.grace_period") < 0 {
+		return Service{}, errors.New("deletion.grace_period cannot be negative")
+	}
+	rules, err := loadUsernameRules(service.config)
+	if err != nil {
+		return Service{}, err
+	}
+	service.usernameRules = rules
 	return service, nil
 }
 
 func (s *Service) SyncRootPermissions(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "user.Service.SyncRootPermissions")
+	defer span.End()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -75,7 +92,7 @@ func (s *Service) SyncRootPermissions(ctx context.Context) error {
 		}
 		uid = u.ID
 	} else {
-		uid, err = s.Register(s.config.GetString("root_username"), s.config.GetString("root_passphrase"))
+		uid, err = s.Register(ctx, s.config.GetString("root_username"), s.config.GetString("root_passphrase"))
 		if err != nil {
 			return err
 		}
@@ -92,8 +109,16 @@ func (s *Service) SyncRootPermissions(ctx context.Context) error {
 	return nil
 }
 
-func (s *Service) Register(u, pass string) (int64, error) {
-	hash, err := passphrase.Hash(pass, passphrase.NewParams())
+func (s *Service) Register(ctx context.Context, u, pass string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Register")
+	defer span.End()
+
+	u, err := s.normalizeUsername(u)
+	if err != nil {
+		return 0, err
+	}
+
+	hash, err := s.hash(ctx, pass)
 	if err != nil {
 		return 0, err
 	}
@@ -105,9 +130,19 @@ func (s *Service) Register(u, pass string) (int64, error) {
 	defer tx.Rollback()
 	qtx := s.query.WithTx(tx)
 
-	r, err := qtx.CreateUser(context.Background(), query.CreateUserParams{
-		Username: u,
-		PwHash:   hash,
+	if u != s.config.GetString("root_username") {
+		if err := s.usernameAllowed(ctx, qtx, u); err != nil {
+			return 0, err
+		}
+	}
+	if err := usernameAvailable(ctx, qtx, 0, u, time.Now()); err != nil {
+		return 0, err
+	}
+
+	r, err := qtx.CreateUser(ctx, query.CreateUserParams{
+		Username:         u,
+		UsernameSkeleton: sql.NullString{String: username.Skeleton(u), Valid: true},
+		PwHash:           hash,
 	})
 	if err != nil {
 		return 0, err
@@ -123,20 +158,13 @@ func (s *Service) Register(u, pass string) (int64, error) {
 		return 0, err
 	}
 
-	if err := qtx.CreateUserSettings(context.Background(), query.CreateUserSettingsParams{
-		Theme: ThemeDefault,
-		UID:   uid,
-	}); err != nil {
-		return 0, err
-	}
-
 	if u == s.config.GetString("root_username") {
-		if err := revokeAllRootUserPermissions(context.Background(), qtx); err != nil {
-			log.Printf("revoke all root permissions err: %v", err)
+		if err := revokeAllRootUserPermissions(ctx, qtx); err != nil {
+			s.logger.ErrorContext(ctx, "revoke all root permissions", "err", err)
 			return 0, err
 		}
-		if err := grantRootUserPermissions(context.Background(), qtx, uid); err != nil {
-			log.Printf("grant all root permissions err: %v", err)
+		if err := grantRootUserPermissions(ctx, qtx, uid); err != nil {
+			s.logger.ErrorContext(ctx, "grant all root permissions", "uid", uid, "err", err)
 			return 0, err
 		}
 	}
@@ -144,6 +172,7 @@ func (s *Service) Register(u, pass string) (int64, error) {
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
+	s.metrics.registrations.Inc()
 
 	return uid, nil
 }
@@ -154,7 +183,10 @@ func (e *UnauthenticatedError) Error() string {
 	return "could not authenticate this username and password"
 }
 
-func (s *Service) Authenticate(u, pw string) (int64, error) {
+func (s *Service) Authenticate(ctx context.Context, u, pw string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Authenticate")
+	defer span.End()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return 0, err
@@ -162,11 +194,22 @@ func (s *Service) Authenticate(u, pw string) (int64, error) {
 	defer tx.Rollback()
 	qtx := s.query.WithTx(tx)
 
-	p, err := qtx.GetUserByUsername(context.Background(), u)
+	if normalized, err := s.normalizeUsername(u); err == nil {
+		u = normalized
+	}
+	p, err := qtx.GetUserByUsername(ctx, u)
 	if err != nil {
 		rand.Seed(uint64(time.Now().UnixNano()))
 		n := rand.Intn(2) + 2
 		time.Sleep(time.Duration(n) * time.Second)
+		if err == sql.ErrNoRows {
+			s.metrics.logins.WithLabelValues("failure").Inc()
+		}
+		return 0, err
+	}
+
+	status, err := accountStatus(ctx, qtx, p.ID, time.Now())
+	if err != nil {
 		return 0, err
 	}
 
@@ -174,117 +217,119 @@ func (s *Service) Authenticate(u, pw string) (int64, error) {
 		return 0, err
 	}
 
-	ok, err := passphrase.Verify(pw, p.PwHash)
+	ok, err := s.verify(ctx, pw, p.PwHash)
 	if err != nil {
 		return 0, err
 	}
 
 	if !ok {
+		s.metrics.logins.WithLabelValues("failure").Inc()
 		return 0, &UnauthenticatedError{}
 	}
+	// Only tell someone an account is inactive once they've proven it's theirs.
+	if !status.Active() {
+		s.metrics.logins.WithLabelValues("inactive").Inc()
+		return 0, &InactiveAccountError{Status: *status}
+	}
+	s.metrics.logins.WithLabelValues("success").Inc()
 
 	return p.ID, nil
 }
 
-func (s *Service) UserSettings(ctx context.Context, uid int64) (*query.UserSetting, error) {
+func (s *Service) UserPermissions(ctx context.Context, uid int64) ([]query.UserPermission, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UserPermissions")
+	defer span.End()
+
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, err
+		return []query.UserPermission{}, err
 	}
 	defer tx.Rollback()
 	qtx := s.query.WithTx(tx)
 
-	settings, err := qtx.GetUserSettings(ctx, uid)
+	permissions, err := userPermissions(ctx, qtx, uid)
 	if err != nil {
-		return nil, err
+		if err == sql.ErrNoRows {
+			return []query.UserPermission{}, nil
+		}
+		return []query.UserPermission{}, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, err
+		return []query.UserPermission{}, err
 	}
 
-	return &settings, nil
+	return permissions, nil
 }
 
-func (s *Service) SetUserSettingsTheme(ctx context.Context, uid int64, theme string) (*query.UserSetting, error) {
-	// TODO: Discrete error type
-	if theme != ThemeLight && theme != ThemeDark {
-		return nil, errors.New("invalid theme value")
-	}
+// EffectivePermissions returns everything a user is permitted to do, and where each permission comes from:
+// a direct grant, a role or a group. UserPermissions only returns direct grants.
+func (s *Service) EffectivePermissions(ctx context.Context, uid int64) (Permissions, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.EffectivePermissions")
+	defer span.End()
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, err
+		return Permissions{}, err
 	}
 	defer tx.Rollback()
 	qtx := s.query.WithTx(tx)
 
-	if err := qtx.UpdateUserSettingsTheme(ctx, query.UpdateUserSettingsThemeParams{
-		UID:   uid,
-		Theme: theme,
-	}); err != nil {
-		return nil, err
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return Permissions{}, ErrUserNotFound
+		}
+		return Permissions{}, err
 	}
-
-	settings, err := qtx.GetUserSettings(ctx, uid)
+	permissions, err := effectivePermissions(ctx, qtx, uid)
 	if err != nil {
-		return nil, err
+		return Permissions{}, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, err
+		return Permissions{}, err
 	}
 
-	return &settings, nil
+	return permissions, nil
 }
 
-func (s *Service) Users(ctx context.Context) ([]query.User, error) {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return []query.User{}, err
-	}
-	defer tx.Rollback()
-	qtx := s.query.WithTx(tx)
+// CheckUserPermission reports whether a user may use a permission on a resource, whether they hold it
+// everywhere or within a scope that matches the resource.
+func (s *Service) CheckUserPermission(ctx context.Context, uid int64, name string, resource Resource) (bool, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.CheckUserPermission")
+	defer span.End()
 
-	users, err := qtx.ListUsers(ctx)
+	permissions, err := s.EffectivePermissions(ctx, uid)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return []query.User{}, nil
-		}
-		return []query.User{}, err
+		return false, err
 	}
+	return permissions.Check(uid, name, resource), nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return []query.User{}, err
-	}
+func (s *Service) GrantUserPermission(ctx context.Context, uid, iuid int64, name string) (int64, error) {
+	return s.GrantUserPermissionUntil(ctx, uid, iuid, name, time.Time{})
+}
 
-	return users, nil
+// GrantUserPermissionUntil grants a permission that's revoked automatically once expiresAt has passed.
+// A zero expiresAt grants it until it's revoked. If the user already has the permission until another time,
+// its expiry is moved to expiresAt. If it's until the same time, nothing changes and the id returned is 0.
+func (s *Service) GrantUserPermissionUntil(ctx context.Context, uid, iuid int64, name string, expiresAt time.Time) (int64, error) {
+	return s.GrantScopedUserPermission(ctx, uid, iuid, name, Scope{}, expiresAt)
 }
 
-func (s *Service) UserPermissions(ctx context.Context, uid int64) ([]query.UserPermission, error) {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return []query.UserPermission{}, err
-	}
-	defer tx.Rollback()
-	qtx := s.query.WithTx(tx)
+// GrantScopedUserPermission grants a permission only within scope, like room:42, until expiresAt. A user
+// can hold the same permission within several scopes, and everywhere too. Root permissions can't be scoped.
+func (s *Service) GrantScopedUserPermission(ctx context.Context, uid, iuid int64, name string, scope Scope, expiresAt time.Time) (int64, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GrantScopedUserPermission")
+	defer span.End()
 
-	permissions, err := userPermissions(ctx, qtx, uid)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return []query.UserPermission{}, nil
-		}
-		return []query.UserPermission{}, err
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return 0, ErrInvalidPermissionExpiry
 	}
-
-	if err := tx.Commit(); err != nil {
-		return []query.UserPermission{}, err
+	if !scope.IsZero() && (!scope.valid() || IsRootPermission(name)) {
+		return 0, ErrInvalidScope
 	}
 
-	return permissions, nil
-}
-
-func (s *Service) GrantUserPermission(ctx context.Context, uid, iuid int64, name string) (int64, error) {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return 0, err
@@ -292,17 +337,16 @@ func (s *Service) GrantUserPermission(ctx context.Context, uid, iuid int64, name
 	defer tx.Rollback()
 	qtx := s.query.WithTx(tx)
 
-	issuerPermissionRecords, err := userPermissions(ctx, qtx, iuid)
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
 	if err != nil {
 		return 0, err
 	}
-	issuerPermissions := NewPermissions(iuid, issuerPermissionRecords)
 	if !issuerPermissions.CanGrant(name) {
 		// TODO: Create a discrete error here
 		return 0, errors.New("this issuer cannot grant this permission")
 	}
 
-	id, err := grantUserPermission(ctx, qtx, uid, iuid, name)
+	id, err := grantUserPermission(ctx, qtx, uid, iuid, name, scope, expiresAt)
 	if err != nil {
 		return 0, err
 	}
@@ -310,11 +354,23 @@ func (s *Service) GrantUserPermission(ctx context.Context, uid, iuid int64, name
 	if err = tx.Commit(); err != nil {
 		return 0, err
 	}
+	if id > 0 {
+		s.metrics.grants.WithLabelValues(name).Inc()
+	}
 
 	return id, nil
 }
 
 func (s *Service) RevokeUserPermission(ctx context.Context, uid, iuid int64, name string) (int64, error) {
+	return s.RevokeScopedUserPermission(ctx, uid, iuid, name, Scope{})
+}
+
+// RevokeScopedUserPermission revokes a permission granted within scope. It leaves any grants of the same
+// permission in other scopes, or everywhere, alone.
+func (s *Service) RevokeScopedUserPermission(ctx context.Context, uid, iuid int64, name string, scope Scope) (int64, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.RevokeScopedUserPermission")
+	defer span.End()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return 0, err
@@ -322,17 +378,16 @@ func (s *Service) RevokeUserPermission(ctx context.Context, uid, iuid int64, nam
 	defer tx.Rollback()
 	qtx := s.query.WithTx(tx)
 
-	issuerPermissionRecords, err := userPermissions(ctx, qtx, iuid)
+	issuerPermissions, err := effectivePermissions(ctx, qtx, iuid)
 	if err != nil {
 		return 0, err
 	}
-	issuerPermissions := NewPermissions(iuid, issuerPermissionRecords)
 	if !issuerPermissions.CanRevoke(name) {
 		// TODO: Create a discrete error here
 		return 0, errors.New("this issuer cannot revoke this permission")
 	}
 
-	id, err := revokeUserPermission(ctx, qtx, uid, iuid, name)
+	id, err := revokeUserPermission(ctx, qtx, uid, iuid, name, scope)
 	if err != nil {
 		return 0, err
 	}
@@ -340,6 +395,9 @@ func (s *Service) RevokeUserPermission(ctx context.Context, uid, iuid int64, nam
 	if err = tx.Commit(); err != nil {
 		return 0, err
 	}
+	if id > 0 {
+		s.metrics.revocations.WithLabelValues(name).Inc()
+	}
 
 	return id, nil
 }
@@ -354,3 +412,19 @@ func (s *Service) userExistsWithUsername(ctx context.Context, u string) (bool, e
 	}
 	return true, nil
 }
+
+func (s *Service) hash(ctx context.Context, pass string) (string, error) {
+	_, span := tracer.Start(ctx, "argon2.Hash")
+	defer span.End()
+	defer s.metrics.observeArgon2("hash", time.Now())
+
+	return passphrase.Hash(pass, passphrase.NewParams())
+}
+
+func (s *Service) verify(ctx context.Context, pass, encodedHash string) (bool, error) {
+	_, span := tracer.Start(ctx, "argon2.Verify")
+	defer span.End()
+	defer s.metrics.observeArgon2("verify", time.Now())
+
+	return passphrase.Verify(pass, encodedHash)
+}
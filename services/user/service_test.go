@@ -41,7 +41,7 @@ func TestSyncRootPermissions(t *testing.T) {
 		err = ps.SyncRootPermissions(context.Background())
 		require.NoError(t, err)
 
-		uid, err := ps.Authenticate(TestRootUsername, TestPassword)
+		uid, err := ps.Authenticate(context.Background(), TestRootUsername, TestPassword)
 		require.NoError(t, err)
 		require.Greater(t, uid, int64(0))
 	})
@@ -58,7 +58,7 @@ func TestSyncRootPermissions(t *testing.T) {
 		ps, err := New(db, WithConfig(config))
 		require.NoError(t, err)
 		ps.SyncRootPermissions(context.Background())
-		uid, err := ps.Authenticate(TestRootUsername, TestPassword)
+		uid, err := ps.Authenticate(context.Background(), TestRootUsername, TestPassword)
 		require.NoError(t, err)
 		require.Greater(t, uid, int64(0))
 
@@ -72,7 +72,7 @@ func TestSyncRootPermissions(t *testing.T) {
 		require.NoError(t, err)
 		require.Empty(t, records)
 
-		uid, err = ps.Authenticate(TestUsername, TestPassword)
+		uid, err = ps.Authenticate(context.Background(), TestUsername, TestPassword)
 		require.NoError(t, err)
 		require.Greater(t, uid, int64(0))
 		records, err = ps.UserPermissions(context.Background(), uid)
@@ -100,7 +100,7 @@ func TestRegister(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	uid, err := ps.Register(TestUsername, TestPassword)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
 	require.NoError(t, err)
 	require.NotEqual(t, 0, uid)
 }
@@ -121,7 +121,7 @@ func TestRegisterAsRoot(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	uid, err := ps.Register(TestRootUsername, "T3sted_tested")
+	uid, err := ps.Register(context.Background(), TestRootUsername, "T3sted_tested")
 	require.NoError(t, err)
 	require.NotEqual(t, 0, uid)
 
@@ -148,10 +148,10 @@ func TestAuthenticate(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	uid, err := ps.Register("testify", "T3sted_tested")
+	uid, err := ps.Register(context.Background(), "testify", "T3sted_tested")
 	require.NoError(t, err)
 
-	actual, err := ps.Authenticate("testify", "T3sted_tested")
+	actual, err := ps.Authenticate(context.Background(), "testify", "T3sted_tested")
 	require.NoError(t, err)
 	require.NotEqual(t, 0, uid)
 
@@ -176,18 +176,19 @@ func TestUsers(t *testing.T) {
 	err = ps.SyncRootPermissions(context.Background())
 	require.NoError(t, err)
 
-	users, err := ps.Users(context.Background())
+	page, err := ps.Users(context.Background(), UsersParams{})
 	require.NoError(t, err)
-	require.NotEmpty(t, users)
-	require.Equal(t, 1, len(users))
+	require.NotEmpty(t, page.Users)
+	require.Equal(t, 1, len(page.Users))
 
-	_, err = ps.Register(TestUsername, TestPassword)
+	_, err = ps.Register(context.Background(), TestUsername, TestPassword)
 	require.NoError(t, err)
 
-	users, err = ps.Users(context.Background())
+	page, err = ps.Users(context.Background(), UsersParams{})
 	require.NoError(t, err)
-	require.NotEmpty(t, users)
-	require.Equal(t, 2, len(users))
+	require.NotEmpty(t, page.Users)
+	require.Equal(t, 2, len(page.Users))
+	require.Empty(t, page.NextPageToken)
 }
 
 func TestUserSettings(t *testing.T) {
@@ -205,7 +206,7 @@ func TestUserSettings(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	uid, err := ps.Register("testify", "T3sted_tested")
+	uid, err := ps.Register(context.Background(), "testify", "T3sted_tested")
 	require.NoError(t, err)
 
 	settings, err := ps.UserSettings(context.Background(), uid)
@@ -228,7 +229,7 @@ func TestSetUserSettingsTheme(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	uid, err := ps.Register(TestUsername, TestPassword)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
 	require.NoError(t, err)
 
 	settings, err := ps.UserSettings(context.Background(), uid)
@@ -259,9 +260,9 @@ func TestGrantAndRevokeUserPermissions(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	iuid, err := ps.Register(TestRootUsername, TestPassword)
+	iuid, err := ps.Register(context.Background(), TestRootUsername, TestPassword)
 	require.NoError(t, err)
-	uid, err := ps.Register(TestUsername, TestPassword)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
 	require.NoError(t, err)
 	name := PermissionViewAllRooms.Name
 
@@ -302,9 +303,9 @@ func TestGrantAndRevokeUserPermissionReturnsEmptyIDForConflict(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	iuid, err := ps.Register(TestRootUsername, TestPassword)
+	iuid, err := ps.Register(context.Background(), TestRootUsername, TestPassword)
 	require.NoError(t, err)
-	uid, err := ps.Register(TestUsername, TestPassword)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
 	require.NoError(t, err)
 	name := PermissionViewAllRooms.Name
 
@@ -338,9 +339,9 @@ func TestRevokeUserPermissionReturnsEmptyIDForConflict(t *testing.T) {
 	ps, err := New(db, WithConfig(config))
 	require.NoError(t, err)
 
-	iuid, err := ps.Register(TestRootUsername, TestPassword)
+	iuid, err := ps.Register(context.Background(), TestRootUsername, TestPassword)
 	require.NoError(t, err)
-	uid, err := ps.Register(TestUsername, TestPassword)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
 	require.NoError(t, err)
 	name := PermissionViewAllRooms.Name
 
@@ -373,3 +374,48 @@ func TestRevokeUserPermissionReturnsEmptyIDForConflict(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int64(0), nextRevokedID)
 }
+
+func TestProfile(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM emails;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+
+	iuid, err := ps.Register(context.Background(), TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(context.Background(), TestUsername, TestPassword)
+	require.NoError(t, err)
+	_, err = ps.GrantUserPermission(context.Background(), uid, iuid, PermissionViewAllRooms.Name)
+	require.NoError(t, err)
+
+	profile, err := ps.Profile(context.Background(), uid)
+	require.NoError(t, err)
+	require.Equal(t, TestUsername, profile.User.Username)
+	require.Equal(t, ThemeDefault, profile.Settings[SettingTheme.Key])
+	require.Nil(t, profile.PrimaryEmail)
+	require.Equal(t, []string{PermissionViewAllRooms.Name}, profile.Permissions)
+
+	_, err = db.Exec("INSERT INTO emails (address, uid, verified) VALUES (?, ?, false), (?, ?, true);", "first@web.site", uid, "verified@web.site", uid)
+	require.NoError(t, err)
+
+	profile, err = ps.ProfileByUsername(context.Background(), TestUsername)
+	require.NoError(t, err)
+	require.Equal(t, uid, profile.User.ID)
+	require.NotNil(t, profile.PrimaryEmail)
+	require.Equal(t, "verified@web.site", profile.PrimaryEmail.Address)
+
+	_, err = ps.Profile(context.Background(), uid+100)
+	require.ErrorIs(t, err, ErrUserNotFound)
+	_, err = ps.ProfileByUsername(context.Background(), "nobody")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
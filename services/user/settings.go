@@ -0,0 +1,338 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+const (
+	SettingTypeString = "string"
+	SettingTypeBool   = "bool"
+	SettingTypeInt    = "int"
+)
+
+var (
+	ErrUnknownSetting = errors.New("no setting exists with this key")
+	// ErrInvalidSettingValue is wrapped by the errors Setting.Check returns for values that aren't valid.
+	ErrInvalidSettingValue = errors.New("this value isn't valid for this setting")
+)
+
+// InvalidSettingError is returned by UpdateSettings when a value isn't valid for its setting.
+type InvalidSettingError struct {
+	Key string
+	Err error
+}
+
+func (e *InvalidSettingError) Error() string {
+	return fmt.Sprintf("the value provided for %s isn't valid: %s", e.Key, e.Err)
+}
+
+func (e *InvalidSettingError) Unwrap() error {
+	return e.Err
+}
+
+// invalidSettingValueError reads as err, and also wraps ErrInvalidSettingValue.
+type invalidSettingValueError struct {
+	err error
+}
+
+// invalidSettingValue marks err as the reason a value isn't valid for its setting.
+func invalidSettingValue(err error) error {
+	return &invalidSettingValueError{err: err}
+}
+
+func (e *invalidSettingValueError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidSettingValueError) Unwrap() []error {
+	return []error{ErrInvalidSettingValue, e.err}
+}
+
+// Setting is a user setting in the registry. Values are stored as text, in the form
+// strconv formats them in for bool and int settings.
+type Setting struct {
+	Key  string
+	Type string
+	// Default is what users have until they change the setting.
+	Default string
+	// Validate checks a value that's already been parsed as Type. It can be nil.
+	Validate func(value string) error
+	// Check validates a value against stored data, in the transaction that sets it. Errors for values that
+	// aren't valid wrap ErrInvalidSettingValue; any others stop the update as they are. It can be nil.
+	Check func(ctx context.Context, qtx *query.Queries, value string) error
+}
+
+// Normalize parses a value as the setting's type and validates it, returning it in the form it's stored in.
+func (s Setting) Normalize(value string) (string, error) {
+	switch s.Type {
+	case SettingTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", err
+		}
+		value = strconv.FormatBool(b)
+	case SettingTypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		value = strconv.FormatInt(n, 10)
+	}
+	if s.Validate != nil {
+		if err := s.Validate(value); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+var SettingTheme Setting = Setting{
+	Key:      "theme",
+	Type:     SettingTypeString,
+	Default:  ThemeDefault,
+	Validate: IsValidThemeName,
+	Check:    themeExists,
+}
+
+// AllSettings is the settings registry. Adding a setting here is all it takes for users to have it.
+var AllSettings []Setting = []Setting{
+	SettingTheme,
+}
+
+var SettingsByKey = settingsByKey(AllSettings)
+
+func settingsByKey(settings []Setting) map[string]Setting {
+	byKey := make(map[string]Setting, len(settings))
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+	return byKey
+}
+
+// Settings are a user's value for every setting in the registry, keyed by Setting.Key, with defaults filled in.
+type Settings map[string]string
+
+// SettingValue is a user's value for a setting.
+type SettingValue struct {
+	Setting Setting
+	Value   string
+	// IsDefault is true when the user hasn't changed the setting.
+	IsDefault bool
+}
+
+// SettingUpdate changes a setting to Value, or back to its default if Reset is set.
+type SettingUpdate struct {
+	Key   string
+	Value string
+	Reset bool
+}
+
+// Settings returns the user's value for each of keys, or for every setting when there are none.
+func (s *Service) Settings(ctx context.Context, uid int64, keys ...string) ([]SettingValue, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Settings")
+	defer span.End()
+
+	settings, err := registeredSettings(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	values, err := settingValues(ctx, qtx, uid, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// UpdateSettings applies all of updates or, if any is invalid, none of them,
+// returning the new value of each setting updated.
+func (s *Service) UpdateSettings(ctx context.Context, uid int64, updates []SettingUpdate) ([]SettingValue, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UpdateSettings")
+	defer span.End()
+
+	settings := make([]Setting, 0, len(updates))
+	values := make([]string, 0, len(updates))
+	for _, update := range updates {
+		setting, ok := SettingsByKey[update.Key]
+		if !ok {
+			return nil, ErrUnknownSetting
+		}
+		value := ""
+		if !update.Reset {
+			normalized, err := setting.Normalize(update.Value)
+			if err != nil {
+				return nil, &InvalidSettingError{Key: update.Key, Err: err}
+			}
+			value = normalized
+		}
+		settings = append(settings, setting)
+		values = append(values, value)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	for i, update := range updates {
+		if update.Reset || settings[i].Check == nil {
+			continue
+		}
+		if err := settings[i].Check(ctx, qtx, values[i]); err != nil {
+			if errors.Is(err, ErrInvalidSettingValue) {
+				return nil, &InvalidSettingError{Key: update.Key, Err: err}
+			}
+			return nil, err
+		}
+	}
+
+	for i, update := range updates {
+		if update.Reset {
+			if err := qtx.DeleteUserSettingValue(ctx, query.DeleteUserSettingValueParams{
+				UID: uid,
+				Key: update.Key,
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := qtx.SetUserSettingValue(ctx, query.SetUserSettingValueParams{
+			Key:   update.Key,
+			Value: values[i],
+			UID:   uid,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := settingValues(ctx, qtx, uid, settings)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.settingsSnapshot(ctx, tx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.settingsHub.commit(tx)
+	if err != nil {
+		return nil, err
+	}
+	s.publishSettings(uid, version, snapshot)
+
+	return updated, nil
+}
+
+// UserSettings is the theme-only view of a user's settings, from before the settings registry.
+type UserSettings struct {
+	UID   int64
+	Theme string
+}
+
+// UserSettings returns the user's theme. It predates Settings, which returns every setting.
+func (s *Service) UserSettings(ctx context.Context, uid int64) (*UserSettings, error) {
+	values, err := s.Settings(ctx, uid, SettingTheme.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &UserSettings{UID: uid, Theme: values[0].Value}, nil
+}
+
+// SetUserSettingsTheme sets the user's theme. It predates UpdateSettings, which can change any setting.
+func (s *Service) SetUserSettingsTheme(ctx context.Context, uid int64, theme string) (*UserSettings, error) {
+	values, err := s.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Value: theme}})
+	if err != nil {
+		return nil, err
+	}
+	return &UserSettings{UID: uid, Theme: values[0].Value}, nil
+}
+
+func registeredSettings(keys []string) ([]Setting, error) {
+	if len(keys) == 0 {
+		return AllSettings, nil
+	}
+	settings := make([]Setting, 0, len(keys))
+	for _, key := range keys {
+		setting, ok := SettingsByKey[key]
+		if !ok {
+			return nil, ErrUnknownSetting
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// settingValues fills in defaults for settings the user hasn't changed. Stored values that are no longer
+// valid, such as a theme that's been removed, are treated as unchanged.
+func settingValues(ctx context.Context, qtx *query.Queries, uid int64, settings []Setting) ([]SettingValue, error) {
+	rows, err := qtx.ListUserSettingValues(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]string, len(rows))
+	for _, row := range rows {
+		stored[row.Key] = row.Value
+	}
+
+	values := make([]SettingValue, 0, len(settings))
+	for _, setting := range settings {
+		value, ok := stored[setting.Key]
+		if ok {
+			if _, err := setting.Normalize(value); err != nil {
+				ok = false
+			}
+		}
+		if !ok {
+			value = setting.Default
+		}
+		values = append(values, SettingValue{Setting: setting, Value: value, IsDefault: !ok})
+	}
+	return values, nil
+}
+
+// userSettings returns every setting's value for a user.
+func userSettings(ctx context.Context, qtx *query.Queries, uid int64) (Settings, error) {
+	values, err := settingValues(ctx, qtx, uid, AllSettings)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(Settings, len(values))
+	for _, value := range values {
+		settings[value.Setting.Key] = value.Value
+	}
+	return settings, nil
+}
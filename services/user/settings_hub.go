@@ -0,0 +1,149 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// settingsHub fans settings changes out to the watchers in this process. Each watcher holds at most
+// the latest snapshot of a user's settings, so a slow watcher skips to the newest one instead of
+// holding up whoever changed them.
+//
+// Every commit that changes settings is given a version, in commit order, and each watcher remembers
+// the version of the last snapshot sent to it. A snapshot published late, after a newer one, is dropped.
+type settingsHub struct {
+	// commits is held while settings are committed or read for a new watcher, so versions follow commit order.
+	commits sync.Mutex
+	version uint64
+
+	mu       sync.Mutex
+	watchers map[int64]map[chan []SettingValue]uint64
+}
+
+func newSettingsHub() *settingsHub {
+	return &settingsHub{watchers: map[int64]map[chan []SettingValue]uint64{}}
+}
+
+func (h *settingsHub) subscribe(uid int64) chan []SettingValue {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan []SettingValue, 1)
+	if h.watchers[uid] == nil {
+		h.watchers[uid] = map[chan []SettingValue]uint64{}
+	}
+	h.watchers[uid][ch] = 0
+	return ch
+}
+
+func (h *settingsHub) unsubscribe(uid int64, ch chan []SettingValue) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.watchers[uid], ch)
+	if len(h.watchers[uid]) == 0 {
+		delete(h.watchers, uid)
+	}
+	close(ch)
+}
+
+func (h *settingsHub) watched(uid int64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.watchers[uid]) > 0
+}
+
+// commit commits tx and returns the version of the settings it committed.
+func (h *settingsHub) commit(tx *sql.Tx) (uint64, error) {
+	h.commits.Lock()
+	defer h.commits.Unlock()
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	h.version++
+	return h.version, nil
+}
+
+// read calls fn with no settings commit in progress, and returns the version of the settings it saw.
+func (h *settingsHub) read(fn func() error) (uint64, error) {
+	h.commits.Lock()
+	defer h.commits.Unlock()
+
+	if err := fn(); err != nil {
+		return 0, err
+	}
+	return h.version, nil
+}
+
+// offer sends a new watcher the settings it started from, unless a newer snapshot was published first.
+func (h *settingsHub) offer(uid int64, ch chan []SettingValue, version uint64, values []SettingValue) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sent, ok := h.watchers[uid][ch]; ok && version >= sent {
+		h.send(uid, ch, version, values)
+	}
+}
+
+func (h *settingsHub) publish(uid int64, version uint64, values []SettingValue) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch, sent := range h.watchers[uid] {
+		if version > sent {
+			h.send(uid, ch, version, values)
+		}
+	}
+}
+
+// send replaces any snapshot the watcher hasn't read yet. The caller must hold h.mu.
+func (h *settingsHub) send(uid int64, ch chan []SettingValue, version uint64, values []SettingValue) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- values
+	h.watchers[uid][ch] = version
+}
+
+// WatchSettings sends the user's settings, then sends them again each time they change, until ctx is done.
+// The channel is closed once ctx is done. Only changes made through this process are seen.
+func (s *Service) WatchSettings(ctx context.Context, uid int64) (<-chan []SettingValue, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.WatchSettings")
+	defer span.End()
+
+	// Subscribing first means no change is missed between reading the settings and watching them.
+	ch := s.settingsHub.subscribe(uid)
+	var values []SettingValue
+	version, err := s.settingsHub.read(func() error {
+		var err error
+		values, err = s.Settings(ctx, uid)
+		return err
+	})
+	if err != nil {
+		s.settingsHub.unsubscribe(uid, ch)
+		return nil, err
+	}
+	s.settingsHub.offer(uid, ch, version, values)
+
+	go func() {
+		<-ctx.Done()
+		s.settingsHub.unsubscribe(uid, ch)
+	}()
+	return ch, nil
+}
+
+// publishSettings sends a user's settings to anyone watching them. Call it after the change is committed,
+// with a snapshot read in the same transaction and the version settingsHub.commit returned.
+func (s *Service) publishSettings(uid int64, version uint64, values []SettingValue) {
+	s.settingsHub.publish(uid, version, values)
+}
+
+// settingsSnapshot reads every setting for uid. It's always read, even with nobody watching,
+// since someone may start watching before the transaction commits.
+func (s *Service) settingsSnapshot(ctx context.Context, tx *sql.Tx, uid int64) ([]SettingValue, error) {
+	return settingValues(ctx, s.query.WithTx(tx), uid, AllSettings)
+}
@@ -0,0 +1,240 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+func TestSettingNormalize(t *testing.T) {
+	b := Setting{Key: "test_bool", Type: SettingTypeBool, Default: "false"}
+	value, err := b.Normalize("1")
+	require.NoError(t, err)
+	require.Equal(t, "true", value)
+	_, err = b.Normalize("yes")
+	require.Error(t, err)
+
+	n := Setting{Key: "test_int", Type: SettingTypeInt, Default: "0"}
+	value, err = n.Normalize("007")
+	require.NoError(t, err)
+	require.Equal(t, "7", value)
+	_, err = n.Normalize("7.5")
+	require.Error(t, err)
+
+	value, err = SettingTheme.Normalize(ThemeDark)
+	require.NoError(t, err)
+	require.Equal(t, ThemeDark, value)
+	_, err = SettingTheme.Normalize("Sepia Tone")
+	require.Error(t, err)
+}
+
+func TestSettings(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	t.Run("Defaults", func(t *testing.T) {
+		values, err := ps.Settings(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, len(AllSettings), len(values))
+		for i, value := range values {
+			require.Equal(t, AllSettings[i].Key, value.Setting.Key)
+			require.Equal(t, AllSettings[i].Default, value.Value)
+			require.True(t, value.IsDefault)
+		}
+
+		_, err = ps.Settings(ctx, uid, "nonexistent")
+		require.ErrorIs(t, err, ErrUnknownSetting)
+		_, err = ps.Settings(ctx, uid+100)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		values, err := ps.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Value: ThemeDark}})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(values))
+		require.Equal(t, ThemeDark, values[0].Value)
+		require.False(t, values[0].IsDefault)
+
+		values, err = ps.Settings(ctx, uid, SettingTheme.Key)
+		require.NoError(t, err)
+		require.Equal(t, ThemeDark, values[0].Value)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ps.UpdateSettings(ctx, uid, []SettingUpdate{
+			{Key: SettingTheme.Key, Value: ThemeLight},
+			{Key: "nonexistent", Value: "true"},
+		})
+		require.ErrorIs(t, err, ErrUnknownSetting)
+
+		_, err = ps.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Value: "sepia"}})
+		var settingErr *InvalidSettingError
+		require.True(t, errors.As(err, &settingErr))
+		require.Equal(t, SettingTheme.Key, settingErr.Key)
+
+		// Nothing is applied when any update is invalid.
+		values, err := ps.Settings(ctx, uid, SettingTheme.Key)
+		require.NoError(t, err)
+		require.Equal(t, ThemeDark, values[0].Value)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		values, err := ps.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Reset: true}})
+		require.NoError(t, err)
+		require.Equal(t, ThemeDefault, values[0].Value)
+		require.True(t, values[0].IsDefault)
+	})
+
+	t.Run("StoredValueNoLongerValid", func(t *testing.T) {
+		_, err := db.Exec("INSERT INTO user_setting_values (key, value, uid) VALUES (?, ?, ?);", SettingTheme.Key, "Sepia Tone", uid)
+		require.NoError(t, err)
+
+		values, err := ps.Settings(ctx, uid, SettingTheme.Key)
+		require.NoError(t, err)
+		require.Equal(t, ThemeDefault, values[0].Value)
+		require.True(t, values[0].IsDefault)
+	})
+}
+
+func TestWatchSettings(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM themes;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	_, err = ps.WatchSettings(ctx, uid+100)
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	updates, err := ps.WatchSettings(watchCtx, uid)
+	require.NoError(t, err)
+
+	values := <-updates
+	require.Equal(t, len(AllSettings), len(values))
+	require.Equal(t, ThemeDefault, values[0].Value)
+
+	_, err = ps.SetUserSettingsTheme(ctx, uid, ThemeLight)
+	require.NoError(t, err)
+	values = <-updates
+	require.Equal(t, ThemeLight, values[0].Value)
+
+	// A watcher that falls behind only gets the latest settings.
+	_, err = ps.SetUserSettingsTheme(ctx, uid, ThemeSystem)
+	require.NoError(t, err)
+	_, err = ps.SetUserSettingsTheme(ctx, uid, ThemeDark)
+	require.NoError(t, err)
+	values = <-updates
+	require.Equal(t, ThemeDark, values[0].Value)
+	require.Empty(t, updates)
+
+	_, err = ps.GrantUserPermission(ctx, root, root, PermissionManageThemes.Name)
+	require.NoError(t, err)
+	_, err = ps.CreateTheme(ctx, root, "solarized", map[string]string{"background": "#fdf6e3"})
+	require.NoError(t, err)
+	_, err = ps.SetUserSettingsTheme(ctx, uid, "solarized")
+	require.NoError(t, err)
+	<-updates
+	require.NoError(t, ps.DeleteTheme(ctx, root, "solarized"))
+	values = <-updates
+	require.Equal(t, ThemeDefault, values[0].Value)
+
+	t.Run("WatchedBeforeCommit", func(t *testing.T) {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer tx.Rollback()
+		err = ps.query.WithTx(tx).SetUserSettingValue(ctx, query.SetUserSettingValueParams{Key: SettingTheme.Key, Value: ThemeLight, UID: uid})
+		require.NoError(t, err)
+		snapshot, err := ps.settingsSnapshot(ctx, tx, uid)
+		require.NoError(t, err)
+
+		// The watcher starts after the snapshot was taken, but before the change is committed.
+		lateCtx, cancelLate := context.WithCancel(ctx)
+		late, err := ps.WatchSettings(lateCtx, uid)
+		require.NoError(t, err)
+		values := <-late
+		require.Equal(t, ThemeDefault, values[0].Value)
+
+		version, err := ps.settingsHub.commit(tx)
+		require.NoError(t, err)
+		ps.publishSettings(uid, version, snapshot)
+		values = <-late
+		require.Equal(t, ThemeLight, values[0].Value)
+		<-updates
+
+		cancelLate()
+		for range late {
+		}
+	})
+
+	cancel()
+	for range updates {
+	}
+	require.False(t, ps.settingsHub.watched(uid))
+}
+
+func TestSettingsHubDropsStaleSnapshots(t *testing.T) {
+	h := newSettingsHub()
+	theme := func(value string) []SettingValue {
+		return []SettingValue{{Setting: SettingTheme, Value: value}}
+	}
+
+	ch := h.subscribe(1)
+	h.offer(1, ch, 0, theme(ThemeDefault))
+	<-ch
+
+	// The update committed second is published first.
+	h.publish(1, 2, theme(ThemeDark))
+	h.publish(1, 1, theme(ThemeLight))
+	require.Equal(t, ThemeDark, (<-ch)[0].Value)
+	require.Empty(t, ch)
+
+	// A new watcher's starting settings don't replace a newer snapshot published while they were read.
+	late := h.subscribe(1)
+	h.publish(1, 4, theme(ThemeSystem))
+	h.offer(1, late, 3, theme(ThemeDark))
+	require.Equal(t, ThemeSystem, (<-late)[0].Value)
+	require.Equal(t, ThemeSystem, (<-ch)[0].Value)
+
+	h.unsubscribe(1, ch)
+	h.unsubscribe(1, late)
+	require.False(t, h.watched(1))
+}
@@ -0,0 +1,255 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
+)
+
+const (
+	StatusActive      = "active"
+	StatusSuspended   = "suspended"
+	StatusBanned      = "banned"
+	StatusDeactivated = "deactivated"
+)
+
+var Statuses = []string{StatusActive, StatusSuspended, StatusBanned, StatusDeactivated}
+
+var (
+	ErrInvalidStatus      = errors.New("status must be one of active, suspended, banned or deactivated")
+	ErrInvalidStatusEnd   = errors.New("a suspension or ban can only end in the future")
+	ErrCannotModerate     = errors.New("this issuer cannot change this user's status")
+	ErrCannotModerateRoot = errors.New("users with root permissions cannot be suspended or banned")
+)
+
+// AccountStatus is whether a user may sign in. Users without a stored status are active,
+// and so are users whose suspension or ban has ended.
+type AccountStatus struct {
+	Status string
+	Reason string
+	// Until is when a suspension or ban ends. It's zero for active users and indefinite bans.
+	Until time.Time
+	// IUID is the user who set this status, or 0 for active users.
+	IUID int64
+}
+
+func (s *AccountStatus) Active() bool {
+	return s.Status == StatusActive
+}
+
+// InactiveAccountError is returned by Authenticate when the passphrase is right but the account can't sign in.
+type InactiveAccountError struct {
+	Status AccountStatus
+}
+
+func (e *InactiveAccountError) Error() string {
+	if e.Status.Until.IsZero() {
+		return fmt.Sprintf("this account is %s", e.Status.Status)
+	}
+	return fmt.Sprintf("this account is %s until %s", e.Status.Status, e.Status.Until.UTC().Format(time.RFC3339))
+}
+
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) AccountStatus(ctx context.Context, uid int64) (*AccountStatus, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.AccountStatus")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	status, err := accountStatus(ctx, qtx, uid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// SuspendUser stops a user signing in until the given time.
+func (s *Service) SuspendUser(ctx context.Context, uid, iuid int64, reason string, until time.Time) (*AccountStatus, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.SuspendUser")
+	defer span.End()
+
+	if !until.After(time.Now()) {
+		return nil, ErrInvalidStatusEnd
+	}
+
+	return s.setAccountStatus(ctx, PermissionSuspendUser, AccountStatus{
+		Status: StatusSuspended,
+		Reason: reason,
+		Until:  until,
+		IUID:   iuid,
+	}, uid)
+}
+
+// BanUser stops a user signing in until the given time, or indefinitely if it's zero.
+func (s *Service) BanUser(ctx context.Context, uid, iuid int64, reason string, until time.Time) (*AccountStatus, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.BanUser")
+	defer span.End()
+
+	if !until.IsZero() && !until.After(time.Now()) {
+		return nil, ErrInvalidStatusEnd
+	}
+
+	return s.setAccountStatus(ctx, PermissionBanUser, AccountStatus{
+		Status: StatusBanned,
+		Reason: reason,
+		Until:  until,
+		IUID:   iuid,
+	}, uid)
+}
+
+// ReinstateUser lifts a user's suspension or ban.
+func (s *Service) ReinstateUser(ctx context.Context, uid, iuid int64, reason string) (*AccountStatus, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ReinstateUser")
+	defer span.End()
+
+	return s.setAccountStatus(ctx, PermissionReinstateUser, AccountStatus{
+		Status: StatusActive,
+		Reason: reason,
+		IUID:   iuid,
+	}, uid)
+}
+
+func (s *Service) setAccountStatus(ctx context.Context, permission Permission, status AccountStatus, uid int64) (*AccountStatus, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	issuerPermissions, err := effectivePermissions(ctx, qtx, status.IUID)
+	if err != nil {
+		return nil, err
+	}
+	if !issuerPermissions.Has(permission.Name) {
+		return nil, ErrCannotModerate
+	}
+
+	if _, err := qtx.GetUser(ctx, uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if !status.Active() {
+		permissions, err := effectivePermissions(ctx, qtx, uid)
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range RootPermissions {
+			if permissions.Has(root.Name) {
+				return nil, ErrCannotModerateRoot
+			}
+		}
+	}
+
+	current, err := accountStatus(ctx, qtx, uid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if status.Active() && current.Active() {
+		return current, nil
+	}
+
+	if err := setAccountStatus(ctx, qtx, uid, status); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if status.Active() {
+		return &AccountStatus{Status: StatusActive}, nil
+	}
+	s.metrics.lockouts.Inc()
+	return &status, nil
+}
+
+func accountStatus(ctx context.Context, qtx *query.Queries, uid int64, now time.Time) (*AccountStatus, error) {
+	row, err := qtx.GetUserStatus(ctx, uid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &AccountStatus{Status: StatusActive}, nil
+		}
+		return nil, err
+	}
+	if row.Until.Valid && row.Until.Int64 <= now.Unix() {
+		return &AccountStatus{Status: StatusActive}, nil
+	}
+
+	status := &AccountStatus{
+		Status: row.Status,
+		Reason: row.Reason,
+		IUID:   row.IUID,
+	}
+	if row.Until.Valid {
+		status.Until = time.Unix(row.Until.Int64, 0)
+	}
+	return status, nil
+}
+
+// setAccountStatus stores a user's status and records the change, along with the request that made it.
+func setAccountStatus(ctx context.Context, qtx *query.Queries, uid int64, status AccountStatus) error {
+	var until sql.NullInt64
+	if !status.Until.IsZero() {
+		until = sql.NullInt64{Int64: status.Until.Unix(), Valid: true}
+	}
+
+	if status.Active() {
+		if err := qtx.DeleteUserStatus(ctx, uid); err != nil {
+			return err
+		}
+	} else {
+		if err := qtx.SetUserStatus(ctx, query.SetUserStatusParams{
+			Status: status.Status,
+			Reason: status.Reason,
+			Until:  until,
+			IUID:   status.IUID,
+			UID:    uid,
+		}); err != nil {
+			return err
+		}
+	}
+
+	requestID, _ := requestid.FromContext(ctx)
+	return qtx.CreateUserStatusChange(ctx, query.CreateUserStatusChangeParams{
+		Status:    status.Status,
+		Reason:    status.Reason,
+		Until:     until,
+		RequestID: requestID,
+		IUID:      status.IUID,
+		UID:       uid,
+	})
+}
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestAccountStatus(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Exec("DELETE FROM user_status_changes;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	moderator, err := ps.Register(ctx, "testmoderator", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	inactive := func(t *testing.T) *InactiveAccountError {
+		_, err := ps.Authenticate(ctx, TestUsername, TestPassword)
+		var inactiveErr *InactiveAccountError
+		require.True(t, errors.As(err, &inactiveErr), err)
+		return inactiveErr
+	}
+	listed := func(t *testing.T, status string) []string {
+		return allUsernames(t, ps, UsersParams{PageSize: 10, Status: status})
+	}
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.SuspendUser(ctx, uid, moderator, "spam", time.Now().Add(time.Hour))
+		require.ErrorIs(t, err, ErrCannotModerate)
+
+		for _, permission := range []Permission{PermissionSuspendUser, PermissionBanUser, PermissionReinstateUser} {
+			_, err := ps.GrantUserPermission(ctx, moderator, root, permission.Name)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("Suspend", func(t *testing.T) {
+		_, err := ps.SuspendUser(ctx, uid, moderator, "spam", time.Now().Add(-time.Minute))
+		require.ErrorIs(t, err, ErrInvalidStatusEnd)
+
+		until := time.Now().Add(time.Hour).Truncate(time.Second)
+		status, err := ps.SuspendUser(ctx, uid, moderator, "spam", until)
+		require.NoError(t, err)
+		require.Equal(t, StatusSuspended, status.Status)
+
+		inactiveErr := inactive(t)
+		require.Equal(t, StatusSuspended, inactiveErr.Status.Status)
+		require.Equal(t, "spam", inactiveErr.Status.Reason)
+		require.True(t, until.Equal(inactiveErr.Status.Until))
+
+		_, err = ps.Authenticate(ctx, TestUsername, "Wr0ng_passphrase")
+		require.IsType(t, &UnauthenticatedError{}, err)
+
+		require.Equal(t, []string{TestUsername}, listed(t, StatusSuspended))
+		require.Equal(t, []string{TestRootUsername, "testmoderator"}, listed(t, StatusActive))
+	})
+
+	t.Run("SuspensionEnds", func(t *testing.T) {
+		_, err := db.Exec("UPDATE user_statuses SET until = ? WHERE uid = ?;", time.Now().Add(-time.Minute).Unix(), uid)
+		require.NoError(t, err)
+
+		authenticated, err := ps.Authenticate(ctx, TestUsername, TestPassword)
+		require.NoError(t, err)
+		require.Equal(t, uid, authenticated)
+
+		status, err := ps.AccountStatus(ctx, uid)
+		require.NoError(t, err)
+		require.True(t, status.Active())
+		require.Empty(t, listed(t, StatusSuspended))
+	})
+
+	t.Run("Ban", func(t *testing.T) {
+		_, err := ps.BanUser(ctx, uid, moderator, "abuse", time.Time{})
+		require.NoError(t, err)
+
+		inactiveErr := inactive(t)
+		require.Equal(t, StatusBanned, inactiveErr.Status.Status)
+		require.True(t, inactiveErr.Status.Until.IsZero())
+		require.Equal(t, moderator, inactiveErr.Status.IUID)
+		require.Equal(t, []string{TestUsername}, listed(t, StatusBanned))
+	})
+
+	t.Run("CannotBanRoot", func(t *testing.T) {
+		_, err := ps.BanUser(ctx, root, moderator, "coup", time.Time{})
+		require.ErrorIs(t, err, ErrCannotModerateRoot)
+	})
+
+	t.Run("Reinstate", func(t *testing.T) {
+		status, err := ps.ReinstateUser(ctx, uid, moderator, "appealed")
+		require.NoError(t, err)
+		require.True(t, status.Active())
+
+		_, err = ps.Authenticate(ctx, TestUsername, TestPassword)
+		require.NoError(t, err)
+
+		_, err = ps.ReinstateUser(ctx, uid, moderator, "again")
+		require.NoError(t, err)
+
+		var changes int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM user_status_changes WHERE uid = ?;", uid).Scan(&changes))
+		require.Equal(t, 3, changes)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := ps.BanUser(ctx, uid+100, moderator, "ghost", time.Time{})
+		require.ErrorIs(t, err, ErrUserNotFound)
+		_, err = ps.AccountStatus(ctx, uid+100)
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("InvalidStatusFilter", func(t *testing.T) {
+		_, err := ps.Users(ctx, UsersParams{Status: "asleep"})
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
@@ -1,35 +1,81 @@
 package user
 
-import "errors"
+import (
+	"errors"
+	"regexp"
+)
 
 const (
-	ThemeDark    = "dark"
-	ThemeLight   = "light"
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+	// ThemeSystem follows the client's OS, which picks between light and dark.
+	ThemeSystem  = "system"
 	ThemeDefault = ThemeDark
 )
 
+var (
+	ErrInvalidThemeName = errors.New("theme names must start with a lowercase letter, followed by up to 31 lowercase letters, digits or hyphens")
+	ErrInvalidPalette   = errors.New("a palette must have 1 to 64 colors, named like theme names, in hex like #1a2b3c")
+)
+
+var (
+	themeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+	colorPattern     = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+)
+
+// BuiltInThemes are always in the catalogue and can't be changed. The system theme has no palette.
+var BuiltInThemes = map[string]map[string]string{
+	ThemeDark: {
+		"background": "#121212",
+		"surface":    "#1e1e1e",
+		"text":       "#e0e0e0",
+		"accent":     "#8ab4f8",
+	},
+	ThemeLight: {
+		"background": "#ffffff",
+		"surface":    "#f5f5f5",
+		"text":       "#202124",
+		"accent":     "#1a73e8",
+	},
+	ThemeSystem: nil,
+}
+
+// IsTheme reports whether s is a built-in theme. Custom themes are in the catalogue; see Service.Themes.
 func IsTheme(s string) bool {
-	switch s {
-	case ThemeDark:
-		return true
-	case ThemeLight:
-		return true
-	default:
-		return false
+	_, ok := BuiltInThemes[s]
+	return ok
+}
+
+func IsValidThemeName(name string) error {
+	if !themeNamePattern.MatchString(name) {
+		return ErrInvalidThemeName
 	}
+	return nil
 }
 
-func OtherTheme(theme string) (string, error) {
-	if !IsTheme(theme) {
-		return "", errors.New("that isn't a theme")
+func IsValidPalette(palette map[string]string) error {
+	if len(palette) == 0 || len(palette) > 64 {
+		return ErrInvalidPalette
 	}
+	for name, color := range palette {
+		if !themeNamePattern.MatchString(name) || !colorPattern.MatchString(color) {
+			return ErrInvalidPalette
+		}
+	}
+	return nil
+}
 
+// OtherTheme switches between the light and dark themes. The system theme follows the client's OS,
+// so it has no other theme.
+func OtherTheme(theme string) (string, error) {
 	switch theme {
 	case ThemeDark:
 		return ThemeLight, nil
 	case ThemeLight:
 		return ThemeDark, nil
+	case ThemeSystem:
+		return "", errors.New("the system theme follows the OS, so it has no other theme")
 	default:
-		return ThemeDefault, nil
+		return "", errors.New("that isn't a theme")
 	}
 }
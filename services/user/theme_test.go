@@ -14,9 +14,10 @@ func TestIsTheme(t *testing.T) {
 		input    string
 		expected bool
 	}
-	testCases := [5]testCase{
+	testCases := [6]testCase{
 		{"light", ThemeLight, true},
 		{"dark", ThemeDark, true},
+		{"system", ThemeSystem, true},
 		{"default", ThemeDefault, true},
 		{"random", strings.Repeat("a", 17), false},
 		{"close", fmt.Sprintf("%s1234!", ThemeDefault), false},
@@ -29,3 +30,44 @@ func TestIsTheme(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidThemeName(t *testing.T) {
+	require.NoError(t, IsValidThemeName("solarized-dark"))
+	require.NoError(t, IsValidThemeName("a"))
+	require.ErrorIs(t, IsValidThemeName(""), ErrInvalidThemeName)
+	require.ErrorIs(t, IsValidThemeName("Solarized"), ErrInvalidThemeName)
+	require.ErrorIs(t, IsValidThemeName("-dark"), ErrInvalidThemeName)
+	require.ErrorIs(t, IsValidThemeName(strings.Repeat("a", 33)), ErrInvalidThemeName)
+}
+
+func TestIsValidPalette(t *testing.T) {
+	require.NoError(t, IsValidPalette(map[string]string{"background": "#fff", "text": "#1A2B3C", "overlay": "#00000080"}))
+	require.ErrorIs(t, IsValidPalette(nil), ErrInvalidPalette)
+	require.ErrorIs(t, IsValidPalette(map[string]string{"background": "white"}), ErrInvalidPalette)
+	require.ErrorIs(t, IsValidPalette(map[string]string{"Background": "#fff"}), ErrInvalidPalette)
+
+	large := map[string]string{}
+	for i := 0; i < 65; i++ {
+		large[fmt.Sprintf("color-%d", i)] = "#fff"
+	}
+	require.ErrorIs(t, IsValidPalette(large), ErrInvalidPalette)
+
+	for name, palette := range BuiltInThemes {
+		if palette != nil {
+			require.NoError(t, IsValidPalette(palette), name)
+		}
+	}
+}
+
+func TestOtherTheme(t *testing.T) {
+	theme, err := OtherTheme(ThemeDark)
+	require.NoError(t, err)
+	require.Equal(t, ThemeLight, theme)
+	theme, err = OtherTheme(ThemeLight)
+	require.NoError(t, err)
+	require.Equal(t, ThemeDark, theme)
+	_, err = OtherTheme(ThemeSystem)
+	require.Error(t, err)
+	_, err = OtherTheme("solarized")
+	require.Error(t, err)
+}
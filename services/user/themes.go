@@ -0,0 +1,262 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+var (
+	ErrThemeExists        = errors.New("a theme with this name already exists")
+	ErrThemeNotFound      = errors.New("no theme exists with this name")
+	ErrBuiltInTheme       = errors.New("built-in themes can't be changed or removed")
+	ErrCannotManageThemes = errors.New("this issuer cannot manage themes")
+)
+
+// Theme is a theme in the catalogue: one of BuiltInThemes, or a custom theme added at runtime.
+type Theme struct {
+	Name string
+	// Palette maps color names, like background, to hex colors. It's nil for the system theme.
+	Palette map[string]string
+	BuiltIn bool
+	// IUID, CreatedAt and UpdatedAt are only set for custom themes.
+	IUID      int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Themes lists the catalogue, built-in themes first, each sorted by name.
+func (s *Service) Themes(ctx context.Context) ([]Theme, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Themes")
+	defer span.End()
+
+	rows, err := s.query.ListThemes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	themes := make([]Theme, 0, len(BuiltInThemes)+len(rows))
+	for name, palette := range BuiltInThemes {
+		themes = append(themes, Theme{Name: name, Palette: palette, BuiltIn: true})
+	}
+	sort.Slice(themes, func(i, j int) bool { return themes[i].Name < themes[j].Name })
+	for _, row := range rows {
+		theme, err := customTheme(row)
+		if err != nil {
+			return nil, err
+		}
+		themes = append(themes, *theme)
+	}
+	return themes, nil
+}
+
+func (s *Service) CreateTheme(ctx context.Context, iuid int64, name string, palette map[string]string) (*Theme, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.CreateTheme")
+	defer span.End()
+
+	if err := IsValidThemeName(name); err != nil {
+		return nil, err
+	}
+	if err := IsValidPalette(palette); err != nil {
+		return nil, err
+	}
+	if IsTheme(name) {
+		return nil, ErrThemeExists
+	}
+	encoded, err := json.Marshal(palette)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canManageThemes(ctx, qtx, iuid); err != nil {
+		return nil, err
+	}
+
+	if _, err := qtx.GetTheme(ctx, name); err == nil {
+		return nil, ErrThemeExists
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err := qtx.CreateTheme(ctx, query.CreateThemeParams{
+		Name:    name,
+		Palette: string(encoded),
+		IUID:    iuid,
+	}); err != nil {
+		return nil, err
+	}
+	row, err := qtx.GetTheme(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return customTheme(row)
+}
+
+// UpdateTheme replaces a custom theme's palette.
+func (s *Service) UpdateTheme(ctx context.Context, iuid int64, name string, palette map[string]string) (*Theme, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UpdateTheme")
+	defer span.End()
+
+	if IsTheme(name) {
+		return nil, ErrBuiltInTheme
+	}
+	if err := IsValidPalette(palette); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(palette)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canManageThemes(ctx, qtx, iuid); err != nil {
+		return nil, err
+	}
+
+	if _, err := qtx.GetTheme(ctx, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrThemeNotFound
+		}
+		return nil, err
+	}
+	if err := qtx.UpdateThemePalette(ctx, query.UpdateThemePaletteParams{
+		Palette: string(encoded),
+		IUID:    iuid,
+		Name:    name,
+	}); err != nil {
+		return nil, err
+	}
+	row, err := qtx.GetTheme(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return customTheme(row)
+}
+
+// DeleteTheme removes a custom theme. Users who had picked it go back to the default theme.
+func (s *Service) DeleteTheme(ctx context.Context, iuid int64, name string) error {
+	ctx, span := tracer.Start(ctx, "user.Service.DeleteTheme")
+	defer span.End()
+
+	if IsTheme(name) {
+		return ErrBuiltInTheme
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canManageThemes(ctx, qtx, iuid); err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetTheme(ctx, name); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrThemeNotFound
+		}
+		return err
+	}
+	uids, err := qtx.ListUserSettingValueUIDs(ctx, query.ListUserSettingValueUIDsParams{
+		Key:   SettingTheme.Key,
+		Value: name,
+	})
+	if err != nil {
+		return err
+	}
+	if err := qtx.DeleteTheme(ctx, name); err != nil {
+		return err
+	}
+	if err := qtx.ResetUserSettingValues(ctx, query.ResetUserSettingValuesParams{
+		Key:   SettingTheme.Key,
+		Value: name,
+	}); err != nil {
+		return err
+	}
+	snapshots := make(map[int64][]SettingValue)
+	for _, uid := range uids {
+		snapshot, err := s.settingsSnapshot(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		snapshots[uid] = snapshot
+	}
+
+	version, err := s.settingsHub.commit(tx)
+	if err != nil {
+		return err
+	}
+	for uid, snapshot := range snapshots {
+		s.publishSettings(uid, version, snapshot)
+	}
+
+	return nil
+}
+
+// themeExists checks a theme name against the catalogue.
+func themeExists(ctx context.Context, qtx *query.Queries, name string) error {
+	if IsTheme(name) {
+		return nil
+	}
+	if _, err := qtx.GetTheme(ctx, name); err != nil {
+		if err == sql.ErrNoRows {
+			return invalidSettingValue(ErrThemeNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+func canManageThemes(ctx context.Context, qtx *query.Queries, iuid int64) error {
+	permissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return err
+	}
+	if !permissions.Has(PermissionManageThemes.Name) {
+		return ErrCannotManageThemes
+	}
+	return nil
+}
+
+func customTheme(row query.Theme) (*Theme, error) {
+	theme := &Theme{Name: row.Name, IUID: row.IUID}
+	if err := json.Unmarshal([]byte(row.Palette), &theme.Palette); err != nil {
+		return nil, err
+	}
+	if row.CreatedAt.Valid {
+		theme.CreatedAt = time.Unix(row.CreatedAt.Int64, 0)
+	}
+	if row.UpdatedAt.Valid {
+		theme.UpdatedAt = time.Unix(row.UpdatedAt.Int64, 0)
+	}
+	return theme, nil
+}
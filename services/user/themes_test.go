@@ -0,0 +1,117 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestThemes(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM themes;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	staff, err := ps.Register(ctx, "teststaff", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	palette := map[string]string{"background": "#fdf6e3", "text": "#657b83"}
+
+	t.Run("BuiltIn", func(t *testing.T) {
+		themes, err := ps.Themes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{ThemeDark, ThemeLight, ThemeSystem}, themeNames(themes))
+		require.True(t, themes[0].BuiltIn)
+		require.Nil(t, themes[2].Palette)
+
+		_, err = ps.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Value: ThemeSystem}})
+		require.NoError(t, err)
+	})
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.CreateTheme(ctx, staff, "solarized", palette)
+		require.ErrorIs(t, err, ErrCannotManageThemes)
+	})
+
+	_, err = ps.GrantUserPermission(ctx, staff, root, PermissionManageThemes.Name)
+	require.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := ps.CreateTheme(ctx, staff, "Solarized", palette)
+		require.ErrorIs(t, err, ErrInvalidThemeName)
+		_, err = ps.CreateTheme(ctx, staff, "solarized", map[string]string{"background": "beige"})
+		require.ErrorIs(t, err, ErrInvalidPalette)
+		_, err = ps.CreateTheme(ctx, staff, ThemeLight, palette)
+		require.ErrorIs(t, err, ErrThemeExists)
+		_, err = ps.UpdateTheme(ctx, staff, ThemeDark, palette)
+		require.ErrorIs(t, err, ErrBuiltInTheme)
+		require.ErrorIs(t, ps.DeleteTheme(ctx, staff, ThemeSystem), ErrBuiltInTheme)
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		_, err := ps.UpdateSettings(ctx, uid, []SettingUpdate{{Key: SettingTheme.Key, Value: "solarized"}})
+		var settingErr *InvalidSettingError
+		require.True(t, errors.As(err, &settingErr))
+		require.ErrorIs(t, err, ErrThemeNotFound)
+		require.ErrorIs(t, err, ErrInvalidSettingValue)
+
+		theme, err := ps.CreateTheme(ctx, staff, "solarized", palette)
+		require.NoError(t, err)
+		require.Equal(t, palette, theme.Palette)
+		require.Equal(t, staff, theme.IUID)
+		_, err = ps.CreateTheme(ctx, staff, "solarized", palette)
+		require.ErrorIs(t, err, ErrThemeExists)
+
+		settings, err := ps.SetUserSettingsTheme(ctx, uid, "solarized")
+		require.NoError(t, err)
+		require.Equal(t, "solarized", settings.Theme)
+
+		palette["accent"] = "#268bd2"
+		theme, err = ps.UpdateTheme(ctx, staff, "solarized", palette)
+		require.NoError(t, err)
+		require.Equal(t, "#268bd2", theme.Palette["accent"])
+
+		themes, err := ps.Themes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{ThemeDark, ThemeLight, ThemeSystem, "solarized"}, themeNames(themes))
+		require.False(t, themes[3].BuiltIn)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, ps.DeleteTheme(ctx, staff, "solarized"))
+		require.ErrorIs(t, ps.DeleteTheme(ctx, staff, "solarized"), ErrThemeNotFound)
+
+		settings, err := ps.UserSettings(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, ThemeDefault, settings.Theme)
+	})
+}
+
+func themeNames(themes []Theme) []string {
+	names := make([]string, 0, len(themes))
+	for _, theme := range themes {
+		names = append(names, theme.Name)
+	}
+	return names
+}
@@ -0,0 +1,5 @@
+package user
+
+import "go.opentelemetry.io/otel"
+
+var tracer = otel.Tracer("github.com/afteralec/grpc-user/services/user")
@@ -0,0 +1,211 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/requestid"
+	"github.com/afteralec/grpc-user/services/user/username"
+)
+
+var (
+	ErrInvalidUsername          = errors.New("the username provided isn't valid")
+	ErrUsernameUnchanged        = errors.New("this is already the user's username")
+	ErrUsernameTaken            = errors.New("this username is already taken")
+	ErrUsernameConfusable       = errors.New("this username looks too much like one that's already taken")
+	ErrUsernameReserved         = errors.New("this username is reserved")
+	ErrCannotChangeUsername     = errors.New("this issuer cannot change this user's username")
+	ErrCannotChangeRootUsername = errors.New("the root user's username cannot be changed")
+)
+
+// UsernameCooldownError is returned by ChangeUsername when users change their own username again too soon.
+type UsernameCooldownError struct {
+	NextChangeAfter time.Time
+}
+
+func (e *UsernameCooldownError) Error() string {
+	return fmt.Sprintf("this username can't be changed again until %s", e.NextChangeAfter.UTC().Format(time.RFC3339))
+}
+
+type UsernameChange struct {
+	UID         int64
+	IUID        int64
+	OldUsername string
+	NewUsername string
+	// ReservedUntil is when OldUsername can be claimed by someone other than UID.
+	ReservedUntil time.Time
+	// NextChangeAfter is when UID can next change their own username, or zero if they have never changed it themselves.
+	NextChangeAfter time.Time
+}
+
+// ChangeUsername renames a user. Users can change their own username once every username.change_cooldown;
+// changing anyone else's needs the change-username permission, and isn't held to the cooldown.
+// The old username stays reserved for the user for username.reservation_period, so it can't be claimed
+// to impersonate them in links and mentions.
+func (s *Service) ChangeUsername(ctx context.Context, uid, iuid int64, name string) (*UsernameChange, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ChangeUsername")
+	defer span.End()
+
+	name, err := s.normalizeUsername(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	u, err := qtx.GetUser(ctx, uid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	ok, err := selfOrPermitted(ctx, qtx, uid, iuid, PermissionChangeUsername)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCannotChangeUsername
+	}
+	rootUsername := s.config.GetString("root_username")
+	if u.Username == rootUsername {
+		return nil, ErrCannotChangeRootUsername
+	}
+
+	now := time.Now()
+	cooldown := s.config.GetDuration("username.change_cooldown")
+	// Only the user's own changes count towards the cooldown, so being renamed by someone else doesn't restart it.
+	latest, err := qtx.GetLatestOwnUsernameHistory(ctx, uid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	var nextChangeAfter time.Time
+	if err == nil && latest.CreatedAt.Valid {
+		nextChangeAfter = time.Unix(latest.CreatedAt.Int64, 0).Add(cooldown)
+	}
+	if uid == iuid && now.Before(nextChangeAfter) {
+		return nil, &UsernameCooldownError{NextChangeAfter: nextChangeAfter}
+	}
+
+	if name == u.Username {
+		return nil, ErrUsernameUnchanged
+	}
+	if name == rootUsername {
+		return nil, ErrUsernameReserved
+	}
+	if err := s.usernameAllowed(ctx, qtx, name); err != nil {
+		return nil, err
+	}
+	if err := usernameAvailable(ctx, qtx, uid, name, now); err != nil {
+		return nil, err
+	}
+
+	if err := qtx.UpdateUsername(ctx, query.UpdateUsernameParams{
+		Username:         name,
+		UsernameSkeleton: sql.NullString{String: username.Skeleton(name), Valid: true},
+		ID:               uid,
+	}); err != nil {
+		return nil, err
+	}
+	requestID, _ := requestid.FromContext(ctx)
+	reservedUntil := now.Add(s.config.GetDuration("username.reservation_period"))
+	if err := qtx.CreateUsernameHistory(ctx, query.CreateUsernameHistoryParams{
+		OldUsername:         u.Username,
+		OldUsernameSkeleton: username.Skeleton(u.Username),
+		NewUsername:         name,
+		ReservedUntil:       reservedUntil.Unix(),
+		RequestID:           requestID,
+		IUID:                iuid,
+		UID:                 uid,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if uid == iuid {
+		nextChangeAfter = time.Unix(now.Add(cooldown).Unix(), 0)
+	}
+
+	return &UsernameChange{
+		UID:             uid,
+		IUID:            iuid,
+		OldUsername:     u.Username,
+		NewUsername:     name,
+		ReservedUntil:   time.Unix(reservedUntil.Unix(), 0),
+		NextChangeAfter: nextChangeAfter,
+	}, nil
+}
+
+// IsValidUsername checks a username is valid for registering or changing to, without checking it's available.
+func (s *Service) IsValidUsername(name string) error {
+	_, err := s.normalizeUsername(name)
+	return err
+}
+
+// normalizeUsername checks a username is valid, returning it in the form it's stored in.
+// Unicode usernames are only allowed when username.unicode is set.
+func (s *Service) normalizeUsername(name string) (string, error) {
+	if !s.config.GetBool("username.unicode") {
+		if err := username.IsValid(name); err != nil {
+			return "", ErrInvalidUsername
+		}
+		return name, nil
+	}
+
+	normalized, err := username.Normalize(name)
+	if err != nil {
+		return "", ErrInvalidUsername
+	}
+	if err := username.IsValidUnicode(normalized); err != nil {
+		return "", ErrInvalidUsername
+	}
+	return normalized, nil
+}
+
+// usernameAvailable returns ErrUsernameTaken if anyone else has the username, ErrUsernameConfusable
+// if anyone else has one with the same skeleton, or ErrUsernameReserved if someone other than uid
+// gave up one with the same skeleton and it's still reserved for them. Pass 0 for a new user.
+// Skeletons are compared whether or not username.unicode is set, so "adm1n" can't be taken alongside "admln".
+func usernameAvailable(ctx context.Context, qtx *query.Queries, uid int64, name string, now time.Time) error {
+	if _, err := qtx.GetUserByUsername(ctx, name); err == nil {
+		return ErrUsernameTaken
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	skeleton := username.Skeleton(name)
+	if u, err := qtx.GetUserByUsernameSkeleton(ctx, sql.NullString{String: skeleton, Valid: true}); err == nil {
+		if u.ID != uid {
+			return ErrUsernameConfusable
+		}
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	reservation, err := qtx.GetUsernameReservation(ctx, query.GetUsernameReservationParams{
+		Skeleton: skeleton,
+		Now:      now.Unix(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	if reservation.UID != uid {
+		return ErrUsernameReserved
+	}
+	return nil
+}
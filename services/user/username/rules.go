@@ -0,0 +1,123 @@
+package username
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	ErrReserved = errors.New("this username is reserved")
+	ErrBlocked  = errors.New("this username contains a blocked word")
+)
+
+// DefaultReserved is used when no reserved-name file is configured.
+var DefaultReserved = []string{
+	"admin",
+	"administrator",
+	"help",
+	"moderator",
+	"official",
+	"root",
+	"security",
+	"staff",
+	"support",
+	"system",
+}
+
+// leetspeak maps digits and pairs of letters that stand in for a letter, once they've been through
+// Skeleton, to that letter.
+var leetspeak = strings.NewReplacer(
+	"l", "i",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"7", "t",
+	"8", "b",
+	"9", "g",
+	"vv", "w",
+)
+
+// fold is stricter than Skeleton, so "adm1n" matches "admin" and "8adw0rd" matches "badword".
+func fold(username string) string {
+	return leetspeak.Replace(Skeleton(strings.ToLower(username)))
+}
+
+func IsValidRule(pattern string) error {
+	if err := validate.Var(pattern, "required,max=32,alphanum,lowercase"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Rules holds the usernames nobody can take and the words no username can contain,
+// both compared after folding look-alike characters and leetspeak.
+type Rules struct {
+	reserved map[string]struct{}
+	blocked  []string
+}
+
+func NewRules(reserved, blocked []string) Rules {
+	return Rules{}.Add(reserved, blocked)
+}
+
+// Add returns a copy of the rules with more entries, leaving the original unchanged.
+func (r Rules) Add(reserved, blocked []string) Rules {
+	rules := Rules{reserved: make(map[string]struct{}, len(r.reserved)+len(reserved))}
+	for name := range r.reserved {
+		rules.reserved[name] = struct{}{}
+	}
+	for _, name := range reserved {
+		rules.reserved[fold(name)] = struct{}{}
+	}
+	rules.blocked = append(rules.blocked, r.blocked...)
+	for _, word := range blocked {
+		rules.blocked = append(rules.blocked, fold(word))
+	}
+	return rules
+}
+
+// Check returns ErrReserved if the username is reserved, or ErrBlocked if it contains a blocked word.
+func (r Rules) Check(username string) error {
+	folded := fold(username)
+	if _, ok := r.reserved[folded]; ok {
+		return ErrReserved
+	}
+	for _, word := range r.blocked {
+		if strings.Contains(folded, word) {
+			return ErrBlocked
+		}
+	}
+	return nil
+}
+
+// ReadList reads one entry per line, lowercased, skipping blank lines and lines starting with #.
+func ReadList(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := IsValidRule(line); err != nil {
+			return nil, err
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func ReadListFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadList(f)
+}
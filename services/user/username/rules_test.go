@@ -0,0 +1,49 @@
+package username
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesCheck(t *testing.T) {
+	rules := NewRules(DefaultReserved, []string{"badword"})
+	type testcase struct {
+		name     string
+		input    string
+		expected error
+	}
+	testcases := []testcase{
+		{"allowed", "tested", nil},
+		{"reserved", "admin", ErrReserved},
+		{"reserved confusable", "adm1n", ErrReserved},
+		{"reserved prefix", "adminfan", nil},
+		{"blocked", "badword", ErrBlocked},
+		{"blocked substring", "xbadwordx", ErrBlocked},
+		{"blocked confusable", "8adw0rd", ErrBlocked},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.ErrorIs(t, rules.Check(tc.input), tc.expected)
+		})
+	}
+}
+
+func TestRulesAdd(t *testing.T) {
+	rules := NewRules(nil, nil)
+	more := rules.Add([]string{"tested"}, nil)
+	require.NoError(t, rules.Check("tested"))
+	require.ErrorIs(t, more.Check("tested"), ErrReserved)
+}
+
+func TestReadList(t *testing.T) {
+	entries, err := ReadList(strings.NewReader("# reserved\n\nAdmin\n  staff  \n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"admin", "staff"}, entries)
+
+	_, err = ReadList(strings.NewReader("not valid\n"))
+	require.Error(t, err)
+}
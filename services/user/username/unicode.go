@@ -0,0 +1,101 @@
+package username
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/secure/precis"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidLength     = errors.New("usernames must be 4 to 16 characters long")
+	ErrInvalidCharacter  = errors.New("usernames can only contain letters and digits")
+	ErrNotNormalized     = errors.New("usernames must be normalized")
+	ErrInvalidNormalized = errors.New("this username can't be normalized")
+)
+
+// Normalize folds a Unicode username to the form it's stored and looked up in: NFKC, then the
+// PRECIS UsernameCaseMapped profile (RFC 8265), which maps wide characters to narrow ones,
+// lowercases and applies NFC.
+func Normalize(username string) (string, error) {
+	normalized, err := precis.UsernameCaseMapped.String(norm.NFKC.String(username))
+	if err != nil {
+		return "", ErrInvalidNormalized
+	}
+	return normalized, nil
+}
+
+// IsValidUnicode is the opt-in alternative to IsValid that allows letters and digits from any script.
+// The username must already be normalized.
+func IsValidUnicode(username string) error {
+	if n := utf8.RuneCountInString(username); n < 4 || n > 16 {
+		return ErrInvalidLength
+	}
+	for i, r := range username {
+		// Combining marks are allowed after a letter, for scripts that NFC can't compose.
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || (i > 0 && unicode.Is(unicode.M, r)) {
+			continue
+		}
+		return ErrInvalidCharacter
+	}
+	normalized, err := Normalize(username)
+	if err != nil {
+		return err
+	}
+	if normalized != username {
+		return ErrNotNormalized
+	}
+	return nil
+}
+
+// confusables maps characters to the lowercase Latin letters they're mistaken for. It's the part of
+// the Unicode confusables table (UTS #39) that covers digits and the Cyrillic and Greek letters
+// that look like Latin ones, along with m, which looks like rn.
+var confusables = map[rune]string{
+	'0': "o",
+	'1': "l",
+	'm': "rn",
+
+	'ı': "i",
+	'ɑ': "a",
+	'ɡ': "g",
+	'ο': "o",
+	'α': "a",
+	'γ': "y",
+	'ι': "i",
+	'ν': "v",
+	'ρ': "p",
+	'υ': "u",
+	'а': "a",
+	'с': "c",
+	'е': "e",
+	'һ': "h",
+	'і': "i",
+	'ј': "j",
+	'ӏ': "l",
+	'о': "o",
+	'р': "p",
+	'ѕ': "s",
+	'у': "y",
+	'х': "x",
+	'ԁ': "d",
+	'ԛ': "q",
+	'ԝ': "w",
+}
+
+// Skeleton folds characters that look alike, following UTS #39, so "аdmin" with a Cyrillic а
+// has the same skeleton as "admin". Usernames with the same skeleton can't both be taken.
+func Skeleton(username string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(username) {
+		if s, ok := confusables[r]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
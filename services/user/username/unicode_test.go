@@ -0,0 +1,66 @@
+package username
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	type testcase struct {
+		name     string
+		input    string
+		expected string
+	}
+	testcases := []testcase{
+		{"ascii", "tested", "tested"},
+		{"uppercase", "Tested", "tested"},
+		{"fullwidth", "ｔｅｓｔｅｄ", "tested"},
+		{"decomposed", "josé", "josé"},
+		{"ligature", "ﬁnder", "finder"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			normalized, err := Normalize(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, normalized)
+		})
+	}
+}
+
+func TestIsValidUnicode(t *testing.T) {
+	type testcase struct {
+		name     string
+		input    string
+		expected error
+	}
+	testcases := []testcase{
+		{"ascii", "tested", nil},
+		{"accented", "josé", nil},
+		{"cyrillic", "иван", nil},
+		{"japanese", "たなかさん", nil},
+		{"too short", "abc", ErrInvalidLength},
+		{"too long", "abcdefghijklmnopq", ErrInvalidLength},
+		{"space", "test ed", ErrInvalidCharacter},
+		{"punctuation", "test_ed", ErrInvalidCharacter},
+		{"leading mark", "\u0301test", ErrInvalidCharacter},
+		{"uppercase", "Tested", ErrNotNormalized},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.ErrorIs(t, IsValidUnicode(tc.input), tc.expected)
+		})
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	require.Equal(t, Skeleton("admin"), Skeleton("аdmin"))
+	require.Equal(t, Skeleton("paypal"), Skeleton("раyраl"))
+	require.Equal(t, Skeleton("modern"), Skeleton("rnodern"))
+	require.Equal(t, Skeleton("bob1"), Skeleton("bobl"))
+	require.NotEqual(t, Skeleton("tested"), Skeleton("testes"))
+}
@@ -0,0 +1,230 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+	"github.com/afteralec/grpc-user/services/user/username"
+	"github.com/spf13/viper"
+)
+
+const (
+	UsernameRuleReserved = "reserved"
+	UsernameRuleBlocked  = "blocked"
+)
+
+var (
+	ErrUsernameBlocked           = errors.New("this username contains a blocked word")
+	ErrInvalidUsernameRule       = errors.New("a username rule must be reserved or blocked, with a lowercase alphanumeric pattern")
+	ErrUsernameRuleExists        = errors.New("this username rule already exists")
+	ErrUsernameRuleNotFound      = errors.New("no username rule exists with this id")
+	ErrCannotManageUsernameRules = errors.New("this issuer cannot manage username rules")
+)
+
+// UsernameRule is a reserved username or blocked word added at runtime. Reserved usernames can't be
+// taken by anyone; blocked words can't appear anywhere in a username. Both are compared after folding
+// look-alike characters, so a rule for "admin" also covers "adm1n".
+type UsernameRule struct {
+	ID        int64
+	Kind      string
+	Pattern   string
+	Reason    string
+	IUID      int64
+	CreatedAt time.Time
+}
+
+// UsernameRules lists the rules added at runtime. Those loaded from files aren't included.
+func (s *Service) UsernameRules(ctx context.Context, iuid int64) ([]UsernameRule, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UsernameRules")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canManageUsernameRules(ctx, qtx, iuid); err != nil {
+		return nil, err
+	}
+
+	rows, err := qtx.ListUsernameRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	rules := make([]UsernameRule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, usernameRule(row))
+	}
+	return rules, nil
+}
+
+// AddUsernameRule reserves a username or blocks a word for new usernames. Existing users keep their usernames.
+func (s *Service) AddUsernameRule(ctx context.Context, iuid int64, kind, pattern, reason string) (*UsernameRule, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.AddUsernameRule")
+	defer span.End()
+
+	if kind != UsernameRuleReserved && kind != UsernameRuleBlocked {
+		return nil, ErrInvalidUsernameRule
+	}
+	if err := username.IsValidRule(pattern); err != nil {
+		return nil, ErrInvalidUsernameRule
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canManageUsernameRules(ctx, qtx, iuid); err != nil {
+		return nil, err
+	}
+
+	if _, err := qtx.GetUsernameRuleByPattern(ctx, query.GetUsernameRuleByPatternParams{
+		Kind:    kind,
+		Pattern: pattern,
+	}); err == nil {
+		return nil, ErrUsernameRuleExists
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	result, err := qtx.CreateUsernameRule(ctx, query.CreateUsernameRuleParams{
+		Kind:    kind,
+		Pattern: pattern,
+		Reason:  reason,
+		IUID:    iuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	row, err := qtx.GetUsernameRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	rule := usernameRule(row)
+	return &rule, nil
+}
+
+// RemoveUsernameRule deletes a rule added at runtime. Rules loaded from files can only be removed from their file.
+func (s *Service) RemoveUsernameRule(ctx context.Context, iuid, id int64) error {
+	ctx, span := tracer.Start(ctx, "user.Service.RemoveUsernameRule")
+	defer span.End()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	if err := canManageUsernameRules(ctx, qtx, iuid); err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetUsernameRule(ctx, id); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrUsernameRuleNotFound
+		}
+		return err
+	}
+	if err := qtx.DeleteUsernameRule(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// usernameAllowed checks a username against the rules from files and those added at runtime.
+func (s *Service) usernameAllowed(ctx context.Context, qtx *query.Queries, name string) error {
+	rows, err := qtx.ListUsernameRules(ctx)
+	if err != nil {
+		return err
+	}
+	var reserved, blocked []string
+	for _, row := range rows {
+		if row.Kind == UsernameRuleReserved {
+			reserved = append(reserved, row.Pattern)
+		} else {
+			blocked = append(blocked, row.Pattern)
+		}
+	}
+
+	switch err := s.usernameRules.Add(reserved, blocked).Check(name); {
+	case errors.Is(err, username.ErrReserved):
+		return ErrUsernameReserved
+	case errors.Is(err, username.ErrBlocked):
+		return ErrUsernameBlocked
+	default:
+		return err
+	}
+}
+
+// loadUsernameRules reads username.reserved_file and username.blocklist_file, falling back to
+// username.DefaultReserved when no reserved-name file is configured.
+func loadUsernameRules(config *viper.Viper) (username.Rules, error) {
+	reserved := username.DefaultReserved
+	if path := config.GetString("username.reserved_file"); path != "" {
+		entries, err := username.ReadListFile(path)
+		if err != nil {
+			return username.Rules{}, err
+		}
+		reserved = entries
+	}
+
+	var blocked []string
+	if path := config.GetString("username.blocklist_file"); path != "" {
+		entries, err := username.ReadListFile(path)
+		if err != nil {
+			return username.Rules{}, err
+		}
+		blocked = entries
+	}
+
+	return username.NewRules(reserved, blocked), nil
+}
+
+func canManageUsernameRules(ctx context.Context, qtx *query.Queries, iuid int64) error {
+	permissions, err := effectivePermissions(ctx, qtx, iuid)
+	if err != nil {
+		return err
+	}
+	if !permissions.Has(PermissionManageUsernameRules.Name) {
+		return ErrCannotManageUsernameRules
+	}
+	return nil
+}
+
+func usernameRule(row query.UsernameRule) UsernameRule {
+	rule := UsernameRule{
+		ID:      row.ID,
+		Kind:    row.Kind,
+		Pattern: row.Pattern,
+		Reason:  row.Reason,
+		IUID:    row.IUID,
+	}
+	if row.CreatedAt.Valid {
+		rule.CreatedAt = time.Unix(row.CreatedAt.Int64, 0)
+	}
+	return rule
+}
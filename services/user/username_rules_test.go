@@ -0,0 +1,99 @@
+package user
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestUsernameRules(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM username_rules;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	dir := t.TempDir()
+	reservedFile := filepath.Join(dir, "reserved.txt")
+	require.NoError(t, os.WriteFile(reservedFile, []byte("# staff accounts\nsupport\n"), 0o600))
+	blocklistFile := filepath.Join(dir, "blocklist.txt")
+	require.NoError(t, os.WriteFile(blocklistFile, []byte("badword\n"), 0o600))
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	config.Set("username.reserved_file", reservedFile)
+	config.Set("username.blocklist_file", blocklistFile)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	t.Run("Files", func(t *testing.T) {
+		_, err := ps.Register(ctx, "supp0rt", TestPassword)
+		require.ErrorIs(t, err, ErrUsernameReserved)
+		_, err = ps.Register(ctx, "mybadword", TestPassword)
+		require.ErrorIs(t, err, ErrUsernameBlocked)
+		_, err = ps.ChangeUsername(ctx, uid, uid, "support")
+		require.ErrorIs(t, err, ErrUsernameReserved)
+
+		// The reserved-name file replaces the built-in list.
+		admin, err := ps.Register(ctx, "admin", TestPassword)
+		require.NoError(t, err)
+		require.NotZero(t, admin)
+	})
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.UsernameRules(ctx, uid)
+		require.ErrorIs(t, err, ErrCannotManageUsernameRules)
+		_, err = ps.AddUsernameRule(ctx, uid, UsernameRuleBlocked, "nasty", "")
+		require.ErrorIs(t, err, ErrCannotManageUsernameRules)
+	})
+
+	t.Run("Runtime", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(ctx, root, root, PermissionManageUsernameRules.Name)
+		require.NoError(t, err)
+
+		_, err = ps.AddUsernameRule(ctx, root, "forbidden", "nasty", "")
+		require.ErrorIs(t, err, ErrInvalidUsernameRule)
+		_, err = ps.AddUsernameRule(ctx, root, UsernameRuleBlocked, "Not Valid", "")
+		require.ErrorIs(t, err, ErrInvalidUsernameRule)
+
+		blocked, err := ps.AddUsernameRule(ctx, root, UsernameRuleBlocked, "nasty", "slur")
+		require.NoError(t, err)
+		_, err = ps.AddUsernameRule(ctx, root, UsernameRuleBlocked, "nasty", "")
+		require.ErrorIs(t, err, ErrUsernameRuleExists)
+		_, err = ps.AddUsernameRule(ctx, root, UsernameRuleReserved, "moderators", "")
+		require.NoError(t, err)
+
+		rules, err := ps.UsernameRules(ctx, root)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		require.Equal(t, "nasty", rules[0].Pattern)
+		require.Equal(t, "slur", rules[0].Reason)
+
+		_, err = ps.Register(ctx, "n4styname", TestPassword)
+		require.ErrorIs(t, err, ErrUsernameBlocked)
+		_, err = ps.Register(ctx, "moderators", TestPassword)
+		require.ErrorIs(t, err, ErrUsernameReserved)
+
+		require.NoError(t, ps.RemoveUsernameRule(ctx, root, blocked.ID))
+		require.ErrorIs(t, ps.RemoveUsernameRule(ctx, root, blocked.ID), ErrUsernameRuleNotFound)
+		_, err = ps.Register(ctx, "n4styname", TestPassword)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func TestChangeUsername(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	config.Set("username.change_cooldown", time.Hour)
+	config.Set("username.reservation_period", time.Hour)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root, err := ps.Register(ctx, TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	staff, err := ps.Register(ctx, "teststaff", TestPassword)
+	require.NoError(t, err)
+	uid, err := ps.Register(ctx, TestUsername, TestPassword)
+	require.NoError(t, err)
+
+	t.Run("RequiresPermission", func(t *testing.T) {
+		_, err := ps.ChangeUsername(ctx, uid, staff, "testrenamed")
+		require.ErrorIs(t, err, ErrCannotChangeUsername)
+		_, err = ps.ChangeUsername(ctx, root, root, "testrenamed")
+		require.ErrorIs(t, err, ErrCannotChangeRootUsername)
+		_, err = ps.ChangeUsername(ctx, uid+100, uid+100, "testrenamed")
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ps.ChangeUsername(ctx, uid, uid, "Not Valid")
+		require.ErrorIs(t, err, ErrInvalidUsername)
+		_, err = ps.ChangeUsername(ctx, uid, uid, TestUsername)
+		require.ErrorIs(t, err, ErrUsernameUnchanged)
+		_, err = ps.ChangeUsername(ctx, uid, uid, "teststaff")
+		require.ErrorIs(t, err, ErrUsernameTaken)
+		_, err = ps.ChangeUsername(ctx, uid, uid, TestRootUsername)
+		require.ErrorIs(t, err, ErrUsernameReserved)
+	})
+
+	t.Run("ReservesOldUsername", func(t *testing.T) {
+		change, err := ps.ChangeUsername(ctx, uid, uid, "testrenamed")
+		require.NoError(t, err)
+		require.Equal(t, TestUsername, change.OldUsername)
+		require.WithinDuration(t, time.Now().Add(time.Hour), change.ReservedUntil, time.Minute)
+		require.WithinDuration(t, time.Now().Add(time.Hour), change.NextChangeAfter, time.Minute)
+
+		profile, err := ps.ProfileByUsername(ctx, "testrenamed")
+		require.NoError(t, err)
+		require.Equal(t, uid, profile.User.ID)
+		_, err = ps.Authenticate(ctx, TestUsername, TestPassword)
+		require.Error(t, err)
+
+		_, err = ps.Register(ctx, TestUsername, TestPassword)
+		require.ErrorIs(t, err, ErrUsernameReserved)
+		_, err = ps.ChangeUsername(ctx, staff, staff, TestUsername)
+		require.ErrorIs(t, err, ErrUsernameReserved)
+	})
+
+	t.Run("Cooldown", func(t *testing.T) {
+		_, err := ps.ChangeUsername(ctx, uid, uid, TestUsername)
+		var cooldownErr *UsernameCooldownError
+		require.True(t, errors.As(err, &cooldownErr))
+		require.WithinDuration(t, time.Now().Add(time.Hour), cooldownErr.NextChangeAfter, time.Minute)
+
+		_, err = ps.GrantUserPermission(ctx, staff, root, PermissionChangeUsername.Name)
+		require.NoError(t, err)
+		change, err := ps.ChangeUsername(ctx, uid, staff, TestUsername)
+		require.NoError(t, err)
+		require.Equal(t, staff, change.IUID)
+	})
+
+	t.Run("RenamedBySomeoneElse", func(t *testing.T) {
+		renamed, err := ps.Register(ctx, "testmodded", TestPassword)
+		require.NoError(t, err)
+		change, err := ps.ChangeUsername(ctx, renamed, staff, "testmodone")
+		require.NoError(t, err)
+		require.True(t, change.NextChangeAfter.IsZero())
+
+		change, err = ps.ChangeUsername(ctx, renamed, renamed, "testmodtwo")
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), change.NextChangeAfter, time.Minute)
+	})
+
+	t.Run("ExpiredReservation", func(t *testing.T) {
+		_, err := db.Exec("UPDATE username_history SET reserved_until = unixepoch('now') - 1;")
+		require.NoError(t, err)
+		_, err = ps.ChangeUsername(ctx, staff, staff, "testrenamed")
+		require.NoError(t, err)
+	})
+
+	data, err := ps.ExportUserData(ctx, uid, uid)
+	require.NoError(t, err)
+	require.Len(t, data.UsernameHistory, 2)
+}
+
+func TestUnicodeUsernames(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Close()
+	})
+
+	ctx := context.Background()
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ascii, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+
+	_, err = ascii.Register(ctx, "josé", TestPassword)
+	require.ErrorIs(t, err, ErrInvalidUsername)
+	uid, err := ascii.Register(ctx, "tested1", TestPassword)
+	require.NoError(t, err)
+	_, err = ascii.Register(ctx, "testedl", TestPassword)
+	require.ErrorIs(t, err, ErrUsernameConfusable)
+
+	config = viper.New()
+	config.Set("root_username", TestRootUsername)
+	config.Set("username.unicode", true)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+
+	jose, err := ps.Register(ctx, "Jose\u0301", TestPassword)
+	require.NoError(t, err)
+	profile, err := ps.ProfileByUsername(ctx, "JOS\u00c9")
+	require.NoError(t, err)
+	require.Equal(t, jose, profile.User.ID)
+	require.Equal(t, "jos\u00e9", profile.User.Username)
+	authenticated, err := ps.Authenticate(ctx, "Jos\u00e9", TestPassword)
+	require.NoError(t, err)
+	require.Equal(t, jose, authenticated)
+
+	_, err = ps.Register(ctx, "\u0430dmins", TestPassword)
+	require.NoError(t, err)
+	_, err = ps.Register(ctx, "admins", TestPassword)
+	require.ErrorIs(t, err, ErrUsernameConfusable)
+	_, err = ps.Register(ctx, "t\u0435sted1", TestPassword)
+	require.ErrorIs(t, err, ErrUsernameConfusable)
+	_, err = ps.Register(ctx, "test ed", TestPassword)
+	require.ErrorIs(t, err, ErrInvalidUsername)
+
+	// A user can take a name that only looks like their own.
+	_, err = ps.ChangeUsername(ctx, uid, uid, "testedl")
+	require.NoError(t, err)
+}
@@ -0,0 +1,259 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/afteralec/grpc-user/db/query"
+)
+
+const (
+	DefaultUsersPageSize = 50
+	MaxUsersPageSize     = 1000
+)
+
+var (
+	ErrInvalidPageSize  = errors.New("page size cannot be negative")
+	ErrInvalidPageToken = errors.New("the page token is invalid or doesn't match this request")
+	ErrInvalidOrderBy   = errors.New("users can only be ordered by id, username or created_at, optionally followed by desc")
+)
+
+// UsersParams filters, orders and pages through users. The zero value lists the first page of all users by id.
+type UsersParams struct {
+	// PageSize defaults to DefaultUsersPageSize and is capped at MaxUsersPageSize.
+	PageSize  int
+	PageToken string
+	// UsernamePrefix matches usernames starting with this prefix.
+	UsernamePrefix string
+	// Permission matches users who currently hold the permission with this name.
+	Permission string
+	// Status matches users whose account currently has this status.
+	Status string
+	// CreatedAfter matches users created after this time, unless it's zero.
+	CreatedAfter time.Time
+	// OrderBy is one of id, username or created_at, optionally followed by desc.
+	OrderBy string
+}
+
+type UsersPage struct {
+	Users []query.User
+	// NextPageToken is empty on the last page.
+	NextPageToken string
+}
+
+// pageToken is the position of the last user on a page, along with enough of the request
+// that produced it to reject tokens replayed against different filters.
+type pageToken struct {
+	OrderBy   string `json:"o"`
+	Filter    string `json:"f"`
+	ID        int64  `json:"i"`
+	Username  string `json:"u,omitempty"`
+	CreatedAt int64  `json:"c,omitempty"`
+	// Offset pages through results that are ranked rather than ordered by a column.
+	Offset int64 `json:"n,omitempty"`
+}
+
+func (s *Service) Users(ctx context.Context, params UsersParams) (*UsersPage, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.Users")
+	defer span.End()
+
+	if params.PageSize < 0 {
+		return nil, ErrInvalidPageSize
+	}
+	pageSize := params.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultUsersPageSize
+	}
+	if pageSize > MaxUsersPageSize {
+		pageSize = MaxUsersPageSize
+	}
+
+	orderBy, err := normalizeOrderBy(params.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	if params.Status != "" && !IsValidStatus(params.Status) {
+		return nil, ErrInvalidStatus
+	}
+	filter := usersFilter(params)
+
+	var after *pageToken
+	if params.PageToken != "" {
+		after, err = decodePageToken(params.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if after.OrderBy != orderBy || after.Filter != filter {
+			return nil, ErrInvalidPageToken
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.query.WithTx(tx)
+
+	// Fetch one extra user to find out whether there's another page.
+	users, err := listUsers(ctx, qtx, params, orderBy, after, int64(pageSize)+1)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &UsersPage{Users: []query.User{}}, nil
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	page := &UsersPage{Users: users}
+	if page.Users == nil {
+		page.Users = []query.User{}
+	}
+	if len(page.Users) > pageSize {
+		page.Users = page.Users[:pageSize]
+		last := page.Users[pageSize-1]
+		page.NextPageToken = encodePageToken(pageToken{
+			OrderBy:   orderBy,
+			Filter:    filter,
+			ID:        last.ID,
+			Username:  last.Username,
+			CreatedAt: last.CreatedAt.Int64,
+		})
+	}
+	return page, nil
+}
+
+func listUsers(ctx context.Context, qtx *query.Queries, params UsersParams, orderBy string, after *pageToken, limit int64) ([]query.User, error) {
+	var usernamePattern, permission, status sql.NullString
+	var createdAfter sql.NullInt64
+	if params.UsernamePrefix != "" {
+		usernamePattern = sql.NullString{String: escapeLike(params.UsernamePrefix) + "%", Valid: true}
+	}
+	if params.Permission != "" {
+		permission = sql.NullString{String: params.Permission, Valid: true}
+	}
+	if params.Status != "" {
+		status = sql.NullString{String: params.Status, Valid: true}
+	}
+	if !params.CreatedAfter.IsZero() {
+		createdAfter = sql.NullInt64{Int64: params.CreatedAfter.Unix(), Valid: true}
+	}
+
+	var afterID, afterCreatedAt sql.NullInt64
+	var afterUsername sql.NullString
+	if after != nil {
+		afterID = sql.NullInt64{Int64: after.ID, Valid: true}
+		afterUsername = sql.NullString{String: after.Username, Valid: true}
+		afterCreatedAt = sql.NullInt64{Int64: after.CreatedAt, Valid: true}
+	}
+
+	byID := query.ListUsersOrderByIDParams{
+		UsernamePattern: usernamePattern,
+		Permission:      permission,
+		Status:          status,
+		CreatedAfter:    createdAfter,
+		AfterID:         afterID,
+		Limit:           limit,
+	}
+	byUsername := query.ListUsersOrderByUsernameParams{
+		UsernamePattern: usernamePattern,
+		Permission:      permission,
+		Status:          status,
+		CreatedAfter:    createdAfter,
+		AfterUsername:   afterUsername,
+		AfterID:         afterID,
+		Limit:           limit,
+	}
+	byCreatedAt := query.ListUsersOrderByCreatedAtParams{
+		UsernamePattern: usernamePattern,
+		Permission:      permission,
+		Status:          status,
+		CreatedAfter:    createdAfter,
+		AfterCreatedAt:  afterCreatedAt,
+		AfterID:         afterID,
+		Limit:           limit,
+	}
+
+	switch orderBy {
+	case "id desc":
+		return qtx.ListUsersOrderByIDDesc(ctx, query.ListUsersOrderByIDDescParams(byID))
+	case "username":
+		return qtx.ListUsersOrderByUsername(ctx, byUsername)
+	case "username desc":
+		return qtx.ListUsersOrderByUsernameDesc(ctx, query.ListUsersOrderByUsernameDescParams(byUsername))
+	case "created_at":
+		return qtx.ListUsersOrderByCreatedAt(ctx, byCreatedAt)
+	case "created_at desc":
+		return qtx.ListUsersOrderByCreatedAtDesc(ctx, query.ListUsersOrderByCreatedAtDescParams(byCreatedAt))
+	default:
+		return qtx.ListUsersOrderByID(ctx, byID)
+	}
+}
+
+// normalizeOrderBy accepts an AIP-132 style order_by like "username desc" and returns its canonical form.
+func normalizeOrderBy(orderBy string) (string, error) {
+	fields := strings.Fields(strings.ToLower(orderBy))
+	if len(fields) == 0 {
+		return "id", nil
+	}
+	if len(fields) > 2 {
+		return "", ErrInvalidOrderBy
+	}
+	switch fields[0] {
+	case "id", "username", "created_at":
+	default:
+		return "", ErrInvalidOrderBy
+	}
+	if len(fields) == 1 || fields[1] == "asc" {
+		return fields[0], nil
+	}
+	if fields[1] != "desc" {
+		return "", ErrInvalidOrderBy
+	}
+	return fields[0] + " desc", nil
+}
+
+func usersFilter(params UsersParams) string {
+	var createdAfter int64
+	if !params.CreatedAfter.IsZero() {
+		createdAfter = params.CreatedAfter.Unix()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d", params.UsernamePrefix, params.Permission, params.Status, createdAfter)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodePageToken(token pageToken) string {
+	b, err := json.Marshal(token)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageToken(s string) (*pageToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var token pageToken
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &token, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s with the ! escape character the listing queries declare.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`).Replace(s)
+}
@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/afteralec/grpc-user/db"
+)
+
+func allUsernames(t *testing.T, ps Service, params UsersParams) []string {
+	usernames := []string{}
+	for {
+		page, err := ps.Users(context.Background(), params)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Users), params.PageSize)
+		for _, u := range page.Users {
+			usernames = append(usernames, u.Username)
+		}
+		if page.NextPageToken == "" {
+			return usernames
+		}
+		params.PageToken = page.NextPageToken
+	}
+}
+
+func TestUsersPagination(t *testing.T) {
+	db, err := db.Open("../../test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users;")
+		db.Exec("DELETE FROM user_permission_grants;")
+		db.Exec("DELETE FROM user_permission_revocations;")
+		db.Close()
+	})
+
+	config := viper.New()
+	config.Set("root_username", TestRootUsername)
+	ps, err := New(db, WithConfig(config))
+	require.NoError(t, err)
+
+	iuid, err := ps.Register(context.Background(), TestRootUsername, TestPassword)
+	require.NoError(t, err)
+	registered := []string{"testpagee", "testpagec", "testpagea", "testpaged", "testpageb"}
+	uids := map[string]int64{}
+	for i, username := range registered {
+		uid, err := ps.Register(context.Background(), username, TestPassword)
+		require.NoError(t, err)
+		uids[username] = uid
+		_, err = db.Exec("UPDATE users SET created_at = ? WHERE id = ?;", 1700000000+int64(i/2), uid)
+		require.NoError(t, err)
+	}
+	_, err = db.Exec("UPDATE users SET created_at = ? WHERE id = ?;", 1600000000, iuid)
+	require.NoError(t, err)
+
+	sorted := append([]string{}, registered...)
+	sort.Strings(sorted)
+	reversed := append([]string{}, sorted...)
+	sort.Sort(sort.Reverse(sort.StringSlice(reversed)))
+
+	t.Run("OrderBy", func(t *testing.T) {
+		tests := []struct {
+			orderBy  string
+			expected []string
+		}{
+			{"", append([]string{TestRootUsername}, registered...)},
+			{"id desc", append([]string{"testpageb", "testpaged", "testpagea", "testpagec", "testpagee"}, TestRootUsername)},
+			{"username", append([]string{TestRootUsername}, sorted...)},
+			{"username desc", append(reversed, TestRootUsername)},
+			{"created_at", append([]string{TestRootUsername}, registered...)},
+			{"created_at DESC", append([]string{"testpageb", "testpaged", "testpagea", "testpagec", "testpagee"}, TestRootUsername)},
+		}
+
+		for _, test := range tests {
+			require.Equal(t, test.expected, allUsernames(t, ps, UsersParams{PageSize: 2, OrderBy: test.orderBy}), test.orderBy)
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		_, err := ps.GrantUserPermission(context.Background(), uids["testpaged"], iuid, PermissionViewAllRooms.Name)
+		require.NoError(t, err)
+
+		require.Equal(t, sorted, allUsernames(t, ps, UsersParams{PageSize: 2, UsernamePrefix: "testpage", OrderBy: "username"}))
+		require.Equal(t, []string{"testpaged"}, allUsernames(t, ps, UsersParams{PageSize: 2, Permission: PermissionViewAllRooms.Name}))
+		require.Equal(t, []string{"testpageb"}, allUsernames(t, ps, UsersParams{PageSize: 2, CreatedAfter: time.Unix(1700000001, 0)}))
+		require.Empty(t, allUsernames(t, ps, UsersParams{PageSize: 2, UsernamePrefix: "testpage%"}))
+	})
+
+	t.Run("PageSize", func(t *testing.T) {
+		page, err := ps.Users(context.Background(), UsersParams{})
+		require.NoError(t, err)
+		require.Len(t, page.Users, len(registered)+1)
+		require.Empty(t, page.NextPageToken)
+
+		_, err = ps.Users(context.Background(), UsersParams{PageSize: -1})
+		require.ErrorIs(t, err, ErrInvalidPageSize)
+	})
+
+	t.Run("InvalidPageToken", func(t *testing.T) {
+		page, err := ps.Users(context.Background(), UsersParams{PageSize: 2, OrderBy: "username"})
+		require.NoError(t, err)
+		require.NotEmpty(t, page.NextPageToken)
+
+		_, err = ps.Users(context.Background(), UsersParams{PageSize: 2, OrderBy: "username desc", PageToken: page.NextPageToken})
+		require.ErrorIs(t, err, ErrInvalidPageToken)
+		_, err = ps.Users(context.Background(), UsersParams{PageSize: 2, OrderBy: "username", UsernamePrefix: "test", PageToken: page.NextPageToken})
+		require.ErrorIs(t, err, ErrInvalidPageToken)
+		_, err = ps.Users(context.Background(), UsersParams{PageToken: "not a token"})
+		require.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+
+	t.Run("InvalidOrderBy", func(t *testing.T) {
+		for _, orderBy := range []string{"pw_hash", "username sideways", "id desc username"} {
+			_, err := ps.Users(context.Background(), UsersParams{OrderBy: orderBy})
+			require.ErrorIs(t, err, ErrInvalidOrderBy, orderBy)
+		}
+	})
+}
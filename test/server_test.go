@@ -2,17 +2,23 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	pb "github.com/afteralec/grpc-user/proto"
 	"github.com/afteralec/grpc-user/services/user"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	tc "github.com/testcontainers/testcontainers-go/modules/compose"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
@@ -59,10 +65,74 @@ func TestServer(t *testing.T) {
 	port, err := server.MappedPort(ctx, "8009/tcp")
 	require.NoError(t, err)
 	addr := fmt.Sprintf("%s:%s", host, port.Port())
+	gatewayPort, err := server.MappedPort(ctx, "8010/tcp")
+	require.NoError(t, err)
+	gatewayURL := fmt.Sprintf("http://%s:%s", host, gatewayPort.Port())
 
 	client, closeClient := newClient(addr)
 	t.Cleanup(closeClient)
 
+	t.Run("Health Serving", func(t *testing.T) {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, conn.Close())
+		})
+
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			reply, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+				Service: pb.User_ServiceDesc.ServiceName,
+			})
+			assert.NoError(c, err)
+			assert.Equal(c, healthpb.HealthCheckResponse_SERVING, reply.GetStatus())
+		}, 10*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("Gateway Register And User Settings", func(t *testing.T) {
+		t.Parallel()
+		res, err := http.Post(gatewayURL+"/v1/users", "application/json", strings.NewReader(`{"username":"testgate","password":"T3sted_tested"}`))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var registerReply struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&registerReply))
+		require.NotEmpty(t, registerReply.ID)
+
+		res, err = http.Get(gatewayURL + "/v1/users/" + registerReply.ID + "/settings")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var settingsReply struct {
+			UID   string `json:"uid"`
+			Theme string `json:"theme"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&settingsReply))
+		require.Equal(t, registerReply.ID, settingsReply.UID)
+		require.Equal(t, user.ThemeDefault, settingsReply.Theme)
+	})
+
+	t.Run("Gateway Error Body", func(t *testing.T) {
+		t.Parallel()
+		res, err := http.Post(gatewayURL+"/v1/users", "application/json", strings.NewReader(`{"username":"testfieduntiltheendoftime","password":"T3sted_tested"}`))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		var body struct {
+			Code    int    `json:"code"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Equal(t, int(codes.InvalidArgument), body.Code)
+		require.Equal(t, "INVALID_ARGUMENT", body.Status)
+		require.NotEmpty(t, body.Message)
+	})
+
 	t.Run("Register Success", func(t *testing.T) {
 		t.Parallel()
 		reply, err := client.Register(ctx, &pb.RegisterRequest{
@@ -247,4 +317,35 @@ func TestServer(t *testing.T) {
 		require.Equal(t, registerReply.Id, userSettingsReply.Uid)
 		require.Equal(t, userSettingsReply.Theme, user.ThemeLight)
 	})
+
+	t.Run("Get User Success", func(t *testing.T) {
+		t.Parallel()
+		registerReply, err := client.Register(ctx, &pb.RegisterRequest{
+			Username: "testgu",
+			Password: "T3sted_tested",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, registerReply)
+
+		profile, err := client.GetUser(ctx, &pb.GetUserRequest{Id: registerReply.Id})
+		require.NoError(t, err)
+		require.Equal(t, "testgu", profile.Username)
+		require.Equal(t, user.ThemeDefault, profile.Theme)
+
+		profile, err = client.GetUserByUsername(ctx, &pb.GetUserByUsernameRequest{Username: "testgu"})
+		require.NoError(t, err)
+		require.Equal(t, registerReply.Id, profile.Id)
+	})
+
+	t.Run("Get User Not Found", func(t *testing.T) {
+		t.Parallel()
+		_, err := client.GetUser(ctx, &pb.GetUserRequest{Id: 1 << 40})
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		res, err := http.Get(gatewayURL + "/v1/usernames/testnobody")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
 }